@@ -1,25 +1,110 @@
 package cmd
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"time"
 
-	"github.com/ashish0kumar/mufetch/pkg/config"
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/internal/tui"
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+	"github.com/ashish0kumar/mufetch/pkg/lastfm"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
 	"github.com/spf13/cobra"
 )
 
+var (
+	authUser    bool
+	authPort    int
+	authKeyring bool
+)
+
+// authProviders describes the settings `auth <provider>` asks for
+var authProviders = []tui.AuthProvider{
+	{Name: "spotify", Title: "Spotify", Help: "https://developer.spotify.com/dashboard", Fields: []tui.AuthField{
+		{Key: "client_id", Label: "Client ID"},
+		{Key: "client_secret", Label: "Client Secret", Secret: true},
+	}},
+	{Name: "jamendo", Title: "Jamendo", Help: "https://devportal.jamendo.com", Fields: []tui.AuthField{
+		{Key: "client_id", Label: "Client ID"},
+	}},
+	{Name: "fma", Title: "FMA", Help: "a mirror of the retired Free Music Archive API", Fields: []tui.AuthField{
+		{Key: "api_key", Label: "API key", Secret: true},
+		{Key: "api_url", Label: "API URL"},
+	}},
+	{Name: "lastfm", Title: "Last.fm", Help: "https://www.last.fm/api/account/create", Fields: []tui.AuthField{
+		{Key: "api_key", Label: "API key", Secret: true},
+	}},
+}
+
 // authCmd represents the authentication command for Spotify API
 var authCmd = &cobra.Command{
-	Use:   "auth",
-	Short: "Authenticate with Spotify API",
+	Use:   "auth [provider]",
+	Short: "Authenticate with Spotify API or another provider",
 	Long: `Set up your Spotify API credentials.
 
 You need to:
 1. Go to https://developer.spotify.com/dashboard
 2. Create a new app
-3. Copy your Client ID and Client Secret`,
+3. Copy your Client ID and Client Secret
+
+With --user, sign in to your Spotify account instead, so mufetch can read
+what you're playing, your library and your top items. Add the redirect URI
+it prints to your app's settings first.
+
+Name a provider (jamendo, fma or lastfm) to set up its key instead; each is
+saved in that provider's section of the config file. In a terminal this
+opens a form where you can pick the provider, paste and edit the keys, and
+test them before they're saved.
+
+With --keyring, move the client secret, refresh token and API keys out of
+the config file into the OS keyring (Keychain, Secret Service or Windows
+Credential Manager); later sign-ins save them there too.`,
+	Args: cobra.MaximumNArgs(1),
 
 	Run: func(cmd *cobra.Command, args []string) {
+		if authKeyring {
+			if err := config.UseKeyring(); err != nil {
+				fmt.Printf("Failed to switch to the keyring: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("Secrets are now stored in the OS keyring.")
+			return
+		}
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+			if authProviderByName(name) == nil {
+				fmt.Printf("Error: unknown provider %q (choose spotify, jamendo, fma or lastfm)\n", name)
+				os.Exit(1)
+			}
+		}
+		if authUser {
+			if name != "" && name != "spotify" {
+				fmt.Println("Error: --user is only supported for spotify")
+				os.Exit(1)
+			}
+			authorizeUser()
+			return
+		}
+		if platform.IsTerminal(os.Stdin) && platform.IsTerminal(os.Stdout) {
+			authWizard(name)
+			return
+		}
+		if name != "" && name != "spotify" {
+			authProvider(name)
+			return
+		}
+
 		fmt.Println("Spotify API Authentication Setup")
 		fmt.Println()
 		fmt.Println("To get your Spotify API credentials:")
@@ -55,10 +140,227 @@ You need to:
 
 		fmt.Println("Credentials saved successfully!")
 		fmt.Println("You can now use 'mufetch search <query>' to search for music.")
+		suggestKeyring()
 	},
 }
 
+// authProviderByName finds a provider in authProviders
+func authProviderByName(name string) *tui.AuthProvider {
+	for i := range authProviders {
+		if authProviders[i].Name == name {
+			return &authProviders[i]
+		}
+	}
+	return nil
+}
+
+// authProvider prompts for a provider's settings, one line each, and saves
+// them in its config section
+func authProvider(name string) {
+	p := authProviderByName(name)
+	fmt.Printf("Get your %s credentials at: %s\n", p.Title, p.Help)
+
+	settings := make(map[string]string)
+	for _, f := range p.Fields {
+		var value string
+		if f.Optional {
+			fmt.Printf("Enter your %s %s (blank for the default): ", p.Title, f.Label)
+		} else {
+			fmt.Printf("Enter your %s %s: ", p.Title, f.Label)
+		}
+		fmt.Scanln(&value)
+		if value == "" {
+			if !f.Optional {
+				fmt.Printf("Error: %s %s is required!\n", p.Title, f.Label)
+				os.Exit(1)
+			}
+			continue
+		}
+		settings[f.Key] = value
+	}
+	saveProvider(name, settings)
+}
+
+// authWizard runs the auth form, starting on the named provider if any
+func authWizard(name string) {
+	loadConfig()
+
+	// Show what's set up already, so the form edits it
+	current := map[string]map[string]string{
+		"spotify": {"client_id": cfg.Spotify.ClientID, "client_secret": cfg.Spotify.ClientSecret},
+		"jamendo": {"client_id": cfg.Jamendo.ClientID},
+		"fma":     {"api_key": cfg.FMA.APIKey, "api_url": cfg.FMA.APIURL},
+		"lastfm":  {"api_key": cfg.LastFM.APIKey},
+	}
+	providers := make([]tui.AuthProvider, len(authProviders))
+	for i, p := range authProviders {
+		p.Fields = append([]tui.AuthField(nil), p.Fields...)
+		for j := range p.Fields {
+			p.Fields[j].Value = current[p.Name][p.Fields[j].Key]
+		}
+		providers[i] = p
+	}
+
+	name, settings, err := tui.RunAuth(ctx, providers, name, testCredentials)
+	if errors.Is(err, tui.ErrCanceled) {
+		fmt.Println("Nothing saved.")
+		return
+	}
+	if err != nil {
+		fmt.Printf("Auth form failed: %v\n", err)
+		os.Exit(1)
+	}
+	saveProvider(name, settings)
+	if name == "spotify" {
+		fmt.Println("You can now use 'mufetch search <query>' to search for music.")
+	}
+}
+
+// testCredentials makes a request with settings from the auth form
+func testCredentials(ctx context.Context, name string, settings map[string]string) error {
+	var err error
+	switch name {
+	case "spotify":
+		return spotify.NewClient(settings["client_id"], settings["client_secret"]).Authenticate(ctx)
+	case "lastfm":
+		_, err = lastfm.NewClient(settings["api_key"]).SimilarArtists(ctx, "Radiohead", 1)
+	case "jamendo":
+		_, err = provider.NewJamendo(settings["client_id"]).SearchArtist(ctx, "Kevin MacLeod")
+	case "fma":
+		_, err = provider.NewFMA(settings["api_key"], settings["api_url"]).SearchArtist(ctx, "Chad Crouch")
+	}
+	if errors.Is(err, provider.ErrNotFound) {
+		return nil // The key worked
+	}
+
+	// Keys sent in the query string would show up in the message
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, perr := url.Parse(urlErr.URL); perr == nil {
+			urlErr.URL = httpclient.Redact(u)
+		}
+	}
+	return err
+}
+
+// saveProvider saves settings in a provider's config section and says where
+func saveProvider(name string, settings map[string]string) {
+	if err := config.SetProvider(name, settings); err != nil {
+		fmt.Printf("Failed to save credentials: %v\n", err)
+		os.Exit(1)
+	}
+	if p := config.Profile(); p != "" {
+		fmt.Printf("Saved to the %s section of the %s profile.\n", name, p)
+	} else {
+		fmt.Printf("Saved to the %s section of the config.\n", name)
+	}
+	suggestKeyring()
+}
+
+// suggestKeyring points at --keyring after secrets were saved in plaintext,
+// if the OS keyring is there to use
+func suggestKeyring() {
+	if config.UsesKeyring() || config.KeyringAvailable() != nil {
+		return
+	}
+	fmt.Println("Tip: run 'mufetch auth --keyring' to keep secrets in the OS keyring instead of the config file.")
+}
+
+// authorizeUser signs the user in with the authorization code flow and
+// PKCE: the browser is sent to Spotify's consent page, which redirects back
+// to a server on localhost with a code that's exchanged for tokens
+func authorizeUser() {
+	loadConfig()
+	if cfg.Spotify.ClientID == "" {
+		fmt.Println("No Spotify Client ID found, run 'mufetch auth' first")
+		os.Exit(exitUnauthorized)
+	}
+
+	// Spotify only allows plain http redirects to loopback addresses
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", authPort)
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", authPort))
+	if err != nil {
+		fmt.Printf("Failed to listen for the Spotify redirect: %v\n", err)
+		os.Exit(1)
+	}
+
+	verifier, err := spotify.NewVerifier()
+	if err != nil {
+		fmt.Printf("Failed to create a PKCE verifier: %v\n", err)
+		os.Exit(1)
+	}
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		fmt.Printf("Failed to create a state token: %v\n", err)
+		os.Exit(1)
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	codes := make(chan string, 1)
+	failures := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("state") != state:
+			http.Error(w, "State mismatch, please try again.", http.StatusBadRequest)
+			return
+		case query.Get("error") != "":
+			fmt.Fprintln(w, "Authorization was denied. You can close this tab.")
+			select {
+			case failures <- fmt.Errorf("authorization denied: %s", query.Get("error")):
+			default:
+			}
+			return
+		}
+		fmt.Fprintln(w, "mufetch is signed in. You can close this tab.")
+		select {
+		case codes <- query.Get("code"):
+		default: // Already have a code, e.g. from a reload
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	sp := &spotify.Client{ClientID: cfg.Spotify.ClientID}
+	authURL := sp.AuthorizeURL(redirectURI, spotify.Challenge(verifier), state)
+	fmt.Printf("Make sure %s is a redirect URI in your app's settings at\nhttps://developer.spotify.com/dashboard, then sign in at:\n\n%s\n\n", redirectURI, authURL)
+	if err := platform.OpenURL(authURL); err != nil {
+		fmt.Println("Couldn't open the browser, open the link above manually.")
+	}
+	fmt.Println("Waiting for Spotify...")
+
+	var code string
+	select {
+	case code = <-codes:
+	case err := <-failures:
+		fmt.Println(err)
+		os.Exit(exitUnauthorized)
+	case <-time.After(5 * time.Minute):
+		fmt.Println("Timed out waiting for the Spotify sign-in")
+		os.Exit(exitUnauthorized)
+	}
+
+	token, err := sp.ExchangeCode(ctx, code, redirectURI, verifier)
+	if err != nil {
+		fmt.Printf("Failed to sign in: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	if err := config.SetRefreshToken(token.RefreshToken); err != nil {
+		fmt.Printf("Failed to save the refresh token: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Signed in! Commands like 'mufetch now' and 'mufetch top' now use your account.")
+	suggestKeyring()
+}
+
 // init adds the auth command to the root command
 func init() {
+	authCmd.Flags().BoolVar(&authUser, "user", false, "Sign in to your Spotify account for personal endpoints")
+	authCmd.Flags().IntVar(&authPort, "port", 8888, "Local port for the sign-in redirect with --user")
+	authCmd.Flags().BoolVar(&authKeyring, "keyring", false, "Move secrets from the config file into the OS keyring")
+
 	rootCmd.AddCommand(authCmd)
 }
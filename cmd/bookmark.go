@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/bookmark"
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+// bookmarkType is the search or ID type saved with a bookmark
+var bookmarkType string
+
+// bookmarkCmd manages named shortcuts to lookups
+var bookmarkCmd = &cobra.Command{
+	Use:   "bookmark",
+	Short: "Save lookups under a name to recall with 'mufetch <name>'",
+	Long: `Save a search query or a Spotify ID, URI or link under a name, then show it
+with 'mufetch <name>'. Flags after the name are passed on, e.g.
+'mufetch fav --json'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listBookmarks()
+	},
+}
+
+// bookmarkAddCmd saves a bookmark
+var bookmarkAddCmd = &cobra.Command{
+	Use:   `add <name> "<query|spotify-id|uri|url>"`,
+	Short: "Save a bookmark, replacing any with the same name",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, target := args[0], strings.TrimSpace(args[1])
+		if strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+			fmt.Println("Bookmark names can't start with - or contain spaces")
+			os.Exit(1)
+		}
+		if c, _, err := rootCmd.Find([]string{name}); err == nil && c != rootCmd {
+			fmt.Printf("%s is a mufetch command, pick another name\n", name)
+			os.Exit(1)
+		}
+		if !isOneOf(bookmarkType, []string{"auto", "track", "album", "artist"}) {
+			fmt.Printf("Unknown type: %s\n", bookmarkType)
+			os.Exit(1)
+		}
+
+		b := bookmark.Bookmark{Name: name, Target: target}
+		if bookmarkType != "auto" {
+			b.Type = bookmarkType
+		}
+		if err := bookmarkStore().Set(b); err != nil {
+			fmt.Printf("Failed to save bookmark: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved %s, show it with 'mufetch %s'\n", name, name)
+	},
+}
+
+// bookmarkRemoveCmd deletes a bookmark
+var bookmarkRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a bookmark",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := bookmarkStore().Remove(args[0])
+		if err != nil {
+			fmt.Printf("Failed to update bookmarks: %v\n", err)
+			os.Exit(1)
+		}
+		if !removed {
+			fmt.Printf("No bookmark named %s\n", args[0])
+			os.Exit(exitNotFound)
+		}
+		fmt.Printf("Removed %s\n", args[0])
+	},
+}
+
+// bookmarkListCmd lists bookmarks
+var bookmarkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bookmarks",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listBookmarks()
+	},
+}
+
+// listBookmarks prints each bookmark with what it looks up
+func listBookmarks() {
+	bookmarks, err := bookmarkStore().Load()
+	if err != nil {
+		fmt.Printf("Failed to read bookmarks: %v\n", err)
+		os.Exit(1)
+	}
+	if len(bookmarks) == 0 {
+		fmt.Println(`No bookmarks yet, add one with 'mufetch bookmark add <name> "<query or link>"'`)
+		return
+	}
+
+	width := 0
+	for _, b := range bookmarks {
+		width = max(width, runewidth.StringWidth(b.Name))
+	}
+	for _, b := range bookmarks {
+		target := b.Target
+		if b.Type != "" {
+			target += " (" + b.Type + ")"
+		}
+		fmt.Printf(" %s  %s\n", runewidth.FillRight(b.Name, width), target)
+	}
+}
+
+// bookmarkStore opens the bookmarks in the config directory
+func bookmarkStore() *bookmark.Store {
+	dir, err := config.Dir()
+	if err != nil {
+		fmt.Printf("Failed to find the config directory: %v\n", err)
+		os.Exit(1)
+	}
+	return bookmark.NewStore(dir)
+}
+
+// expandBookmark turns `mufetch <name> [flags]` into the get or search
+// command the bookmark stands for. Other arguments are returned unchanged.
+func expandBookmark(args []string) []string {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return args
+	}
+	if c, _, err := rootCmd.Find(args[:1]); err == nil && c != rootCmd {
+		return args
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return args
+	}
+	b, err := bookmark.NewStore(dir).Get(args[0])
+	if err != nil || b == nil {
+		return args
+	}
+
+	var expanded []string
+	if _, _, err := spotify.ParseID(b.Target); err == nil {
+		expanded = []string{"get", b.Target}
+	} else {
+		expanded = []string{"search", b.Target}
+	}
+	if b.Type != "" {
+		expanded = append(expanded, "-t", b.Type)
+	}
+	return append(expanded, args[1:]...)
+}
+
+// init registers the bookmark commands
+func init() {
+	bookmarkAddCmd.Flags().StringVarP(&bookmarkType, "type", "t", "auto", "Search type, or the type of a bare Spotify ID: track, album, artist, or auto")
+
+	bookmarkCmd.AddCommand(bookmarkAddCmd, bookmarkRemoveCmd, bookmarkListCmd)
+	rootCmd.AddCommand(bookmarkCmd)
+}
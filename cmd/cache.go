@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+// variables for the cache commands
+var (
+	cacheKind      string
+	cacheOlderThan string
+	cacheMaxSize   string
+)
+
+// cacheCmd shows cache usage; its subcommands clear and prune it
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the on-disk caches",
+	Long: `Show how much space the response and image caches use. Use 'cache clear' to
+empty them and 'cache prune --older-than 7d' or 'cache prune --max-size 200M' to
+drop old entries.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showCacheStats()
+	},
+}
+
+// cacheStatsCmd shows cache usage
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the size of each cache",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showCacheStats()
+	},
+}
+
+// cacheClearCmd empties the caches
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete everything in the caches",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openCache()
+		files, bytes, err := c.Clear(cacheKinds()...)
+		if err != nil {
+			fmt.Printf("Failed to clear the cache: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %s (%s)\n", pluralFiles(files), formatBytes(bytes))
+	},
+}
+
+// cachePruneCmd drops old cache entries
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete cache entries older than a given age",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c := openCache()
+
+		// --max-size alone only trims by size
+		if cacheMaxSize != "" {
+			maxBytes, err := parseSize(cacheMaxSize)
+			if err != nil {
+				fmt.Printf("Invalid --max-size: %v\n", err)
+				os.Exit(1)
+			}
+			for _, kind := range cacheKinds() {
+				files, bytes, err := c.Trim(kind, maxBytes)
+				if err != nil {
+					fmt.Printf("Failed to prune the cache: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Printf("Removed %s (%s) to fit %s in %s\n", pluralFiles(files), formatBytes(bytes), kind, cacheMaxSize)
+			}
+			if !cmd.Flags().Changed("older-than") {
+				return
+			}
+		}
+
+		age, err := parseAge(cacheOlderThan)
+		if err != nil {
+			fmt.Printf("Invalid --older-than: %v\n", err)
+			os.Exit(1)
+		}
+
+		files, bytes, err := c.Prune(time.Now().Add(-age), cacheKinds()...)
+		if err != nil {
+			fmt.Printf("Failed to prune the cache: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %s (%s) older than %s\n", pluralFiles(files), formatBytes(bytes), cacheOlderThan)
+	},
+}
+
+// showCacheStats prints the files, size and oldest entry of each cache
+func showCacheStats() {
+	c := openCache()
+	usage, err := c.Stats()
+	if err != nil {
+		fmt.Printf("Failed to read the cache: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Cache directory: %s\n\n", c.Dir)
+	var totalFiles int
+	var totalBytes int64
+	for _, u := range usage {
+		oldest := "-"
+		if !u.Oldest.IsZero() {
+			oldest = u.Oldest.Format("2006-01-02")
+		}
+		fmt.Printf(" %-10s %8s %10s   oldest %s\n", u.Kind, pluralFiles(u.Files), formatBytes(u.Bytes), oldest)
+		totalFiles += u.Files
+		totalBytes += u.Bytes
+	}
+	fmt.Printf(" %-10s %8s %10s\n", "total", pluralFiles(totalFiles), formatBytes(totalBytes))
+}
+
+// openCache returns the default cache or exits
+func openCache() *cache.Cache {
+	c, err := cache.Default()
+	if err != nil {
+		fmt.Printf("Failed to find the cache directory: %v\n", err)
+		os.Exit(1)
+	}
+	return c
+}
+
+// imageCache returns the cache for cover art, trimmed to image_cache_size,
+// or nil when it's turned off or unavailable
+func imageCache() *cache.Cache {
+	if cfg == nil || cfg.ImageCacheSize <= 0 {
+		return nil
+	}
+	c, err := cache.Default()
+	if err != nil {
+		return nil
+	}
+	c.Trim(cache.Images, int64(cfg.ImageCacheSize)<<20)
+	return c
+}
+
+// responseCache returns the cache for API responses, trimmed to
+// response_cache_size, or nil when it's turned off or unavailable
+func responseCache() *cache.Cache {
+	if cfg == nil || cfg.ResponseCacheSize <= 0 {
+		return nil
+	}
+	c, err := cache.Default()
+	if err != nil {
+		return nil
+	}
+	c.Trim(cache.Responses, int64(cfg.ResponseCacheSize)<<20)
+	return c
+}
+
+// cacheKinds returns the kinds picked with --kind, or all of them
+func cacheKinds() []string {
+	if cacheKind == "" || cacheKind == "all" {
+		return cache.Kinds
+	}
+	if !isOneOf(cacheKind, cache.Kinds) {
+		fmt.Printf("Unknown cache kind: %s (use %s or all)\n", cacheKind, strings.Join(cache.Kinds, ", "))
+		os.Exit(1)
+	}
+	return []string{cacheKind}
+}
+
+// parseAge parses a duration that may also use days, e.g. 7d or 36h
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%q is not a number of days", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// parseSize parses a size in bytes with an optional K, M or G suffix
+// (binary units), e.g. 500M
+func parseSize(s string) (int64, error) {
+	num, shift := strings.ToUpper(s), 0
+	for i, suffix := range []string{"K", "M", "G"} {
+		if n, ok := strings.CutSuffix(num, suffix); ok {
+			num, shift = n, 10*(i+1)
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a size like 500M or 2G", s)
+	}
+	return n << shift, nil
+}
+
+// pluralFiles formats a file count
+func pluralFiles(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+// formatBytes formats a size with binary units, e.g. 1.5 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// init registers the cache commands
+func init() {
+	cacheClearCmd.Flags().StringVar(&cacheKind, "kind", "all", "Cache to clear: "+strings.Join(cache.Kinds, ", ")+", or all")
+	cachePruneCmd.Flags().StringVar(&cacheKind, "kind", "all", "Cache to prune: "+strings.Join(cache.Kinds, ", ")+", or all")
+	cachePruneCmd.Flags().StringVar(&cacheOlderThan, "older-than", "30d", "Delete entries older than this, e.g. 7d or 12h")
+	cachePruneCmd.Flags().StringVar(&cacheMaxSize, "max-size", "", "Delete the least recently used entries until each cache fits in this size, e.g. 200M")
+
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd, cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// compareCmd shows two artists side by side
+var compareCmd = &cobra.Command{
+	Use:   `compare "artist a" "artist b"`,
+	Short: "Compare two artists side by side",
+	Long: `Show two artists in side-by-side columns with their followers, popularity,
+genres and top tracks. Artists can be names or Spotify IDs or links.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+
+		// Follower counts and top tracks come from Spotify
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		sp := p.(*provider.Spotify)
+
+		tty := setupDisplay(cmd)
+		finishCards := startCards(tty)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching " + args[0] + " and " + args[1] + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		var artists [2]*spotify.Artist
+		var topTracks [2][]spotify.Track
+		for i, query := range args {
+			if artists[i], err = findArtist(sp, query); err != nil {
+				displayOpts.Spinner.Stop()
+				if errors.Is(err, provider.ErrNotFound) {
+					fmt.Printf("No artists found for: %s\n", query)
+					outputPager.Stop()
+					os.Exit(exitNotFound)
+				}
+				fmt.Printf("Failed to get %s: %v\n", query, err)
+				outputPager.Stop()
+				os.Exit(exitCode(err))
+			}
+
+			// A missing top tracks list just shows as N/A
+			if top, err := sp.Client.GetArtistTopTracks(ctx, artists[i].ID); err == nil {
+				topTracks[i] = top.Tracks
+			} else {
+				slog.Debug("top tracks unavailable", "artist", artists[i].Name, "err", err)
+			}
+		}
+
+		display.DisplayComparison(*artists[0], *artists[1], topTracks[0], topTracks[1], displayOpts)
+		finishCards()
+	},
+}
+
+// init registers the compare command
+func init() {
+	addDisplayFlags(compareCmd)
+
+	rootCmd.AddCommand(compareCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/musicbrainz"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+// creditsCmd lists the people credited on a track
+var creditsCmd = &cobra.Command{
+	Use:   `credits "<track>"`,
+	Short: "Show songwriter, producer and engineer credits of a track",
+	Long: `Find a track, then list its songwriters, producers, engineers and performers
+from MusicBrainz, matched by ISRC when the source provides one`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+
+		var err error
+		prov, err = buildProvider(cmd.Flags().Changed("source"), cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		tty := setupListDisplay()
+		finishCards := startCards(tty)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching " + args[0] + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		track, err := prov.SearchTrack(ctx, args[0])
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, provider.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No tracks found for: %s\n", args[0])
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Search failed: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		artist := ""
+		if len(track.Artists) > 0 {
+			artist = track.Artists[0].Name
+		}
+
+		displayOpts.Spinner.SetMessage("Fetching credits from MusicBrainz...")
+		mb := musicbrainz.NewClient()
+		var credits []musicbrainz.Credit
+		id, err := mb.FindRecording(ctx, track.ExternalIDs.ISRC, track.Name, artist)
+		if err == nil {
+			credits, err = mb.Credits(ctx, id)
+		}
+		if err == nil && len(credits) == 0 {
+			err = musicbrainz.ErrNotFound
+		}
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, musicbrainz.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No credits found for %s by %s on MusicBrainz\n", track.Name, artist)
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Failed to get credits: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		lines := make([]display.Credit, len(credits))
+		for i, c := range credits {
+			lines[i] = display.Credit{Role: c.Label(), Name: c.Name}
+		}
+		display.DisplayCredits(*track, lines, displayOpts)
+		finishCards()
+	},
+}
+
+// init registers the credits command
+func init() {
+	addSourceFlag(creditsCmd)
+	creditsCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	creditsCmd.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+
+	rootCmd.AddCommand(creditsCmd)
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/internal/daemon"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/ashish0kumar/mufetch/pkg/nowplaying"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+// daemonAskTimeout bounds a lookup answered by the daemon, which may have to
+// call the API first
+const daemonAskTimeout = 30 * time.Second
+
+// daemonCacheSize caps how many answers the daemon keeps; past it the least
+// recently used go first
+const daemonCacheSize = 1000
+
+// variables for the daemon command
+var (
+	daemonPoll time.Duration
+	daemonTTL  time.Duration
+)
+
+// daemonCmd keeps a client and the now-playing poller running for status bars
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Answer lookups from other mufetch runs over a local socket",
+	Long: `Stay running with an authenticated client, a result cache and the now-playing
+poller, and answer lookups over a Unix socket. While it runs, 'mufetch now'
+and 'mufetch search' with an output format such as --polybar ask the daemon
+instead of authenticating and calling the API themselves.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if daemonPoll < time.Second {
+			fmt.Println("--poll must be at least 1s")
+			os.Exit(1)
+		}
+
+		loadConfig()
+		p, err := buildProvider(cmd.Flags().Changed("source"), cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		if !cmd.Flags().Changed("backend") {
+			nowBackend = cfg.NowBackend
+		}
+		nowPlayer = cfg.MPRISPlayer
+		src, err := newNowSource(nowBackend)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		path := daemon.SocketPath()
+		l, err := daemon.Listen(path)
+		if err != nil {
+			fmt.Printf("Failed to start the daemon: %v\n", err)
+			os.Exit(1)
+		}
+		defer os.Remove(path)
+
+		s := &daemonState{
+			prov:  p,
+			src:   src,
+			cache: newAnswerCache(daemonCacheSize),
+		}
+		s.poll()
+		go func() {
+			for range time.Tick(daemonPoll) {
+				s.poll()
+				s.cache.sweep()
+			}
+		}()
+
+		interrupt := make(chan os.Signal, 1)
+		handlesInterrupt.Store(true)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-interrupt
+			l.Close()
+		}()
+
+		fmt.Printf("Listening on %s\n", path)
+		daemon.Serve(l, s.handle)
+	},
+}
+
+// daemonEntry is a cached answer and when it was made
+type daemonEntry struct {
+	key     string
+	resp    daemon.Response
+	fetched time.Time
+}
+
+// answerCache keeps answers for --ttl, dropping the least recently used
+// once it holds max of them
+type answerCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+}
+
+// newAnswerCache creates a cache holding at most max answers
+func newAnswerCache(max int) *answerCache {
+	return &answerCache{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the answer for key if it's younger than --ttl
+func (c *answerCache) get(key string) (daemon.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return daemon.Response{}, false
+	}
+	entry := el.Value.(daemonEntry)
+	if time.Since(entry.fetched) >= daemonTTL {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return daemon.Response{}, false
+	}
+	c.order.MoveToFront(el)
+	return entry.resp, true
+}
+
+// put stores the answer for key, evicting the least recently used when full
+func (c *answerCache) put(key string, resp daemon.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := daemonEntry{key: key, resp: resp, fetched: time.Now()}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(daemonEntry).key)
+	}
+}
+
+// sweep drops every answer older than --ttl
+func (c *answerCache) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, el := range c.entries {
+		if time.Since(el.Value.(daemonEntry).fetched) >= daemonTTL {
+			c.order.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// daemonState is what the daemon keeps between requests
+type daemonState struct {
+	prov provider.Provider
+	src  nowplaying.Source
+
+	// apiMu serializes provider calls; the clients aren't safe for
+	// concurrent use
+	apiMu sync.Mutex
+	cache *answerCache
+
+	mu     sync.Mutex
+	nowKey string
+	now    daemon.Response
+}
+
+// handle answers one request
+func (s *daemonState) handle(req daemon.Request) daemon.Response {
+	switch req.Op {
+	case daemon.OpPing:
+		return daemon.Response{}
+	case daemon.OpNow:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.now
+	case daemon.OpSearch:
+		return s.search(req.Query, req.Type)
+	}
+	return daemon.Response{Error: "unknown request: " + req.Op, Code: exitError}
+}
+
+// cached answers key from the cache, or calls fetch once the cached answer
+// is older than --ttl. Answers fetch doesn't want kept, like failures, are
+// returned with ok false.
+func (s *daemonState) cached(key string, fetch func() (resp daemon.Response, ok bool)) daemon.Response {
+	if resp, ok := s.cache.get(key); ok {
+		return resp
+	}
+
+	s.apiMu.Lock()
+	defer s.apiMu.Unlock()
+	// Requests for the same key wait here; only the first asks the API
+	if resp, ok := s.cache.get(key); ok {
+		return resp
+	}
+	resp, ok := fetch()
+	if ok {
+		s.cache.put(key, resp)
+	}
+	return resp
+}
+
+// search answers a lookup from the cache, or from the provider once the
+// cached answer is older than --ttl
+func (s *daemonState) search(query, sType string) daemon.Response {
+	return s.cached(sType+"\x00"+query, func() (daemon.Response, bool) {
+		return s.searchProvider(query, sType)
+	})
+}
+
+// searchProvider looks a query up with the provider, reporting whether the
+// answer may be cached
+func (s *daemonState) searchProvider(query, sType string) (daemon.Response, bool) {
+	result, err := lookupResult(s.prov, query, sType)
+
+	var resp daemon.Response
+	switch {
+	case errors.Is(err, provider.ErrNotFound) && sType == "auto":
+		resp = daemon.Response{Error: "No results found for: " + query, Code: exitNotFound}
+	case errors.Is(err, provider.ErrNotFound):
+		resp = daemon.Response{Error: fmt.Sprintf("No %ss found for: %s", sType, query), Code: exitNotFound}
+	case err != nil:
+		// Failures aren't cached, so the next request tries again
+		return daemon.Response{Error: err.Error(), Code: exitCode(err)}, false
+	default:
+		resp = daemon.Response{Result: result}
+	}
+	return resp, true
+}
+
+// poll reads the player and, when the song changed, looks it up so `now`
+// requests are answered right away
+func (s *daemonState) poll() {
+	playing, err := s.src.Current(ctx)
+	key := nowKey(playing, err)
+
+	s.mu.Lock()
+	unchanged := key == s.nowKey && err == nil
+	s.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	var resp daemon.Response
+	switch {
+	case errors.Is(err, nowplaying.ErrUnavailable):
+		resp = daemon.Response{Error: fmt.Sprintf("No player found (backend: %s)", s.src.Name()), Code: exitError}
+	case err != nil:
+		resp = daemon.Response{Error: fmt.Sprintf("Failed to get the current song: %v", err), Code: exitCode(err)}
+	case playing == nil:
+		resp = daemon.Response{Error: "Nothing is playing", Code: exitNotFound}
+	case playing.Episode != nil:
+		resp = daemon.Response{Result: export.FromEpisode(*playing.Episode, "Spotify")}
+	case playing.Track != nil:
+		resp = daemon.Response{Result: export.FromTrack(*playing.Track, "Spotify")}
+	default:
+		s.apiMu.Lock()
+		result, err := lookupResult(s.prov, playing.Query(), "track")
+		s.apiMu.Unlock()
+		if err != nil {
+			result = export.FromTrack(playing.AsTrack(), s.src.Name())
+		}
+		resp = daemon.Response{Result: result}
+	}
+
+	s.mu.Lock()
+	s.nowKey, s.now = key, resp
+	s.mu.Unlock()
+}
+
+// lookupResult searches p like `mufetch search` does, returning the result
+// instead of showing it
+func lookupResult(p provider.Provider, query, sType string) (*export.Result, error) {
+	source := func() string {
+		if f, ok := p.(*provider.Failover); ok {
+			return f.Last().Name()
+		}
+		return p.Name()
+	}
+
+	var err error
+	if sType == "track" || sType == "auto" {
+		track, e := p.SearchTrack(ctx, query)
+		if e == nil {
+			return export.FromTrack(*track, source()), nil
+		}
+		if err = e; sType == "track" || !errors.Is(err, provider.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if sType == "album" || sType == "auto" {
+		album, e := p.SearchAlbum(ctx, query)
+		if e == nil {
+			return export.FromAlbum(*album, source()), nil
+		}
+		if err = e; sType == "album" || !errors.Is(err, provider.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if sType == "artist" || sType == "auto" {
+		artist, e := p.SearchArtist(ctx, query)
+		if e == nil {
+			return export.FromArtist(*artist, source()), nil
+		}
+		return nil, e
+	}
+	return nil, fmt.Errorf("unknown search type: %s", sType)
+}
+
+// daemonSearch asks a running daemon for the lookup and writes its answer.
+// It returns false when no daemon is running, so the caller searches itself.
+func daemonSearch(query, sType string) bool {
+	resp, err := daemon.Ask(daemon.SocketPath(), daemon.Request{Op: daemon.OpSearch, Query: query, Type: sType}, daemonAskTimeout)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case resp.Code == exitNotFound:
+		displayOpts.Spinner.Stop()
+		fmt.Fprintln(messageOutput(), resp.Error)
+		exitStatus = exitNotFound
+	case resp.Error != "":
+		displayOpts.Spinner.Stop()
+		fmt.Printf("Search failed: %s\n", resp.Error)
+		os.Exit(resp.Code)
+	default:
+		recordLookup(resp.Result)
+		writeResult(resp.Result)
+	}
+	return true
+}
+
+// daemonNow asks a running daemon for the current song and writes it. It
+// returns false when no daemon is running.
+func daemonNow() bool {
+	resp, err := daemon.Ask(daemon.SocketPath(), daemon.Request{Op: daemon.OpNow}, daemonAskTimeout)
+	if err != nil {
+		return false
+	}
+
+	if resp.Error != "" {
+		fmt.Fprintln(os.Stderr, resp.Error)
+		exitStatus = resp.Code
+		return true
+	}
+	exitStatus = exitOK
+	writeResult(resp.Result)
+	return true
+}
+
+// init registers the daemon command
+func init() {
+	daemonCmd.Flags().DurationVar(&daemonPoll, "poll", 2*time.Second, "How often to check the player for the current song")
+	daemonCmd.Flags().DurationVar(&daemonTTL, "ttl", 10*time.Minute, "How long search answers are reused before asking the API again")
+	daemonCmd.Flags().StringVar(&nowBackend, "backend", "auto", "Where to read the current song: "+strings.Join(nowplaying.SourceNames, ", "))
+	addSourceFlag(daemonCmd)
+
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// discographyGroups are the release types listed by default
+var discographyGroups []string
+
+// discographyCmd lists every release of an artist
+var discographyCmd = &cobra.Command{
+	Use:   "discography <artist>",
+	Short: "List an artist's releases by year",
+	Long: `List every album, single and compilation of an artist, oldest first and
+grouped by year. Releases the artist appears on are listed separately with
+--include appears_on. The artist can be a name or a Spotify ID or link.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, g := range discographyGroups {
+			if !isOneOf(g, []string{"album", "single", "compilation", "appears_on"}) {
+				fmt.Printf("Unknown release type: %s\n", g)
+				fmt.Println("Available types: album, single, compilation, appears_on")
+				os.Exit(1)
+			}
+		}
+
+		loadConfig()
+
+		// Discographies come from Spotify's artist albums endpoint
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		sp := p.(*provider.Spotify)
+
+		tty := setupListDisplay()
+		finishCards := startCards(tty)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching " + args[0] + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		artist, err := findArtist(sp, args[0])
+		var albums []spotify.Album
+		if err == nil {
+			albums, err = sp.Client.GetAllArtistAlbums(ctx, artist.ID, strings.Join(discographyGroups, ","))
+		}
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, provider.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No artists found for: %s\n", args[0])
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Failed to get discography: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		display.DisplayDiscography(*artist, albums, displayOpts)
+		finishCards()
+	},
+}
+
+// findArtist looks an artist up by Spotify ID or link, or else by name
+func findArtist(sp *provider.Spotify, arg string) (*spotify.Artist, error) {
+	if entityType, id, err := spotify.ParseID(arg); err == nil && (entityType == "" || entityType == "artist") {
+		return sp.Client.GetArtist(ctx, id)
+	}
+	return sp.SearchArtist(ctx, arg)
+}
+
+// init registers the discography command
+func init() {
+	discographyCmd.Flags().StringSliceVar(&discographyGroups, "include", []string{"album", "single", "compilation"}, "Release types to list: album, single, compilation, appears_on")
+	discographyCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	discographyCmd.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+
+	rootCmd.AddCommand(discographyCmd)
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+	"github.com/spf13/cobra"
+)
+
+// loadConfig reads the config file into cfg and opens the response cache.
+// --client-id and --client-secret override the config without being saved.
+func loadConfig() {
+	var err error
+	if cfg, err = config.GetConfig(); err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if clientID != "" {
+		cfg.Spotify.ClientID = clientID
+	}
+	if clientSecret != "" {
+		cfg.Spotify.ClientSecret = clientSecret
+	}
+	httpclient.ResponseCache = responseCache()
+}
+
+// cellAspect measures the terminal's cell shape from its pixel size,
+// returning 0 when the terminal doesn't report one
+func cellAspect() float64 {
+	w, h, err := platform.CellSize()
+	if err != nil || w == 0 {
+		return 0
+	}
+	if a := float64(h) / float64(w); a >= 1 && a <= 4 {
+		return a
+	}
+	return 0
+}
+
+// formatLocale returns the locale for dates and counts: format_locale from
+// the config, or the one the environment sets for times
+func formatLocale() string {
+	if cfg.FormatLocale != "" {
+		return cfg.FormatLocale
+	}
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if l := os.Getenv(name); l != "" {
+			if l == "C" || l == "POSIX" || strings.HasPrefix(l, "C.") {
+				return ""
+			}
+			return l
+		}
+	}
+	return ""
+}
+
+// addSourceFlag adds --source to commands that look up metadata
+func addSourceFlag(c *cobra.Command) {
+	c.Flags().StringVar(&source, "source", "", "Metadata source: "+strings.Join(providerNames, ", ")+", or an installed plugin (default spotify, or source from the config)")
+	c.Flags().StringVar(&locale, "locale", "", "Language for names and descriptions where the source has translations, e.g. ja or pt-BR")
+}
+
+// addDisplayFlags adds the flags that control how cards are rendered
+func addDisplayFlags(c *cobra.Command) {
+	c.Flags().IntVarP(&imageSize, "size", "s", 20, "Image size (20-50)")
+	c.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+	c.Flags().BoolVar(&noImage, "no-image", false, "Text-only mode: skip downloading and rendering art")
+	c.Flags().StringVar(&renderer, "renderer", display.RendererAuto, "Art renderer: auto, chafa, truecolor, 256, 16, or braille")
+	c.Flags().StringVar(&dither, "dither", "", "Dithering for low-color art: none, ordered, or floyd-steinberg")
+	c.Flags().StringVar(&crop, "crop", display.CropCenter, "How to fit non-square art: center, smart (face-weighted), or none")
+	c.Flags().Float64Var(&aspect, "aspect", 0, "Height-to-width ratio of a terminal cell, e.g. 2.2 (default measured from the terminal, else 2)")
+	c.Flags().IntVar(&maxWidth, "max-width", 0, "Longest value before it's cut off (default 50)")
+	c.Flags().BoolVar(&wrap, "wrap", false, "Wrap long values onto multiple lines instead of cutting them off")
+	c.Flags().BoolVar(&allTracks, "tracks", false, "List every track of an album with its number, length and explicit marker")
+	c.Flags().BoolVar(&full, "full", false, "Also show the optional fields: markets, and rights (label and copyrights) for albums")
+	c.Flags().BoolVar(&swatches, "swatches", false, "Show the cover's dominant colors under the art")
+	c.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	c.Flags().BoolVar(&forceImage, "force-image", false, "Render art even when output is piped")
+	c.Flags().BoolVar(&icons, "icons", false, "Prefix fields with Nerd Font icons")
+	c.Flags().StringVar(&logoPath, "logo", "", "Show an ASCII/ANSI art file instead of the cover art")
+	c.Flags().StringSliceVarP(&fields, "fields", "f", nil, "Comma-separated info fields to show, in order (e.g. name,artist,released)")
+}
+
+// setupDisplay validates the display flags, filling in config defaults, and
+// builds displayOpts. It reports whether stdout is an interactive terminal.
+func setupDisplay(cmd *cobra.Command) bool {
+	// Config defaults apply to whatever isn't set by a flag
+	if !cmd.Flags().Changed("size") && cfg.ImageSize > 0 {
+		imageSize = cfg.ImageSize
+	}
+	if !cmd.Flags().Changed("renderer") && cfg.Renderer != "" {
+		renderer = cfg.Renderer
+	}
+	if !cmd.Flags().Changed("dither") {
+		dither = cfg.Dither
+	}
+	if !cmd.Flags().Changed("crop") && cfg.Crop != "" {
+		crop = cfg.Crop
+	}
+	if !cmd.Flags().Changed("aspect") {
+		aspect = cfg.Aspect
+	}
+
+	// Validate image size
+	if imageSize < 15 {
+		imageSize = 15
+	}
+	if imageSize > 35 {
+		imageSize = 35
+	}
+
+	if showLyrics {
+		if _, err := newLyricsClient(cfg); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	// Fall back to the configured field list when --fields isn't given
+	if !cmd.Flags().Changed("fields") {
+		fields = cfg.Fields
+	}
+	for _, f := range fields {
+		if !display.IsValidField(f) {
+			fmt.Printf("Unknown field: %s\n", f)
+			fmt.Printf("Available fields: %s\n", strings.Join(display.FieldNames, ", "))
+			os.Exit(1)
+		}
+	}
+
+	if !cmd.Flags().Changed("no-image") {
+		noImage = cfg.NoImage
+	}
+
+	// Piped output stays plain so grep and files get readable text, as does
+	// a Windows console too old for escape codes
+	tty := platform.IsTerminal(os.Stdout) && outputPath == ""
+	noColor := (!tty || !vtOK || os.Getenv("NO_COLOR") != "") && !forceColor
+	if (!tty || !vtOK) && !forceImage {
+		noImage = true
+	}
+	if !cmd.Flags().Changed("icons") {
+		icons = cfg.Icons
+	}
+	if !cmd.Flags().Changed("max-width") {
+		maxWidth = cfg.MaxWidth
+	}
+	if !cmd.Flags().Changed("wrap") {
+		wrap = cfg.Wrap
+	}
+	if !cmd.Flags().Changed("swatches") {
+		swatches = cfg.Swatches
+	}
+	if maxWidth < 0 || (maxWidth > 0 && maxWidth < 10) {
+		fmt.Println("Max width must be at least 10")
+		os.Exit(1)
+	}
+
+	if !isOneOf(renderer, display.RendererNames) {
+		fmt.Printf("Unknown renderer: %s\n", renderer)
+		fmt.Printf("Available renderers: %s\n", strings.Join(display.RendererNames, ", "))
+		os.Exit(1)
+	}
+	if dither != "" && !isOneOf(dither, display.DitherNames) {
+		fmt.Printf("Unknown dither method: %s\n", dither)
+		fmt.Printf("Available methods: %s\n", strings.Join(display.DitherNames, ", "))
+		os.Exit(1)
+	}
+
+	if !isOneOf(crop, display.CropNames) {
+		fmt.Printf("Unknown crop mode: %s\n", crop)
+		fmt.Printf("Available modes: %s\n", strings.Join(display.CropNames, ", "))
+		os.Exit(1)
+	}
+	if aspect != 0 && (aspect < 1 || aspect > 4) {
+		fmt.Println("Aspect must be between 1 and 4")
+		os.Exit(1)
+	}
+	if aspect == 0 && tty {
+		aspect = cellAspect()
+	}
+
+	var logo []string
+	var err error
+	if logoPath != "" {
+		if logo, err = display.LoadLogo(logoPath); err != nil {
+			fmt.Printf("Failed to load logo: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	theme, err := buildTheme(cfg.Theme)
+	if err != nil {
+		fmt.Printf("Invalid theme: %v\n", err)
+		os.Exit(1)
+	}
+
+	displayOpts = display.Options{
+		ImageSize: imageSize,
+		Fields:    fields,
+		NoImage:   noImage,
+		Theme:     theme,
+		Renderer:  renderer,
+		Dither:    dither,
+		Crop:      crop,
+		Aspect:    aspect,
+		Logo:      logo,
+		Icons:     icons,
+		MaxWidth:  maxWidth,
+		Wrap:      wrap,
+		Full:      full,
+		Tracks:    allTracks,
+		Swatches:  swatches,
+		PNGPath:   pngPath,
+		NoColor:   noColor,
+		NoLinks:   !platform.ShowHyperlinks(),
+		Locale:    formatLocale(),
+		Market:    cfg.Market,
+		Context:   ctx,
+	}
+	if !noImage {
+		displayOpts.ImageCache = imageCache()
+	}
+
+	return tty
+}
+
+// setupListDisplay builds displayOpts for list views like the discography,
+// which have no art: only the theme, width and color settings apply
+func setupListDisplay() bool {
+	tty := platform.IsTerminal(os.Stdout)
+
+	theme, err := buildTheme(cfg.Theme)
+	if err != nil {
+		fmt.Printf("Invalid theme: %v\n", err)
+		os.Exit(1)
+	}
+
+	displayOpts = display.Options{
+		Theme:    theme,
+		MaxWidth: cfg.MaxWidth,
+		NoColor:  (!tty || !vtOK || os.Getenv("NO_COLOR") != "") && !forceColor,
+		NoLinks:  !platform.ShowHyperlinks(),
+		Locale:   formatLocale(),
+		Context:  ctx,
+	}
+	return tty
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+	"github.com/ashish0kumar/mufetch/pkg/lyrics"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// checkStatus is the outcome of a doctor check
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// checkResult is one line of the doctor report, with a fix for problems
+type checkResult struct {
+	name   string
+	status checkStatus
+	detail string
+	fix    string
+}
+
+// doctorCmd diagnoses setup problems
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check your setup for problems",
+	Long: `Check the config file, Spotify credentials, terminal features and network
+access, and suggest fixes for anything that's wrong`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+
+		color := platform.IsTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+		sections := []struct {
+			title  string
+			checks []checkResult
+		}{
+			{"Config", checkConfig()},
+			{"Credentials", checkCredentials()},
+			{"Terminal", checkTerminal()},
+			{"Network", checkNetwork()},
+		}
+
+		failed := false
+		for _, section := range sections {
+			fmt.Println(section.title)
+			for _, c := range section.checks {
+				fmt.Printf(" %s %-18s %s\n", statusMark(c.status, color), c.name, c.detail)
+				if c.fix != "" && c.status != checkOK {
+					fmt.Printf("%s-> %s\n", strings.Repeat(" ", 25), c.fix)
+				}
+				failed = failed || c.status == checkFail
+			}
+			fmt.Println()
+		}
+
+		if failed {
+			exitStatus = exitError
+		}
+	},
+}
+
+// statusMark returns the symbol for a check's status
+func statusMark(status checkStatus, color bool) string {
+	mark, code := "ok", display.ColorGreen
+	switch status {
+	case checkWarn:
+		mark, code = "!!", display.ColorYellow
+	case checkFail:
+		mark, code = "xx", display.ColorRed
+	}
+	if !color {
+		return "[" + mark + "]"
+	}
+	return code + "[" + mark + "]" + display.ColorReset
+}
+
+// checkConfig reports where the config file is
+func checkConfig() []checkResult {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return []checkResult{{name: "Config file", status: checkWarn, detail: "none, using defaults and the environment", fix: "run 'mufetch auth' to create it"}}
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return []checkResult{{name: "Config file", status: checkWarn, detail: path + " doesn't exist yet, using defaults and the environment", fix: "run 'mufetch auth' to create it"}}
+	} else if err != nil {
+		return []checkResult{{name: "Config file", status: checkFail, detail: err.Error(), fix: "run 'mufetch auth' to create it"}}
+	}
+	results := []checkResult{{name: "Config file", status: checkOK, detail: path}}
+	if name := config.Profile(); name != "" {
+		results = append(results, checkResult{name: "Profile", status: checkOK, detail: name})
+	}
+	if config.UsesKeyring() {
+		if err := config.KeyringAvailable(); err != nil {
+			results = append(results, checkResult{name: "Secret store", status: checkFail, detail: err.Error(), fix: "set secret_store: config and run 'mufetch auth' again"})
+		} else {
+			results = append(results, checkResult{name: "Secret store", status: checkOK, detail: "OS keyring"})
+		}
+	}
+	return results
+}
+
+// checkCredentials requests tokens with the configured credentials
+func checkCredentials() []checkResult {
+	var results []checkResult
+
+	if cfg.Spotify.ClientID == "" || cfg.Spotify.ClientSecret == "" {
+		results = append(results, checkResult{
+			name: "Spotify app", status: checkFail, detail: "no client ID or secret",
+			fix: "run 'mufetch auth', set MUFETCH_SPOTIFY_CLIENT_ID and MUFETCH_SPOTIFY_CLIENT_SECRET, or pass --client-id and --client-secret",
+		})
+	} else if err := spotify.NewClient(cfg.Spotify.ClientID, cfg.Spotify.ClientSecret).Authenticate(ctx); err != nil {
+		results = append(results, checkResult{
+			name: "Spotify app", status: checkFail, detail: err.Error(),
+			fix: credentialFix(err, "check the client ID and secret at https://developer.spotify.com/dashboard and run 'mufetch auth' again"),
+		})
+	} else {
+		results = append(results, checkResult{name: "Spotify app", status: checkOK, detail: "token request succeeded"})
+	}
+
+	if cfg.Spotify.RefreshToken == "" && cfg.Spotify.UserToken == "" {
+		results = append(results, checkResult{
+			name: "Spotify account", status: checkWarn, detail: "not signed in",
+			fix: "run 'mufetch auth --user' to use 'now' and 'top'",
+		})
+	} else if user, err := userClient(); err != nil {
+		results = append(results, checkResult{name: "Spotify account", status: checkFail, detail: err.Error(), fix: "run 'mufetch auth --user'"})
+	} else if err := user.Authenticate(ctx); err != nil {
+		results = append(results, checkResult{
+			name: "Spotify account", status: checkFail, detail: err.Error(),
+			fix: credentialFix(err, "run 'mufetch auth --user' to sign in again"),
+		})
+	} else {
+		results = append(results, checkResult{name: "Spotify account", status: checkOK, detail: "signed in"})
+	}
+
+	return results
+}
+
+// credentialFix suggests a fix for a failed token request; network errors
+// point at the connection rather than the credentials
+func credentialFix(err error, fix string) string {
+	if exitCode(err) == exitNetwork {
+		return "check your internet connection"
+	}
+	return fix
+}
+
+// checkTerminal guesses what the terminal supports from the environment
+func checkTerminal() []checkResult {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	var results []checkResult
+
+	if platform.IsTerminal(os.Stdout) {
+		results = append(results, checkResult{name: "Output", status: checkOK, detail: "interactive terminal"})
+	} else {
+		results = append(results, checkResult{name: "Output", status: checkWarn, detail: "not a terminal, cards print without color or art", fix: "use --force-color and --force-image when piping"})
+	}
+
+	if runtime.GOOS == "windows" && !vtOK {
+		results = append(results, checkResult{name: "Escape codes", status: checkFail, detail: "the console can't show colors or art", fix: "use Windows Terminal, or Windows 10 or later"})
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		results = append(results, checkResult{name: "Color", status: checkWarn, detail: "disabled by NO_COLOR", fix: "unset NO_COLOR or pass --force-color"})
+	}
+
+	if platform.SupportsTrueColor() {
+		results = append(results, checkResult{name: "Truecolor", status: checkOK, detail: "supported"})
+	} else {
+		results = append(results, checkResult{
+			name: "Truecolor", status: checkWarn, detail: "not advertised (COLORTERM is unset)",
+			fix: "if the art looks wrong, try --renderer 256 or --renderer 16",
+		})
+	}
+
+	switch {
+	case term == "xterm-kitty" || os.Getenv("KITTY_WINDOW_ID") != "" || program == "ghostty" || program == "WezTerm":
+		results = append(results, checkResult{name: "Graphics", status: checkOK, detail: "kitty graphics protocol"})
+	case program == "iTerm.app" || term == "foot" || term == "mlterm" || strings.Contains(term, "sixel"):
+		results = append(results, checkResult{name: "Graphics", status: checkOK, detail: "sixel or inline images"})
+	default:
+		results = append(results, checkResult{name: "Graphics", status: checkWarn, detail: "no image protocol detected, art uses text blocks"})
+	}
+
+	if _, err := exec.LookPath("chafa"); err == nil {
+		results = append(results, checkResult{name: "chafa", status: checkOK, detail: "installed"})
+	} else {
+		results = append(results, checkResult{name: "chafa", status: checkWarn, detail: "not installed", fix: "install chafa for sharper album art"})
+	}
+
+	if plugins := provider.Plugins(); len(plugins) > 0 {
+		results = append(results, checkResult{name: "Plugins", status: checkOK, detail: strings.Join(plugins, ", ")})
+	}
+
+	switch {
+	case platform.SupportsHyperlinks():
+		results = append(results, checkResult{name: "Hyperlinks", status: checkOK, detail: "supported"})
+	case !platform.ShowHyperlinks():
+		results = append(results, checkResult{name: "Hyperlinks", status: checkWarn, detail: "not supported by this console, names print as plain text", fix: "use Windows Terminal for clickable names"})
+	default:
+		results = append(results, checkResult{name: "Hyperlinks", status: checkWarn, detail: "not detected, names may not be clickable"})
+	}
+
+	return results
+}
+
+// checkNetwork makes a request to each service mufetch talks to
+func checkNetwork() []checkResult {
+	lyricsURL := lyrics.DefaultBaseURL
+	if cfg.LyricsURL != "" {
+		lyricsURL = cfg.LyricsURL
+	}
+	hosts := []struct{ name, url string }{
+		{"Spotify accounts", "https://accounts.spotify.com"},
+		{"Spotify API", "https://api.spotify.com"},
+		{"Lyrics", lyricsURL},
+	}
+
+	httpClient := &http.Client{Transport: httpclient.Transport, Timeout: 10 * time.Second}
+	var results []checkResult
+	for _, h := range hosts {
+		start := time.Now()
+		resp, err := httpClient.Head(h.url)
+		if err != nil {
+			results = append(results, checkResult{
+				name: h.name, status: checkFail, detail: err.Error(),
+				fix: "check your connection, firewall or proxy settings",
+			})
+			continue
+		}
+		resp.Body.Close()
+		results = append(results, checkResult{name: h.name, status: checkOK, detail: fmt.Sprintf("reachable (%dms)", time.Since(start).Milliseconds())})
+	}
+	return results
+}
+
+// init registers the doctor command
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
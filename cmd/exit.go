@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// Exit codes let scripts tell "nothing matched" apart from broken
+// credentials or a network outage
+const (
+	exitOK           = 0
+	exitError        = 1   // Invalid flags, config, or any other failure
+	exitNotFound     = 2   // The search returned no results
+	exitUnauthorized = 3   // Missing or rejected credentials
+	exitRateLimited  = 4   // The API is throttling requests
+	exitNetwork      = 5   // The API couldn't be reached
+	exitNewReleases  = 6   // `releases` found something new, for cron jobs
+	exitInterrupted  = 130 // Stopped with Ctrl-C, like other shell commands
+)
+
+// exitStatus is returned once the command finishes, so a batch with an
+// unmatched query still prints the other results first
+var exitStatus = exitOK
+
+// exitCode picks the exit code for a failed request
+func exitCode(err error) int {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, spotify.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, spotify.ErrUnauthorized):
+		return exitUnauthorized
+	case errors.Is(err, spotify.ErrRateLimited):
+		return exitRateLimited
+	case errors.As(err, &netErr):
+		return exitNetwork
+	}
+	return exitError
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/internal/follow"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+// followCmd manages the artists checked by `mufetch releases`
+var followCmd = &cobra.Command{
+	Use:   "follow",
+	Short: "Manage the artists checked for new releases",
+	Long: `Keep a local list of artists to check with 'mufetch releases'. This is
+separate from the artists you follow on Spotify.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listFollowed()
+	},
+}
+
+// followAddCmd follows an artist
+var followAddCmd = &cobra.Command{
+	Use:   `add "<artist>"`,
+	Short: "Follow an artist by name or Spotify ID/link",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		artist, err := findArtist(p.(*provider.Spotify), args[0])
+		if err != nil {
+			fmt.Printf("Failed to find artist: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		added, err := followStore().Add(follow.Artist{ID: artist.ID, Name: artist.Name, Added: time.Now()})
+		if err != nil {
+			fmt.Printf("Failed to save followed artists: %v\n", err)
+			os.Exit(1)
+		}
+		if !added {
+			fmt.Printf("Already following %s\n", artist.Name)
+			return
+		}
+		fmt.Printf("Following %s\n", artist.Name)
+	},
+}
+
+// followRemoveCmd unfollows an artist
+var followRemoveCmd = &cobra.Command{
+	Use:   `remove "<artist>"`,
+	Short: "Stop following an artist, by name or Spotify ID",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := followStore().Remove(args[0])
+		if err != nil {
+			fmt.Printf("Failed to update followed artists: %v\n", err)
+			os.Exit(1)
+		}
+		if removed == nil {
+			fmt.Printf("Not following: %s\n", args[0])
+			os.Exit(exitNotFound)
+		}
+		fmt.Printf("Unfollowed %s\n", removed.Name)
+	},
+}
+
+// followListCmd lists followed artists
+var followListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List followed artists",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listFollowed()
+	},
+}
+
+// listFollowed prints the followed artists with when they were added
+func listFollowed() {
+	artists, err := followStore().Load()
+	if err != nil {
+		fmt.Printf("Failed to read followed artists: %v\n", err)
+		os.Exit(1)
+	}
+	if len(artists) == 0 {
+		fmt.Println("Not following anyone yet, add artists with 'mufetch follow add <artist>'")
+		return
+	}
+	for _, a := range artists {
+		fmt.Printf(" %s %s  since %s\n", runewidth.FillRight(a.Name, 30), a.ID, a.Added.Format("2006-01-02"))
+	}
+}
+
+// followStore opens the followed artists in the config directory
+func followStore() *follow.Store {
+	dir, err := config.Dir()
+	if err != nil {
+		fmt.Printf("Failed to find the config directory: %v\n", err)
+		os.Exit(1)
+	}
+	return follow.NewStore(dir)
+}
+
+// announcedStore opens the releases the release hook was already given
+func announcedStore() *follow.Announced {
+	dir, err := config.Dir()
+	if err != nil {
+		fmt.Printf("Failed to find the config directory: %v\n", err)
+		os.Exit(1)
+	}
+	return follow.NewAnnounced(dir)
+}
+
+// init registers the follow commands
+func init() {
+	followCmd.AddCommand(followAddCmd, followRemoveCmd, followListCmd)
+	rootCmd.AddCommand(followCmd)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// getType is the entity type for bare IDs passed to get
+var getType string
+
+// getCmd fetches Spotify entities directly by ID, URI or link
+var getCmd = &cobra.Command{
+	Use:   "get <spotify-id|uri|url>...",
+	Short: "Show tracks, albums, or artists by Spotify ID or link",
+	Long: `Fetch tracks, albums, or artists directly by their Spotify IDs, URIs
+(spotify:album:...) or open.spotify.com links, without searching. Several
+IDs of a type are fetched together.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		types := make([]string, len(args))
+		ids := make(map[string][]string)
+		for i, arg := range args {
+			entityType, id, err := spotifyIDType(arg, getType, cmd.Flags().Changed("type"))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			types[i] = entityType
+			ids[entityType] = append(ids[entityType], id)
+		}
+
+		loadConfig()
+
+		// IDs are Spotify's, so this always uses the Spotify provider
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		prov = p
+		sp := p.(*provider.Spotify)
+
+		setupOutputFormat(cmd)
+		tty := setupDisplay(cmd)
+		closeOutput := openOutput()
+		defer closeOutput()
+		finishCards := startCards(tty)
+
+		message := "Fetching " + args[0] + "..."
+		if len(args) > 1 {
+			message = fmt.Sprintf("Fetching %d items...", len(args))
+		}
+		displayOpts.Spinner = display.NewSpinner(message)
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		// One request per type (per batch of 20-50 IDs) instead of one per ID
+		var tracks []*spotify.Track
+		var albums []*spotify.Album
+		var artists []*spotify.Artist
+		for entityType, batch := range ids {
+			switch entityType {
+			case "track":
+				tracks, err = sp.Client.GetTracks(ctx, batch)
+			case "album":
+				albums, err = sp.Client.GetAlbums(ctx, batch)
+			case "artist":
+				artists, err = sp.Client.GetArtists(ctx, batch)
+			}
+			if err != nil {
+				displayOpts.Spinner.Stop()
+				fmt.Printf("Failed to get %ss: %v\n", entityType, err)
+				outputPager.Stop()
+				os.Exit(exitCode(err))
+			}
+		}
+
+		// Show the results in the order they were asked for
+		for i, entityType := range types {
+			lookupQuery, lookupType = args[i], "id"
+			found := true
+			switch entityType {
+			case "track":
+				if found = tracks[0] != nil; found {
+					showTrack(tracks[0])
+				}
+				tracks = tracks[1:]
+			case "album":
+				if found = albums[0] != nil; found {
+					showAlbum(albums[0])
+				}
+				albums = albums[1:]
+			case "artist":
+				if found = artists[0] != nil; found {
+					showArtist(artists[0])
+				}
+				artists = artists[1:]
+			}
+			if !found {
+				displayOpts.Spinner.Stop()
+				fmt.Fprintf(os.Stderr, "No %s found for: %s\n", entityType, args[i])
+				exitStatus = exitNotFound
+			}
+		}
+
+		finishCards()
+		saveHTML()
+	},
+}
+
+// spotifyIDType parses arg and works out its type: the one in the URI or
+// URL, else --type (track by default). An explicit --type must agree.
+func spotifyIDType(arg, flagType string, explicit bool) (string, string, error) {
+	entityType, id, err := spotify.ParseID(arg)
+	if err != nil {
+		return "", "", err
+	}
+	if !isOneOf(flagType, []string{"track", "album", "artist"}) {
+		return "", "", fmt.Errorf("unknown type: %s", flagType)
+	}
+
+	switch {
+	case entityType == "":
+		entityType = flagType
+	case explicit && entityType != flagType:
+		return "", "", fmt.Errorf("--type %s doesn't match the %s link", flagType, entityType)
+	case !isOneOf(entityType, []string{"track", "album", "artist"}):
+		return "", "", fmt.Errorf("unsupported Spotify type: %s", entityType)
+	}
+	return entityType, id, nil
+}
+
+// init registers the get command
+func init() {
+	getCmd.Flags().StringVarP(&getType, "type", "t", "track", "Type of a bare ID: track, album, or artist")
+	addDisplayFlags(getCmd)
+	addOutputFlags(getCmd)
+	getCmd.Flags().BoolVar(&showLyrics, "lyrics", false, "Show the opening lyrics next to the track")
+
+	rootCmd.AddCommand(getCmd)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+)
+
+// defaultGridLimit is the number of results shown by --grid without --limit
+const defaultGridLimit = 8
+
+// searchGrid shows several matches as a grid of thumbnails; auto searches
+// list albums since those are what people usually pick between visually
+func searchGrid(query, sType string, limit int) {
+	var items []display.GridItem
+	var err error
+
+	switch sType {
+	case "track":
+		tracks, e := provider.ListTracks(ctx, prov, query, limit)
+		items, err = display.TrackGridItems(tracks), e
+	case "album", "auto":
+		albums, e := provider.ListAlbums(ctx, prov, query, limit)
+		items, err = display.AlbumGridItems(albums), e
+		sType = "album"
+	case "artist":
+		artists, e := provider.ListArtists(ctx, prov, query, limit)
+		items, err = display.ArtistGridItems(artists), e
+	default:
+		err = fmt.Errorf("unknown search type: %s", sType)
+	}
+
+	if err != nil {
+		displayOpts.Spinner.Stop()
+		if errors.Is(err, provider.ErrNotFound) {
+			fmt.Printf("No %ss found for: %s\n", sType, query)
+			exitStatus = exitNotFound
+			return
+		}
+		fmt.Printf("Search failed: %v\n", err)
+		outputPager.Stop()
+		os.Exit(exitCode(err))
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	display.DisplayGrid(items, displayOpts)
+}
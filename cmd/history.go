@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/internal/history"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/spf13/cobra"
+)
+
+// variables for the history command
+var (
+	historyClear bool
+	historyRun   int
+	historyLimit int
+	historyUTC   bool
+
+	// lookupQuery and lookupType describe the lookup in progress, for the
+	// history entry of its result
+	lookupQuery string
+	lookupType  string
+)
+
+// historyCmd lists and re-runs past lookups
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List or re-run past lookups",
+	Long: `List past lookups newest first, grouped by day. Use --run with an entry's
+number to look it up again. Set history: false in the config to stop recording.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+
+		store, err := historyStore()
+		if err != nil {
+			fmt.Printf("Failed to open history: %v\n", err)
+			os.Exit(1)
+		}
+
+		if historyClear {
+			if err := store.Clear(); err != nil {
+				fmt.Printf("Failed to clear history: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("History cleared")
+			return
+		}
+
+		entries, err := store.Load()
+		if err != nil {
+			fmt.Printf("Failed to read history: %v\n", err)
+			os.Exit(1)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No lookups recorded yet")
+			if !cfg.History {
+				fmt.Println("History is turned off, set history: true in the config to record lookups")
+			}
+			return
+		}
+
+		// Entries are numbered newest first
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+
+		if historyRun != 0 {
+			if historyRun < 1 || historyRun > len(entries) {
+				fmt.Printf("No history entry %d\n", historyRun)
+				os.Exit(1)
+			}
+			rerunLookup(entries[historyRun-1])
+			return
+		}
+
+		if historyLimit > 0 && len(entries) > historyLimit {
+			entries = entries[:historyLimit]
+		}
+
+		timeline := make([]display.TimelineEntry, len(entries))
+		for i, e := range entries {
+			subtitle := fmt.Sprintf("%q", e.Query)
+			if e.Artist != "" {
+				subtitle = e.Artist + " · " + subtitle
+			}
+			timeline[i] = display.TimelineEntry{
+				Time:     e.Time,
+				Title:    fmt.Sprintf("%d. %s", i+1, e.Name),
+				Subtitle: subtitle,
+				URL:      e.URL,
+			}
+		}
+		setupListDisplay()
+		display.DisplayTimeline(timeline, historyUTC, displayOpts)
+	},
+}
+
+// historyStore opens the history file in the config directory
+func historyStore() (*history.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return history.NewStore(dir), nil
+}
+
+// recordLookup adds a result to the history unless it's turned off. History
+// is a convenience, so failing to write it doesn't fail the lookup.
+func recordLookup(r *export.Result) {
+	// Status bars and --watch repeat the same lookup
+	if cfg == nil || !cfg.History || lookupQuery == "" || interval != 0 || watchSeconds != 0 {
+		return
+	}
+
+	store, err := historyStore()
+	if err != nil {
+		return
+	}
+	store.Add(history.Entry{
+		Time:   time.Now(),
+		Query:  lookupQuery,
+		Type:   lookupType,
+		Result: r.Type,
+		Name:   r.Name,
+		Artist: strings.Join(r.Artists, ", "),
+		URL:    r.URL,
+	})
+}
+
+// rerunLookup runs a past lookup again as a new mufetch process, exiting
+// with its status
+func rerunLookup(e history.Entry) {
+	args := []string{"search", e.Query, "-t", e.Type}
+	if e.Type == "id" {
+		args = []string{"get", e.Query, "-t", e.Result}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Printf("Failed to re-run lookup: %v\n", err)
+		os.Exit(1)
+	}
+
+	run := exec.Command(exe, args...)
+	run.Stdin, run.Stdout, run.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := run.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Printf("Failed to re-run lookup: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// init registers the history command
+func init() {
+	historyCmd.Flags().BoolVar(&historyClear, "clear", false, "Delete the lookup history")
+	historyCmd.Flags().IntVar(&historyRun, "run", 0, "Look up entry N again (1 is the newest)")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Number of entries to list (0 for all)")
+	historyCmd.Flags().BoolVar(&historyUTC, "utc", false, "Show times in UTC instead of local time")
+	historyCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+)
+
+// ctx is cancelled by Ctrl-C, aborting the requests in flight
+var ctx = context.Background()
+
+// handlesInterrupt is set by commands that stop on Ctrl-C themselves, like
+// --watch loops that finish the current lookup and save their output. A
+// second Ctrl-C still aborts them.
+var handlesInterrupt atomic.Bool
+
+// watchInterrupt cancels ctx on Ctrl-C or SIGTERM and exits, leaving the
+// terminal as it was: spinner gone, pager closed and cursor visible
+func watchInterrupt(cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	<-signals
+	if handlesInterrupt.Load() {
+		<-signals
+	}
+
+	// Clean up before cancelling, so the aborted requests' errors never
+	// reach the screen
+	displayOpts.Spinner.Stop()
+	outputPager.Stop()
+	if platform.IsTerminal(os.Stdout) {
+		fmt.Print("\033[?25h")
+	}
+	cancel()
+	os.Exit(exitInterrupted)
+}
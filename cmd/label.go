@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+// variables for the label command
+var (
+	labelLimit int
+	labelNew   bool
+)
+
+// labelCmd lists a record label's recent releases
+var labelCmd = &cobra.Command{
+	Use:   "label <label name>",
+	Short: "List recent releases from a record label",
+	Long: `List a record label's releases newest first, grouped by year, using Spotify's
+label: search filter`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if labelLimit < 1 || labelLimit > 50 {
+			fmt.Println("Limit must be between 1 and 50")
+			os.Exit(1)
+		}
+
+		loadConfig()
+
+		// The label: filter is a Spotify search feature
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		sp := p.(*provider.Spotify)
+
+		tty := setupListDisplay()
+		finishCards := startCards(tty)
+
+		name := strings.TrimSpace(args[0])
+		displayOpts.Spinner = display.NewSpinner("Fetching releases from " + name + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		query := fmt.Sprintf("label:%q", name)
+		if labelNew {
+			query += " tag:new" // Released in the past two weeks
+		}
+		albums, err := sp.ListAlbums(ctx, query, labelLimit)
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, provider.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No releases found for label: %s\n", name)
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Failed to get releases: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		display.DisplayReleases(name, albums, displayOpts)
+		finishCards()
+	},
+}
+
+// init registers the label command
+func init() {
+	labelCmd.Flags().IntVar(&labelLimit, "limit", 30, "Number of releases to fetch (1-50)")
+	labelCmd.Flags().BoolVar(&labelNew, "new", false, "Only list releases from the past two weeks")
+	labelCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	labelCmd.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+
+	rootCmd.AddCommand(labelCmd)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/lyrics"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// lyricsExcerptLines caps the lyrics panel next to the card
+const lyricsExcerptLines = 12
+
+// lyricsProviders lists the accepted lyrics_provider values
+var lyricsProviders = []string{"lrclib", "none"}
+
+// newLyricsClient returns the configured lyrics source, or nil when lyrics
+// are disabled with lyrics_provider: none
+func newLyricsClient(cfg *config.Config) (*lyrics.Client, error) {
+	switch cfg.LyricsProvider {
+	case "none":
+		return nil, nil
+	case "lrclib", "":
+	default:
+		return nil, fmt.Errorf("unknown lyrics_provider: %s (choose %s)", cfg.LyricsProvider, strings.Join(lyricsProviders, " or "))
+	}
+
+	lc := lyrics.NewClient()
+	if cfg.LyricsURL != "" {
+		lc.BaseURL = cfg.LyricsURL
+	}
+	return lc, nil
+}
+
+// trackLyrics returns the opening lyrics of a track, or nil when lyrics are
+// disabled or missing. The error is set when the lookup itself failed.
+func trackLyrics(track *spotify.Track) ([]string, error) {
+	lc, err := newLyricsClient(cfg)
+	if lc == nil {
+		return nil, err
+	}
+
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0].Name
+	}
+	duration := time.Duration(track.Duration) * time.Millisecond
+
+	found, err := lc.Get(ctx, artist, track.Name, duration)
+	if errors.Is(err, lyrics.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return found.FirstSection(lyricsExcerptLines), nil
+}
+
+// lyricsCmd prints the full lyrics of a song
+var lyricsCmd = &cobra.Command{
+	Use:   `lyrics "<song>"`,
+	Short: "Show the lyrics of a song",
+	Long: `Find a song, then print its full lyrics from LRCLIB under the track's name,
+artists and album. Long lyrics go through the pager.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+
+		lc, err := newLyricsClient(cfg)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if lc == nil {
+			fmt.Println("Lyrics are turned off, set lyrics_provider: lrclib in the config")
+			os.Exit(1)
+		}
+
+		prov, err = buildProvider(cmd.Flags().Changed("source"), cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		tty := setupListDisplay()
+		finishCards := startCards(tty)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching " + args[0] + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		track, err := prov.SearchTrack(ctx, args[0])
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, provider.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No tracks found for: %s\n", args[0])
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Search failed: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		artist := ""
+		if len(track.Artists) > 0 {
+			artist = track.Artists[0].Name
+		}
+		found, err := lc.Get(ctx, artist, track.Name, time.Duration(track.Duration)*time.Millisecond)
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, lyrics.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No lyrics found for %s by %s\n", track.Name, artist)
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Failed to get lyrics: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		display.DisplayLyrics(*track, found.Lines(), displayOpts)
+		finishCards()
+	},
+}
+
+// init registers the lyrics command
+func init() {
+	addSourceFlag(lyricsCmd)
+	lyricsCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	lyricsCmd.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+
+	rootCmd.AddCommand(lyricsCmd)
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/audio"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/ashish0kumar/mufetch/pkg/nowplaying"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// variables for the now command
+var (
+	nowBackend string
+	nowPlayer  string
+	nowWatch   bool
+	nowPoll    time.Duration
+
+	// nowClient acts as the user for the Spotify backend
+	nowClient *spotify.Client
+)
+
+// nowTagFields are shown for songs that couldn't be looked up
+var nowTagFields = []string{"name", "artist", "album", "duration", "quality"}
+
+// nowCmd shows the song that's currently playing
+var nowCmd = &cobra.Command{
+	Use:   "now",
+	Short: "Show the currently playing song",
+	Long: `Show the song playing in a local player (MPRIS on Linux, MPD) or on your
+Spotify account, optionally staying open and redrawing when it changes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+		setupOutputFormat(cmd)
+		closeOutput := openOutput()
+		defer closeOutput()
+
+		// A running daemon already polls the player; ask it instead
+		if outputFormat != "" && !nowWatch && !cmd.Flags().Changed("backend") && !cmd.Flags().Changed("player") &&
+			!cmd.Flags().Changed("source") && !cmd.Flags().Changed("locale") && daemonNow() {
+			return
+		}
+
+		if !cmd.Flags().Changed("backend") {
+			nowBackend = cfg.NowBackend
+		}
+		if !cmd.Flags().Changed("player") {
+			nowPlayer = cfg.MPRISPlayer
+		}
+		src, err := newNowSource(nowBackend)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		if nowPoll < time.Second {
+			fmt.Println("--poll must be at least 1s")
+			os.Exit(1)
+		}
+
+		tty := setupDisplay(cmd)
+
+		// Local players only know the tags, so songs are looked up on the
+		// metadata source when one is set up
+		prov, _ = buildProvider(cmd.Flags().Changed("source"), cfg)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching current song...")
+		defer displayOpts.Spinner.Stop()
+
+		if tty && outputFormat == "" {
+			fmt.Print("\033[?25l")
+			defer fmt.Print("\033[?25h")
+		}
+
+		if !nowWatch {
+			if tty && outputFormat == "" {
+				fmt.Printf("\n")
+			}
+			displayOpts.Spinner.Start()
+			playing, err := src.Current(ctx)
+			showNowPlaying(src, playing, err)
+			if tty && outputFormat == "" {
+				fmt.Print("\033[F\033[K\n")
+			}
+			return
+		}
+
+		watchNowPlaying(src, tty)
+	},
+}
+
+// newNowSource creates the now-playing backend by name
+func newNowSource(name string) (nowplaying.Source, error) {
+	var userErr error
+	nowClient, userErr = userClient()
+	sp := nowplaying.NewSpotify(nowClient)
+
+	switch name {
+	case "auto":
+		return nowplaying.NewAuto(sp, nowplaying.NewMPRIS(nowPlayer), nowplaying.NewMPD(cfg.MPDHost)), nil
+	case "spotify":
+		if userErr != nil {
+			return nil, userErr
+		}
+		return sp, nil
+	case "mpris":
+		return nowplaying.NewMPRIS(nowPlayer), nil
+	case "mpd":
+		return nowplaying.NewMPD(cfg.MPDHost), nil
+	}
+	return nil, fmt.Errorf("unknown backend: %s\nAvailable backends: %s", name, strings.Join(nowplaying.SourceNames, ", "))
+}
+
+// showNowPlaying prints the card for the current song, or a notice when
+// nothing is playing or the player can't be read
+func showNowPlaying(src nowplaying.Source, playing *nowplaying.Playing, err error) {
+	displayOpts.Spinner.Stop()
+
+	switch {
+	case errors.Is(err, nowplaying.ErrUnavailable):
+		fmt.Fprintf(os.Stderr, "No player found (backend: %s)\n", src.Name())
+		exitStatus = exitError
+		return
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Failed to get the current song: %v\n", err)
+		exitStatus = exitCode(err)
+		return
+	case playing == nil:
+		fmt.Fprintln(os.Stderr, "Nothing is playing")
+		exitStatus = exitNotFound
+		return
+	}
+
+	exitStatus = exitOK
+	if playing.Episode != nil {
+		displayOpts.Source = "Spotify"
+		if outputFormat != "" {
+			writeResult(export.FromEpisode(*playing.Episode, displayOpts.Source))
+			return
+		}
+		display.DisplayEpisode(*playing.Episode, playing.Position, displayOpts)
+		return
+	}
+
+	track := playing.Track
+	if track != nil {
+		client, displayOpts.Source = nowClient, "Spotify"
+	} else if prov != nil {
+		if track, err = prov.SearchTrack(ctx, playing.Query()); err == nil {
+			useServingProvider()
+		}
+	}
+	opts := displayOpts
+	if track == nil {
+		t := playing.AsTrack()
+		track, client, opts.Source = &t, nil, src.Name()
+
+		// The player's tags have no release date, popularity and so on
+		if len(opts.Fields) == 0 {
+			opts.Fields = nowTagFields
+		}
+	}
+	track = withLocalQuality(playing, track)
+	if outputFormat != "" {
+		writeResult(export.FromTrack(*track, opts.Source))
+		return
+	}
+	display.DisplayTrack(*track, client, opts)
+}
+
+// withLocalQuality fills in the track's audio quality from the file's header
+// when a local player is playing a FLAC or WAV file
+func withLocalQuality(playing *nowplaying.Playing, track *spotify.Track) *spotify.Track {
+	path := playing.LocalFile()
+	if track.Quality != nil || path == "" {
+		return track
+	}
+	q, err := audio.Probe(path)
+	if err != nil {
+		return track
+	}
+	t := *track
+	t.Quality = q
+	return &t
+}
+
+// watchNowPlaying polls the player and redraws the card when the song
+// changes, until interrupted
+func watchNowPlaying(src nowplaying.Source, tty bool) {
+	interrupt := make(chan os.Signal, 1)
+	handlesInterrupt.Store(true)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	ticker := time.NewTicker(nowPoll)
+	defer ticker.Stop()
+
+	var last string
+	for {
+		playing, err := src.Current(ctx)
+		if key := nowKey(playing, err); key != last {
+			if tty && outputFormat == "" {
+				fmt.Print("\033[H\033[2J\n")
+			}
+			showNowPlaying(src, playing, err)
+			last = key
+		}
+
+		select {
+		case <-interrupt:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// nowKey identifies a poll result, so the card is only redrawn on changes
+func nowKey(playing *nowplaying.Playing, err error) string {
+	switch {
+	case errors.Is(err, nowplaying.ErrUnavailable):
+		return "unavailable"
+	case err != nil:
+		return "error"
+	case playing == nil:
+		return "stopped"
+	}
+	return playing.Key()
+}
+
+// init registers the now command
+func init() {
+	nowCmd.Flags().StringVar(&nowBackend, "backend", "auto", "Where to read the current song: "+strings.Join(nowplaying.SourceNames, ", "))
+	nowCmd.Flags().StringVar(&nowPlayer, "player", "", "MPRIS player to read, e.g. spotify or vlc (default: the one playing)")
+	nowCmd.Flags().BoolVarP(&nowWatch, "watch", "w", false, "Stay open and redraw the card when the song changes")
+	nowCmd.Flags().DurationVar(&nowPoll, "poll", 2*time.Second, "How often --watch checks the player")
+	addSourceFlag(nowCmd)
+	addDisplayFlags(nowCmd)
+	addFormatFlags(nowCmd)
+
+	rootCmd.AddCommand(nowCmd)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// variables for opening results
+var (
+	openResult bool // --open on search and get
+	openInApp  bool // Use spotify: URIs so the desktop app opens them
+)
+
+// openCmd opens the top result without printing a card
+var openCmd = &cobra.Command{
+	Use:   "open <query>",
+	Short: "Open a track, album, or artist in Spotify or the browser",
+	Long: `Search for music and open the top result's page in the browser, or in the
+Spotify desktop app with --app`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !isOneOf(searchType, []string{"auto", "track", "album", "artist"}) {
+			fmt.Printf("Unknown search type: %s\n", searchType)
+			os.Exit(1)
+		}
+
+		loadConfig()
+
+		var err error
+		prov, err = buildProvider(cmd.Flags().Changed("source"), cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		spinner := display.NewSpinner("Fetching " + args[0] + "...")
+		spinner.Start()
+		result, err := findResult(args[0], searchType)
+		spinner.Stop()
+
+		if err != nil {
+			if errors.Is(err, provider.ErrNotFound) {
+				fmt.Printf("No results found for: %s\n", args[0])
+				os.Exit(exitNotFound)
+			}
+			fmt.Printf("Search failed: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		fmt.Printf("Opening %s\n", result.Name)
+		openLink(result.URL)
+	},
+}
+
+// findResult returns the top result for query without rendering it; auto
+// tries tracks, then albums, then artists
+func findResult(query, sType string) (*export.Result, error) {
+	var err error
+	if sType == "auto" || sType == "track" {
+		var track *spotify.Track
+		if track, err = prov.SearchTrack(ctx, query); err == nil {
+			return export.FromTrack(*track, prov.Name()), nil
+		}
+		if sType == "track" || !errors.Is(err, provider.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if sType == "auto" || sType == "album" {
+		var album *spotify.Album
+		if album, err = prov.SearchAlbum(ctx, query); err == nil {
+			return export.FromAlbum(*album, prov.Name()), nil
+		}
+		if sType == "album" || !errors.Is(err, provider.ErrNotFound) {
+			return nil, err
+		}
+	}
+	artist, err := prov.SearchArtist(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return export.FromArtist(*artist, prov.Name()), nil
+}
+
+// openLink opens url in the browser, or as a spotify: URI in the desktop
+// app when that's preferred and the link is a Spotify one
+func openLink(url string) {
+	if url == "" {
+		fmt.Fprintln(os.Stderr, "No link to open")
+		return
+	}
+
+	target := url
+	if openInApp || cfg.OpenWith == "app" {
+		if entityType, id, err := spotify.ParseID(url); err == nil && entityType != "" {
+			target = "spotify:" + entityType + ":" + id
+		}
+	}
+
+	if err := platform.OpenURL(target); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open %s: %v\n", target, err)
+	}
+}
+
+// init registers the open command
+func init() {
+	openCmd.Flags().StringVarP(&searchType, "type", "t", "auto", "Search type: track, album, artist, or auto")
+	openCmd.Flags().BoolVar(&openInApp, "app", false, "Open in the Spotify desktop app instead of the browser")
+	addSourceFlag(openCmd)
+
+	rootCmd.AddCommand(openCmd)
+}
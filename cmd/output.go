@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/pager"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/spf13/cobra"
+)
+
+// addOutputFlags adds the export format flags and the file outputs
+func addOutputFlags(c *cobra.Command) {
+	addFormatFlags(c)
+	c.Flags().StringVarP(&outputPath, "output", "o", "", "Write the output to this file instead of stdout; .html files get the HTML card")
+	c.Flags().StringVar(&htmlPath, "html", "", "Also save a self-contained HTML card to this file")
+	c.Flags().StringVar(&pngPath, "png", "", "Also save the rendered card as a PNG image")
+	c.Flags().StringVar(&copyField, "copy", "", "Copy the result's link, isrc or upc to the clipboard (works over SSH via OSC 52)")
+	c.Flags().BoolVar(&copyURL, "copy-link", false, "Copy the result's link to the clipboard (same as --copy link)")
+	c.Flags().BoolVar(&openResult, "open", false, "Open the result in the browser (or the Spotify app with open_with: app)")
+}
+
+// addFormatFlags adds only the flags that print results in an export format
+func addFormatFlags(c *cobra.Command) {
+	c.Flags().Bool("json", false, "Print the result as JSON")
+	c.Flags().Bool("yaml", false, "Print the result as YAML")
+	c.Flags().Bool("markdown", false, "Print a Markdown summary for notes and READMEs")
+	c.Flags().Bool("csv", false, "Print CSV rows (name, artist, album, isrc, duration, popularity, url)")
+	c.Flags().Bool("polybar", false, "Print a single status line with polybar color tags")
+	c.Flags().Bool("i3blocks", false, "Print full text, short text and color lines for i3blocks")
+	c.Flags().Bool("tmux", false, "Print a #[fg=...] tagged string for tmux's status-right")
+	c.Flags().Bool("porcelain", false, "Print stable key=value lines for scripts (see README)")
+	c.Flags().StringVar(&formatTmpl, "format", "", `Print each result with a Go template, e.g. "{{.Artist}} - {{.Name}}"`)
+	c.Flags().IntVar(&maxLength, "max-length", 0, "Cut status line output (polybar, i3blocks, tmux) to this many columns")
+}
+
+// setupOutputFormat sets outputFormat from the format flags, --format and
+// an .html --output file
+func setupOutputFormat(cmd *cobra.Command) {
+	var err error
+	if outputFormat, err = pickOutputFormat(cmd); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if formatTmpl != "" {
+		if outputFormat != "" {
+			fmt.Printf("--format can't be combined with --%s\n", outputFormat)
+			os.Exit(1)
+		}
+		outputFormat = "template"
+	}
+
+	if copyURL {
+		if copyField != "" && copyField != "link" {
+			fmt.Println("--copy-link can't be combined with --copy " + copyField)
+			os.Exit(1)
+		}
+		copyField = "link"
+	}
+	if copyField != "" && !isOneOf(copyField, copyFields) {
+		fmt.Printf("Unknown --copy value: %s\n", copyField)
+		fmt.Printf("Available values: %s\n", strings.Join(copyFields, ", "))
+		os.Exit(1)
+	}
+
+	// An .html --output file gets the HTML card unless a format was picked
+	if outputPath != "" && outputFormat == "" && isHTMLPath(outputPath) {
+		if htmlPath != "" {
+			fmt.Println("--html can't be combined with an .html --output file")
+			os.Exit(1)
+		}
+		outputFormat, htmlPath = "html", outputPath
+	}
+}
+
+// openOutput creates the --output file and the result writer for the
+// selected format. The returned func closes the file.
+func openOutput() func() {
+	var out io.Writer = os.Stdout
+	closeOutput := func() {}
+	if outputPath != "" && outputFormat != "html" {
+		f, err := createOutput(outputPath)
+		if err != nil {
+			fmt.Printf("Failed to create output file: %v\n", err)
+			os.Exit(1)
+		}
+		out, displayOpts.Out = f, f
+		closeOutput = func() { f.Close() }
+	}
+
+	if outputFormat == "template" {
+		var err error
+		if resultWriter, err = export.NewTemplateWriter(out, formatTmpl); err != nil {
+			fmt.Printf("Invalid --format template: %v\n", err)
+			os.Exit(1)
+		}
+	} else if outputFormat != "" && outputFormat != "html" {
+		resultWriter = export.NewWriter(out, outputFormat)
+		resultWriter.MaxLength = maxLength
+	}
+	return closeOutput
+}
+
+// startCards gets the terminal ready for printing cards: it hides the cursor
+// and sends long output through the pager. The returned func restores it.
+func startCards(tty bool) func() {
+	if outputFormat != "" || !tty {
+		return func() {}
+	}
+
+	fmt.Print("\033[?25l")
+	fmt.Printf("\n")
+
+	// Long output (lyrics, big grids) goes through the pager
+	if !noPager {
+		outputPager = pager.Start()
+	}
+
+	return func() {
+		// Move cursor up and clear the line
+		fmt.Print("\033[F\033[K\n")
+		outputPager.Stop()
+		fmt.Print("\033[?25h")
+	}
+}
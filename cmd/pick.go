@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// stdinReader reads picks, shared so input typed ahead isn't lost between
+// queries
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// canPick reports whether results can be picked from interactively
+func canPick() bool {
+	return platform.IsTerminal(os.Stdin) && platform.IsTerminal(os.Stdout)
+}
+
+// searchList shows up to limit matches as a numbered list and, in a
+// terminal, asks which one to show as a card. Auto searches list tracks,
+// then albums, then artists, whichever finds something first.
+func searchList(query, sType string, limit int) {
+	var tracks []spotify.Track
+	var albums []spotify.Album
+	var artists []spotify.Artist
+	var items []display.GridItem
+	err := provider.ErrNotFound
+
+	if sType == "track" || sType == "auto" {
+		if tracks, err = provider.ListTracks(ctx, prov, query, limit); err == nil {
+			items, sType = display.TrackGridItems(tracks), "track"
+		}
+	}
+	if (sType == "album" || sType == "auto") && errors.Is(err, provider.ErrNotFound) {
+		if albums, err = provider.ListAlbums(ctx, prov, query, limit); err == nil {
+			items, sType = display.AlbumGridItems(albums), "album"
+		}
+	}
+	if (sType == "artist" || sType == "auto") && errors.Is(err, provider.ErrNotFound) {
+		if artists, err = provider.ListArtists(ctx, prov, query, limit); err == nil {
+			items, sType = display.ArtistGridItems(artists), "artist"
+		}
+	}
+	if !isOneOf(sType, []string{"track", "album", "artist", "auto"}) {
+		err = fmt.Errorf("unknown search type: %s", sType)
+	}
+
+	if err != nil {
+		displayOpts.Spinner.Stop()
+		if errors.Is(err, provider.ErrNotFound) {
+			if sType == "auto" {
+				fmt.Printf("No results found for: %s\n", query)
+			} else {
+				fmt.Printf("No %ss found for: %s\n", sType, query)
+			}
+			exitStatus = exitNotFound
+			return
+		}
+		fmt.Printf("Search failed: %v\n", err)
+		outputPager.Stop()
+		os.Exit(exitCode(err))
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	display.DisplayList(items, displayOpts)
+	if !canPick() {
+		return
+	}
+
+	n := promptPick(len(items))
+	if n == 0 {
+		return
+	}
+
+	displayOpts.Spinner.SetMessage("Fetching " + items[n-1].Title + "...")
+	displayOpts.Spinner.Start()
+	switch sType {
+	case "track":
+		showTrack(&tracks[n-1])
+	case "album":
+		showAlbum(fullAlbum(albums[n-1]))
+	case "artist":
+		showArtist(&artists[n-1])
+	}
+}
+
+// promptPick asks for a result number, returning 0 when the user skips
+func promptPick(count int) int {
+	fmt.Print("\033[?25h")
+	defer fmt.Print("\033[?25l")
+
+	for {
+		fmt.Printf(" Show which result? [1-%d, enter to skip] ", count)
+		line, err := stdinReader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if err != nil || line == "" || line == "q" {
+			fmt.Println()
+			return 0
+		}
+		if n, convErr := strconv.Atoi(line); convErr == nil && n >= 1 && n <= count {
+			fmt.Println()
+			return n
+		}
+	}
+}
+
+// fullAlbum fetches the tracklist of a listed album when the result came
+// from Spotify, whose search results leave it out
+func fullAlbum(album spotify.Album) *spotify.Album {
+	useServingProvider()
+	if client == nil || album.ID == "" {
+		return &album
+	}
+	if full, err := client.GetAlbum(ctx, album.ID); err == nil {
+		return full
+	}
+	return &album
+}
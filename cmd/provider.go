@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// providerNames lists the accepted --source values
+var providerNames = []string{"spotify", "jamendo", "fma", "archive"}
+
+// errNoSpotifyCredentials is returned when the Spotify client ID or secret
+// isn't configured; printError expands it into setup instructions
+var errNoSpotifyCredentials = errors.New("no Spotify credentials found, run 'mufetch auth' or set MUFETCH_SPOTIFY_CLIENT_ID and MUFETCH_SPOTIFY_CLIENT_SECRET")
+
+// missingCredentials reports unconfigured credentials, exiting with the same
+// code as credentials the API rejected
+type missingCredentials struct{ error }
+
+// Is matches spotify.ErrUnauthorized
+func (missingCredentials) Is(target error) bool {
+	return target == spotify.ErrUnauthorized
+}
+
+// Unwrap returns the error describing what's missing
+func (m missingCredentials) Unwrap() error {
+	return m.error
+}
+
+// printError prints why a provider couldn't be set up or used, spelling out
+// how to get started when Spotify credentials are missing
+func printError(err error) {
+	if errors.Is(err, errNoSpotifyCredentials) {
+		fmt.Println("No Spotify credentials found!")
+		fmt.Println("Run 'mufetch auth' to set up your API credentials, or set MUFETCH_SPOTIFY_CLIENT_ID and MUFETCH_SPOTIFY_CLIENT_SECRET.")
+		return
+	}
+	fmt.Println(err)
+}
+
+// newProvider creates the named provider from configured credentials
+func newProvider(name string, cfg *config.Config) (provider.Provider, error) {
+	switch name {
+	case "spotify":
+		if cfg.Spotify.ClientID == "" || cfg.Spotify.ClientSecret == "" {
+			return nil, missingCredentials{errNoSpotifyCredentials}
+		}
+		sp := provider.NewSpotify(cfg.Spotify.ClientID, cfg.Spotify.ClientSecret)
+		sp.Client.Locale = metadataLocale()
+		sp.Client.Market = cfg.Market
+		useTokenCache(sp.Client)
+		return sp, nil
+	case "jamendo":
+		if cfg.Jamendo.ClientID == "" {
+			return nil, missingCredentials{errors.New("no Jamendo client ID found, run 'mufetch auth jamendo' (https://devportal.jamendo.com)")}
+		}
+		return provider.NewJamendo(cfg.Jamendo.ClientID), nil
+	case "fma":
+		if cfg.FMA.APIKey == "" {
+			return nil, missingCredentials{errors.New("no Free Music Archive API key found, run 'mufetch auth fma'")}
+		}
+		if cfg.FMA.APIURL == "" {
+			return nil, missingCredentials{errors.New("no Free Music Archive API URL found, set fma.api_url to a mirror of the retired API with 'mufetch auth fma'")}
+		}
+		return provider.NewFMA(cfg.FMA.APIKey, cfg.FMA.APIURL), nil
+	case "archive":
+		return provider.NewArchive(), nil
+	}
+
+	// Anything else may be a mufetch-provider-<name> plugin on $PATH
+	if plugin, err := provider.FindPlugin(name); err == nil {
+		plugin.Locale = metadataLocale()
+		return plugin, nil
+	}
+	return nil, fmt.Errorf("unknown source: %s (and no %s%s on $PATH)", name, provider.PluginPrefix, name)
+}
+
+// userClient creates a Spotify client that acts as the user, for endpoints
+// about the user's own listening. The refresh token from `auth --user` is
+// preferred over a manually set spotify.user_token.
+func userClient() (*spotify.Client, error) {
+	var user *spotify.Client
+	switch {
+	case cfg.Spotify.RefreshToken != "" && cfg.Spotify.ClientID != "":
+		user = spotify.NewRefreshingUserClient(cfg.Spotify.ClientID, cfg.Spotify.RefreshToken, func(token *spotify.UserToken) {
+			if config.FromEnv("spotify.refresh_token") {
+				return // Nowhere to save it, the environment keeps working
+			}
+			if err := config.SetRefreshToken(token.RefreshToken); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to save the new refresh token: %v\n", err)
+			}
+		})
+	case cfg.Spotify.UserToken != "":
+		user = spotify.NewUserClient(cfg.Spotify.UserToken)
+	default:
+		return nil, missingCredentials{errors.New("not signed in to Spotify, run 'mufetch auth --user'")}
+	}
+	user.Locale = metadataLocale()
+	user.Market = cfg.Market
+	return user, nil
+}
+
+// metadataLocale returns the --locale language, or the configured one, in
+// the form Spotify expects (pt-BR rather than pt_BR)
+func metadataLocale() string {
+	l := locale
+	if l == "" {
+		l = cfg.Locale
+	}
+	return strings.ReplaceAll(strings.TrimSpace(l), "_", "-")
+}
+
+// buildProvider returns the provider for --source. Without the flag it's a
+// failover chain over provider_priority when one is configured, or else the
+// configured default source.
+func buildProvider(explicit bool, cfg *config.Config) (provider.Provider, error) {
+	if explicit {
+		return newProvider(source, cfg)
+	}
+	if len(cfg.ProviderPriority) == 0 {
+		return newProvider(cfg.Source, cfg)
+	}
+
+	// Providers that aren't set up are left out of the chain
+	var chain []provider.Provider
+	var firstErr error
+	for _, name := range cfg.ProviderPriority {
+		p, err := newProvider(name, cfg)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		chain = append(chain, p)
+	}
+
+	switch len(chain) {
+	case 0:
+		return nil, firstErr
+	case 1:
+		return chain[0], nil
+	}
+	return provider.NewFailover(chain, notifyFailover), nil
+}
+
+// notifyFailover prints a notice when a provider is skipped
+func notifyFailover(from, to provider.Provider, err error) {
+	displayOpts.Spinner.Stop()
+	fmt.Fprintf(os.Stderr, "%s unavailable (%v), falling back to %s\n", from.Name(), err, to.Name())
+	displayOpts.Spinner.Start()
+}
+
+// useServingProvider points display enrichment and links at the provider
+// that answered the last lookup
+func useServingProvider() {
+	p := prov
+	if f, ok := prov.(*provider.Failover); ok {
+		p = f.Last()
+	}
+
+	client = nil
+	if sp, ok := p.(*provider.Spotify); ok {
+		client = sp.Client
+	}
+	displayOpts.Source = p.Name()
+}
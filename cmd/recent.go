@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/spf13/cobra"
+)
+
+// variables for the recent command
+var (
+	recentLimit int
+	recentUTC   bool
+)
+
+// recentCmd shows the tracks the user played last on Spotify
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "Show what you played recently on Spotify",
+	Long: `Show the tracks you played last on Spotify, newest first and grouped by day
+with local times. Needs you to sign in with 'mufetch auth --user'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if recentLimit < 1 || recentLimit > 50 {
+			fmt.Println("Limit must be between 1 and 50")
+			os.Exit(1)
+		}
+
+		loadConfig()
+
+		user, err := userClient()
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		setupListDisplay()
+		displayOpts.Spinner = display.NewSpinner("Fetching recently played tracks...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		recent, err := user.GetRecentlyPlayed(ctx, recentLimit)
+		displayOpts.Spinner.Stop()
+		if err != nil {
+			fmt.Printf("Failed to get recently played tracks: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		if len(recent.Items) == 0 {
+			fmt.Fprintln(os.Stderr, "Nothing played recently")
+			exitStatus = exitNotFound
+			return
+		}
+
+		timeline := make([]display.TimelineEntry, len(recent.Items))
+		for i, item := range recent.Items {
+			artists := make([]string, len(item.Track.Artists))
+			for j, a := range item.Track.Artists {
+				artists[j] = a.Name
+			}
+			subtitle := strings.Join(artists, ", ")
+			if item.Track.Album.Name != "" {
+				subtitle += " · " + item.Track.Album.Name
+			}
+			timeline[i] = display.TimelineEntry{
+				Time:     item.PlayedAt,
+				Title:    item.Track.Name,
+				Subtitle: subtitle,
+				URL:      item.Track.ExternalURL.URL(),
+			}
+		}
+		display.DisplayTimeline(timeline, recentUTC, displayOpts)
+	},
+}
+
+// init registers the recent command
+func init() {
+	recentCmd.Flags().IntVar(&recentLimit, "limit", 20, "Number of tracks to show (1-50)")
+	recentCmd.Flags().BoolVar(&recentUTC, "utc", false, "Show times in UTC instead of local time")
+	recentCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+
+	rootCmd.AddCommand(recentCmd)
+}
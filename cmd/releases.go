@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// variables for the releases command
+var (
+	releasesSince string // How far back to look, e.g. 30d
+	releasesRSS   bool
+	releasesAtom  bool
+	releasesHook  string // Overrides release_hook
+)
+
+// releasesCmd checks followed artists for new releases
+var releasesCmd = &cobra.Command{
+	Use:   "releases",
+	Short: "Check followed artists for new releases",
+	Long: `List albums and singles released recently by the artists added with
+'mufetch follow add'. Exits with status 6 when there is something new and 0
+when there isn't, so a cron job can act on it. --rss and --atom print a
+feed instead, for feed readers.
+
+When there is something new, the release_hook command from the config (or
+--hook) is run through the shell with the releases as JSON on stdin:
+
+  {"since": "2024-05-01", "releases": [<same keys as --json>, ...]}
+
+Each release is given to the hook once; the ones it has seen are kept in
+announced.json next to follows.json.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if releasesRSS && releasesAtom {
+			fmt.Println("--rss and --atom can't be used together")
+			os.Exit(1)
+		}
+		age, err := parseAge(releasesSince)
+		if err != nil {
+			fmt.Printf("Invalid --since: %v\n", err)
+			os.Exit(1)
+		}
+		cutoff := time.Now().Add(-age)
+
+		followed, err := followStore().Load()
+		if err != nil {
+			fmt.Printf("Failed to read followed artists: %v\n", err)
+			os.Exit(1)
+		}
+		if len(followed) == 0 && feedFormat() == "" {
+			fmt.Println("Not following anyone yet, add artists with 'mufetch follow add <artist>'")
+			return
+		}
+
+		loadConfig()
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		sp := p.(*provider.Spotify)
+
+		tty := setupListDisplay()
+		finishCards := func() {}
+		if feedFormat() == "" {
+			finishCards = startCards(tty)
+		}
+
+		displayOpts.Spinner = display.NewSpinner("")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		var fresh []spotify.Album
+		seen := make(map[string]bool)
+		for _, artist := range followed {
+			displayOpts.Spinner.SetMessage("Checking " + artist.Name + "...")
+			albums, err := sp.Client.GetAllArtistAlbums(ctx, artist.ID, "album,single")
+			if err != nil {
+				displayOpts.Spinner.Stop()
+				fmt.Printf("Failed to check %s: %v\n", artist.Name, err)
+				outputPager.Stop()
+				os.Exit(exitCode(err))
+			}
+			for _, album := range albums {
+				if !seen[album.ID] && !releaseTime(album).Before(cutoff) {
+					seen[album.ID] = true
+					fresh = append(fresh, album)
+				}
+			}
+		}
+
+		displayOpts.Spinner.Stop()
+		if format := feedFormat(); format != "" {
+			writeReleasesFeed(format, fresh)
+		} else if len(fresh) == 0 {
+			fmt.Printf("No new releases in the last %s\n", releasesSince)
+		} else {
+			display.DisplayReleases("New releases since "+cutoff.Format("2006-01-02"), fresh, displayOpts)
+		}
+		finishCards()
+		if len(fresh) == 0 {
+			return
+		}
+
+		hook := cfg.ReleaseHook
+		if cmd.Flags().Changed("hook") {
+			hook = releasesHook
+		}
+		if hook != "" {
+			announceReleases(hook, cutoff, fresh)
+		}
+		exitStatus = exitNewReleases
+	},
+}
+
+// announceReleases runs the release hook with the albums it hasn't been
+// given before, and remembers them once it succeeds so a failed hook gets
+// them again next time
+func announceReleases(hook string, cutoff time.Time, albums []spotify.Album) {
+	store := announcedStore()
+	announced, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read announced releases: %v\n", err)
+		return
+	}
+
+	var unseen []spotify.Album
+	var ids []string
+	for _, album := range albums {
+		if !announced[album.ID] {
+			unseen = append(unseen, album)
+			ids = append(ids, album.ID)
+		}
+	}
+	if len(unseen) == 0 {
+		return
+	}
+
+	if err := runReleaseHook(hook, cutoff, unseen); err != nil {
+		fmt.Fprintf(os.Stderr, "Release hook failed: %v\n", err)
+		return
+	}
+	if err := store.Add(ids...); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save announced releases: %v\n", err)
+	}
+}
+
+// releaseTime parses an album's release date at whatever precision Spotify
+// gives it; year-only dates count as the first of January
+func releaseTime(album spotify.Album) time.Time {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.ParseInLocation(layout, album.ReleaseDate, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// feedFormat returns the feed format picked with --rss or --atom, or ""
+func feedFormat() string {
+	switch {
+	case releasesRSS:
+		return "rss"
+	case releasesAtom:
+		return "atom"
+	}
+	return ""
+}
+
+// writeReleasesFeed prints the new releases as a feed
+func writeReleasesFeed(format string, albums []spotify.Album) {
+	results := make([]*export.Result, len(albums))
+	for i, album := range albums {
+		results[i] = export.FromAlbum(album, "Spotify")
+	}
+	feed := export.Feed{
+		Title:   "New releases from followed artists",
+		Link:    "https://open.spotify.com",
+		ID:      "urn:mufetch:releases",
+		Updated: time.Now(),
+	}
+	if err := export.WriteFeed(os.Stdout, format, feed, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write the feed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// releaseHookInput is the JSON a release hook reads from stdin
+type releaseHookInput struct {
+	Since    string           `json:"since"`
+	Releases []*export.Result `json:"releases"`
+}
+
+// runReleaseHook runs command through the shell with the new releases as
+// JSON on stdin. Its output goes to stderr so it can't end up in a feed.
+func runReleaseHook(command string, cutoff time.Time, albums []spotify.Album) error {
+	input := releaseHookInput{Since: cutoff.Format("2006-01-02")}
+	for _, album := range albums {
+		input.Releases = append(input.Releases, export.FromAlbum(album, "Spotify"))
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	var hook *exec.Cmd
+	if runtime.GOOS == "windows" {
+		hook = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		hook = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	hook.Stdin = bytes.NewReader(data)
+	hook.Stdout, hook.Stderr = os.Stderr, os.Stderr
+	return hook.Run()
+}
+
+// init registers the releases command
+func init() {
+	releasesCmd.Flags().StringVar(&releasesSince, "since", "30d", "How far back to look, e.g. 7d or 48h")
+	releasesCmd.Flags().BoolVar(&releasesRSS, "rss", false, "Print the new releases as an RSS feed")
+	releasesCmd.Flags().BoolVar(&releasesAtom, "atom", false, "Print the new releases as an Atom feed")
+	releasesCmd.Flags().StringVar(&releasesHook, "hook", "", "Command to run with new releases as JSON on stdin, overriding release_hook")
+	releasesCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	releasesCmd.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+
+	rootCmd.AddCommand(releasesCmd)
+}
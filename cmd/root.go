@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/ashish0kumar/mufetch/pkg/config"
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/internal/pager"
+	"github.com/ashish0kumar/mufetch/internal/platform"
 	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
 	"github.com/ashish0kumar/mufetch/pkg/spotify"
 	"github.com/spf13/cobra"
 )
@@ -15,10 +27,59 @@ var version = "dev"
 
 // variables to hold command line args and configuration
 var (
-	searchType string
-	imageSize  int
-	cfg        *config.Config
-	client     *spotify.Client
+	searchType   string
+	imageSize    int
+	fields       []string
+	noImage      bool
+	icons        bool
+	maxWidth     int
+	wrap         bool
+	full         bool
+	allTracks    bool
+	swatches     bool
+	showLyrics   bool
+	limit        int
+	grid         bool
+	noPager      bool
+	batchPath    string
+	htmlPath     string
+	outputPath   string
+	pngPath      string
+	interval     time.Duration
+	watchSeconds int
+	maxLength    int
+	formatTmpl   string
+	forceColor   bool
+	forceImage   bool
+	copyURL      bool
+	copyField    string // What --copy puts on the clipboard, see copyFields
+	renderer     string
+	dither       string
+	crop         string
+	aspect       float64
+	source       string
+	locale       string
+	logoPath     string
+	proxy        string
+	clientID     string
+	clientSecret string
+	configPath   string
+	profileName  string
+	vtOK         bool // The console interprets escape codes
+	verbose      bool
+	cfg          *config.Config
+	client       *spotify.Client
+	prov         provider.Provider
+	displayOpts  display.Options
+	outputPager  *pager.Pager
+
+	// outputFormat is the export format picked by --json, --yaml, etc.;
+	// empty renders the card
+	outputFormat string
+	resultWriter *export.Writer
+
+	// htmlResults collects results for the --html file
+	htmlResults []*export.Result
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -28,6 +89,27 @@ var rootCmd = &cobra.Command{
 	Long: `mufetch displays beautiful music information with cover art in your terminal.
 Search for tracks, albums, or artists.`,
 	Version: version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// auth is how a new profile gets its first settings
+		if name := config.Profile(); name != "" && cmd != authCmd && !isOneOf(name, config.Profiles()) {
+			fmt.Printf("Unknown profile: %s\n", name)
+			if profiles := config.Profiles(); len(profiles) > 0 {
+				fmt.Printf("Available profiles: %s\n", strings.Join(profiles, ", "))
+			}
+			os.Exit(1)
+		}
+		if verbose {
+			// Logs go to stderr, which the pager would draw over
+			slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+			noPager = true
+		}
+		if proxy != "" {
+			if err := httpclient.SetProxy(proxy); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	},
 }
 
 // searchCmd represents the search command
@@ -35,129 +117,262 @@ var searchCmd = &cobra.Command{
 	Use:   "search [query]",
 	Short: "Search for music",
 	Long:  `Search for tracks, albums, or artists and display their metadata`,
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		query := args[0]
+		var queries []string
+		var err error
+		switch {
+		case batchPath != "" && len(args) > 0:
+			fmt.Println("Pass either a query or --batch, not both")
+			os.Exit(1)
+		case batchPath != "":
+			if queries, err = readBatch(batchPath); err != nil {
+				fmt.Printf("Failed to read batch file: %v\n", err)
+				os.Exit(1)
+			}
+		case len(args) == 1:
+			queries = args
+		default:
+			fmt.Println("Missing search query")
+			os.Exit(1)
+		}
 
-		if !config.HasCredentials() {
-			fmt.Println("No Spotify credentials found!")
-			fmt.Println("Run 'mufetch auth' to set up your API credentials.")
+		loadConfig()
+
+		// Config defaults apply unless overridden by flags
+		if !cmd.Flags().Changed("type") && cfg.SearchType != "" {
+			searchType = cfg.SearchType
+		}
+		if !isOneOf(searchType, []string{"auto", "track", "album", "artist"}) {
+			fmt.Printf("Unknown search type: %s (use track, album, artist, or auto)\n", searchType)
+			os.Exit(1)
+		}
+		if cfg.Layout != "" && cfg.Layout != "card" && cfg.Layout != "grid" {
+			fmt.Printf("Unknown layout: %s (use card or grid)\n", cfg.Layout)
 			os.Exit(1)
 		}
 
-		// Load config
-		var err error
-		cfg, err = config.GetConfig()
+		// Initialize the provider, or a failover chain from provider_priority
+		prov, err = buildProvider(cmd.Flags().Changed("source"), cfg)
 		if err != nil {
-			fmt.Printf("Failed to load config: %v\n", err)
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		setupOutputFormat(cmd)
+
+		// A grid layout from the config only applies to cards
+		if !cmd.Flags().Changed("grid") && cfg.Layout == "grid" && outputFormat == "" {
+			grid = true
+		}
+		if interval != 0 && (outputFormat == "" || outputFormat == "html" || interval < time.Second) {
+			fmt.Println("--interval needs an output format such as --polybar and must be at least 1s")
+			os.Exit(1)
+		}
+		if watchSeconds < 0 || (watchSeconds > 0 && outputFormat != "") {
+			fmt.Println("--watch takes a positive number of seconds and only refreshes cards; use --interval with output formats")
+			os.Exit(1)
+		}
+		if outputFormat != "" && grid {
+			fmt.Printf("--grid can't be combined with --%s\n", outputFormat)
+			os.Exit(1)
+		}
+		listing := limit > 1 && !grid
+		if listing && outputFormat != "" {
+			fmt.Printf("--limit without --grid can't be combined with --%s\n", outputFormat)
+			os.Exit(1)
+		}
+		if listing && watchSeconds > 0 {
+			fmt.Println("--limit without --grid can't be combined with --watch")
 			os.Exit(1)
 		}
 
-		// Initialize Spotify client with credentials
-		client = spotify.NewClient(cfg.SpotifyClientID, cfg.SpotifyClientSecret)
+		if grid && !cmd.Flags().Changed("limit") {
+			limit = defaultGridLimit
+		}
+		if limit < 1 || limit > 50 {
+			fmt.Println("Limit must be between 1 and 50")
+			os.Exit(1)
+		}
 
-		fmt.Print("\033[?25l")
-		defer fmt.Print("\033[?25h")
+		tty := setupDisplay(cmd)
 
-		fmt.Printf("\n")
+		// The pick prompt has to reach the terminal instead of the pager
+		if listing && canPick() {
+			noPager = true
+		}
 
-		// Validate image size
-		if imageSize < 15 {
-			imageSize = 15
+		// Redrawn cards replace each other, so there's nothing to page
+		refresh := interval
+		if watchSeconds > 0 {
+			refresh = time.Duration(watchSeconds) * time.Second
+			noPager = true
 		}
-		if imageSize > 35 {
-			imageSize = 35
+
+		// A running daemon answers plain status-bar lookups from its cache
+		askDaemon := outputFormat != "" && outputFormat != "html" && htmlPath == "" && pngPath == "" &&
+			!grid && !listing && copyField == "" && !openResult &&
+			!cmd.Flags().Changed("source") && !cmd.Flags().Changed("locale")
+
+		closeOutput := openOutput()
+		defer closeOutput()
+		finishCards := startCards(tty)
+
+		// Animate while API calls and the image download are in flight
+		displayOpts.Spinner = display.NewSpinner("")
+		defer displayOpts.Spinner.Stop()
+
+		interrupt := make(chan os.Signal, 1)
+		if refresh > 0 {
+			handlesInterrupt.Store(true)
+			signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 		}
 
-		// Perform search
-		if searchType == "auto" {
-			searchAuto(query)
-		} else {
-			searchSpecific(query, searchType)
+	refreshLoop:
+		for {
+			if watchSeconds > 0 && tty {
+				fmt.Print("\033[H\033[2J\n")
+			}
+			htmlResults = htmlResults[:0]
+
+			for _, query := range queries {
+				lookupQuery, lookupType = query, searchType
+				displayOpts.Spinner.SetMessage("Fetching " + query + "...")
+				displayOpts.Spinner.Start()
+
+				// Perform search
+				if grid {
+					searchGrid(query, searchType, limit)
+				} else if listing {
+					searchList(query, searchType, limit)
+				} else if askDaemon && daemonSearch(query, searchType) {
+					continue
+				} else if searchType == "auto" {
+					searchAuto(query)
+				} else {
+					searchSpecific(query, searchType)
+				}
+			}
+
+			// Status bars and --watch keep the process running until
+			// interrupted
+			if refresh == 0 {
+				break
+			}
+			select {
+			case <-interrupt:
+				break refreshLoop
+			case <-time.After(refresh):
+			}
 		}
 
-		// Move cursor up and clear the line
-		fmt.Print("\033[F\033[K\n")
+		finishCards()
+		saveHTML()
 	},
 }
 
 // searchAuto performs an automatic search based on the query
 func searchAuto(query string) {
 	// Try track first
-	if result, err := client.Search(query, "track"); err == nil && len(result.Tracks.Items) > 0 {
-		display.DisplayTrack(result.Tracks.Items[0], client, imageSize)
+	track, err := prov.SearchTrack(ctx, query)
+	if err == nil {
+		showTrack(track)
 		return
 	}
 
 	// Try album
-	if result, err := client.Search(query, "album"); err == nil && len(result.Albums.Items) > 0 {
-		if album, err := client.GetAlbum(result.Albums.Items[0].ID); err == nil {
-			display.DisplayAlbum(*album, client, imageSize)
+	if errors.Is(err, provider.ErrNotFound) {
+		var album *spotify.Album
+		if album, err = prov.SearchAlbum(ctx, query); err == nil {
+			showAlbum(album)
 			return
 		}
 	}
 
 	// Try artist
-	if result, err := client.Search(query, "artist"); err == nil && len(result.Artists.Items) > 0 {
-		if artist, err := client.GetArtist(result.Artists.Items[0].ID); err == nil {
-			display.DisplayArtist(*artist, client, imageSize)
+	if errors.Is(err, provider.ErrNotFound) {
+		var artist *spotify.Artist
+		if artist, err = prov.SearchArtist(ctx, query); err == nil {
+			showArtist(artist)
 			return
 		}
 	}
 
-	fmt.Printf("No results found for: %s\n", query)
+	displayOpts.Spinner.Stop()
+	if errors.Is(err, provider.ErrNotFound) {
+		fmt.Fprintf(messageOutput(), "No results found for: %s\n", query)
+		exitStatus = exitNotFound
+		return
+	}
+	fmt.Printf("Search failed: %v\n", err)
+	outputPager.Stop()
+	os.Exit(exitCode(err))
 }
 
 // searchSpecific performs a search for a specific type (track, album, artist)
 func searchSpecific(query, sType string) {
-
-	result, err := client.Search(query, sType)
-	if err != nil {
-		fmt.Printf("Search failed: %v\n", err)
-		os.Exit(1)
-	}
+	var err error
 
 	switch sType {
 	case "track":
-		if len(result.Tracks.Items) > 0 {
-			display.DisplayTrack(result.Tracks.Items[0], client, imageSize)
-		} else {
-			fmt.Printf("No tracks found for: %s\n", query)
+		var track *spotify.Track
+		if track, err = prov.SearchTrack(ctx, query); err == nil {
+			showTrack(track)
+			return
 		}
 	case "album":
-		if len(result.Albums.Items) > 0 {
-			if album, err := client.GetAlbum(result.Albums.Items[0].ID); err == nil {
-				display.DisplayAlbum(*album, client, imageSize)
-			} else {
-				fmt.Printf("Failed to get album details: %v\n", err)
-			}
-		} else {
-			fmt.Printf("No albums found for: %s\n", query)
+		var album *spotify.Album
+		if album, err = prov.SearchAlbum(ctx, query); err == nil {
+			showAlbum(album)
+			return
 		}
 	case "artist":
-		if len(result.Artists.Items) > 0 {
-			if artist, err := client.GetArtist(result.Artists.Items[0].ID); err == nil {
-				display.DisplayArtist(*artist, client, imageSize)
-			} else {
-				fmt.Printf("Failed to get artist details: %v\n", err)
-			}
-		} else {
-			fmt.Printf("No artists found for: %s\n", query)
+		var artist *spotify.Artist
+		if artist, err = prov.SearchArtist(ctx, query); err == nil {
+			showArtist(artist)
+			return
 		}
+	default:
+		err = fmt.Errorf("unknown search type: %s", sType)
 	}
+
+	displayOpts.Spinner.Stop()
+	if errors.Is(err, provider.ErrNotFound) {
+		fmt.Fprintf(messageOutput(), "No %ss found for: %s\n", sType, query)
+		exitStatus = exitNotFound
+		return
+	}
+	fmt.Printf("Search failed: %v\n", err)
+	outputPager.Stop()
+	os.Exit(exitCode(err))
+}
+
+// isOneOf reports whether value is in the list of allowed values
+func isOneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately
 func Execute() {
-	if err := config.InitConfig(); err != nil {
-		fmt.Printf("Failed to initialize config: %v\n", err)
-		os.Exit(1)
-	}
+	vtOK = platform.EnableVT() == nil
+
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(context.Background())
+	go watchInterrupt(cancel)
+
+	// `mufetch <bookmark>` runs the saved lookup
+	rootCmd.SetArgs(expandBookmark(os.Args[1:]))
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
+	os.Exit(exitStatus)
 }
 
 // init initializes the root command and adds subcommands
@@ -165,9 +380,44 @@ func init() {
 	// Disable default completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
+	// Read the config once flags are parsed, so --config is known
+	cobra.OnInitialize(func() {
+		if err := config.InitConfig(configPath); err != nil {
+			fmt.Printf("Failed to initialize config: %v\n", err)
+			os.Exit(1)
+		}
+		if profileName == "" {
+			profileName = os.Getenv("MUFETCH_PROFILE")
+		}
+		config.UseProfile(profileName)
+	})
+
+	rootCmd.PersistentFlags().DurationVar(&httpclient.AttemptTimeout, "timeout", httpclient.Timeout, "Give up on each attempt at an API request or image download after this long")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log requests, status codes, timings and cache hits to stderr")
+	rootCmd.PersistentFlags().StringVar(&proxy, "proxy", "", "Send requests through this proxy (http://, https://, socks5://); defaults to HTTP_PROXY, HTTPS_PROXY or ALL_PROXY")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Config file to use (default ~/.config/mufetch/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&themeName, "theme", "", "Built-in color theme: "+strings.Join(display.ThemeNames, ", ")+" (default from theme.preset in the config)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Use the credentials and defaults of this profile from the config (or set MUFETCH_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&clientID, "client-id", "", "Spotify client ID to use instead of the configured one")
+	rootCmd.PersistentFlags().StringVar(&clientSecret, "client-secret", "", "Spotify client secret to use instead of the configured one")
+	rootCmd.PersistentFlags().IntVar(&httpclient.Retries, "retries", httpclient.Retries, "Times to repeat a request after a server error or dropped connection")
+
+	// Rate-limited requests wait for the API; say so instead of stalling
+	httpclient.OnRateLimit = func(wait time.Duration) {
+		displayOpts.Spinner.SetMessage(fmt.Sprintf("Rate limited, retrying in %s...", wait.Round(time.Second)))
+	}
+
 	// Flags for search command
+	addSourceFlag(searchCmd)
+	addDisplayFlags(searchCmd)
+	addOutputFlags(searchCmd)
 	searchCmd.Flags().StringVarP(&searchType, "type", "t", "auto", "Search type: track, album, artist, or auto")
-	searchCmd.Flags().IntVarP(&imageSize, "size", "s", 20, "Image size (20-50)")
+	searchCmd.Flags().IntVar(&limit, "limit", 1, "Number of results to fetch (1-50), listed to pick from or shown with --grid")
+	searchCmd.Flags().BoolVar(&grid, "grid", false, "Show several results as a grid of thumbnails")
+	searchCmd.Flags().IntVar(&watchSeconds, "watch", 0, "Clear and redraw the card every N seconds until interrupted")
+	searchCmd.Flags().DurationVar(&interval, "interval", 0, "Repeat the lookup at this interval (e.g. 30s), printing a new line each time")
+	searchCmd.Flags().StringVar(&batchPath, "batch", "", "Look up every query in a file, one per line (- for stdin)")
+	searchCmd.Flags().BoolVar(&showLyrics, "lyrics", false, "Show the opening lyrics next to track results")
 
 	rootCmd.AddCommand(searchCmd)
 }
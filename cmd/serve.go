@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/internal/daemon"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/ashish0kumar/mufetch/pkg/nowplaying"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/spf13/cobra"
+)
+
+// serveListen is the address the HTTP server listens on
+var serveListen string
+
+// serveCmd answers lookups over HTTP for dashboards and home automation
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Answer lookups over HTTP with the same JSON as --json",
+	Long: `Stay running like 'mufetch daemon' and answer lookups over HTTP:
+
+  GET /search?q=<query>&type=<track|album|artist|auto>
+  GET /track/<spotify-id|uri|url>
+  GET /now
+
+Each answers with the JSON 'mufetch search --json' prints, or with
+{"error": "..."} and a 4xx or 5xx status. Search and track answers are
+reused for --ttl and the player is checked every --poll.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if daemonPoll < time.Second {
+			fmt.Println("--poll must be at least 1s")
+			os.Exit(1)
+		}
+
+		loadConfig()
+		p, err := buildProvider(cmd.Flags().Changed("source"), cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		if !cmd.Flags().Changed("backend") {
+			nowBackend = cfg.NowBackend
+		}
+		nowPlayer = cfg.MPRISPlayer
+		src, err := newNowSource(nowBackend)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		// IDs are Spotify's, so /track always asks Spotify; without
+		// credentials it answers with the error instead
+		sp, spErr := newProvider("spotify", cfg)
+
+		l, err := net.Listen("tcp", serveListen)
+		if err != nil {
+			fmt.Printf("Failed to start the server: %v\n", err)
+			os.Exit(1)
+		}
+
+		s := &serveState{
+			daemonState: &daemonState{
+				prov:  p,
+				src:   src,
+				cache: newAnswerCache(daemonCacheSize),
+			},
+			spotify:    sp,
+			spotifyErr: spErr,
+		}
+		s.poll()
+		go func() {
+			for range time.Tick(daemonPoll) {
+				s.poll()
+				s.cache.sweep()
+			}
+		}()
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /search", s.handleSearch)
+		mux.HandleFunc("GET /track/{id...}", s.handleTrack)
+		mux.HandleFunc("GET /now", s.handleNow)
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		interrupt := make(chan os.Signal, 1)
+		handlesInterrupt.Store(true)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-interrupt
+			srv.Close()
+		}()
+
+		fmt.Printf("Listening on http://%s\n", l.Addr())
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Server failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// serveState is the daemon's state plus the Spotify provider /track uses
+type serveState struct {
+	*daemonState
+	spotify    provider.Provider
+	spotifyErr error // Why spotify couldn't be set up
+}
+
+// handleSearch answers /search?q=&type=
+func (s *serveState) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	sType := r.URL.Query().Get("type")
+	if sType == "" {
+		sType = "auto"
+	}
+	switch {
+	case query == "":
+		writeHTTPError(w, http.StatusBadRequest, "missing the q parameter")
+		return
+	case !isOneOf(sType, []string{"track", "album", "artist", "auto"}):
+		writeHTTPError(w, http.StatusBadRequest, "unknown type: "+sType)
+		return
+	}
+	writeHTTPResponse(w, s.search(query, sType))
+}
+
+// handleTrack answers /track/{id} with the Spotify track, taking a bare ID,
+// URI or link like `mufetch get`
+func (s *serveState) handleTrack(w http.ResponseWriter, r *http.Request) {
+	if _, id, err := spotifyIDType(r.PathValue("id"), "track", true); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err.Error())
+	} else {
+		writeHTTPResponse(w, s.track(id))
+	}
+}
+
+// handleNow answers /now with the song the poller last saw
+func (s *serveState) handleNow(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	resp := s.now
+	s.mu.Unlock()
+	if resp.Error != "" && resp.Code == exitError {
+		// No player to read isn't the server's fault
+		writeHTTPError(w, http.StatusServiceUnavailable, resp.Error)
+		return
+	}
+	writeHTTPResponse(w, resp)
+}
+
+// track fetches a Spotify track by ID, sharing the search cache
+func (s *serveState) track(id string) daemon.Response {
+	if s.spotify == nil {
+		return daemon.Response{Error: s.spotifyErr.Error(), Code: exitCode(s.spotifyErr)}
+	}
+	return s.cached("id\x00"+id, func() (daemon.Response, bool) {
+		track, err := s.spotify.(*provider.Spotify).Client.GetTrack(ctx, id)
+		switch {
+		case errors.Is(err, provider.ErrNotFound):
+			return daemon.Response{Error: "No track found for: " + id, Code: exitNotFound}, true
+		case err != nil:
+			return daemon.Response{Error: err.Error(), Code: exitCode(err)}, false
+		}
+		return daemon.Response{Result: export.FromTrack(*track, s.spotify.Name())}, true
+	})
+}
+
+// writeHTTPResponse writes a result as --json does, or an error with the
+// status matching its exit code
+func writeHTTPResponse(w http.ResponseWriter, resp daemon.Response) {
+	if resp.Error != "" {
+		writeHTTPError(w, httpStatus(resp.Code), resp.Error)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	export.Write(w, "json", resp.Result)
+}
+
+// writeHTTPError writes {"error": msg} with the given status
+func writeHTTPError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// httpStatus maps an exit code onto an HTTP status
+func httpStatus(code int) int {
+	switch code {
+	case exitNotFound:
+		return http.StatusNotFound
+	case exitRateLimited:
+		return http.StatusTooManyRequests
+	case exitUnauthorized, exitNetwork:
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// init registers the serve command
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "localhost:8080", "Address to listen on, e.g. :8080 for every interface")
+	serveCmd.Flags().DurationVar(&daemonPoll, "poll", 2*time.Second, "How often to check the player for the current song")
+	serveCmd.Flags().DurationVar(&daemonTTL, "ttl", 10*time.Minute, "How long search and track answers are reused before asking the API again")
+	serveCmd.Flags().StringVar(&nowBackend, "backend", "auto", "Where to read the current song: "+strings.Join(nowplaying.SourceNames, ", "))
+	addSourceFlag(serveCmd)
+
+	rootCmd.AddCommand(serveCmd)
+}
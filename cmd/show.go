@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/export"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// showTrack displays a track card, with the opening lyrics when --lyrics is
+// set, or writes it in the requested output format
+func showTrack(track *spotify.Track) {
+	useServingProvider()
+
+	result := export.FromTrack(*track, displayOpts.Source)
+	recordLookup(result)
+	if htmlPath != "" {
+		htmlResults = append(htmlResults, result)
+	}
+	if copyField != "" {
+		defer copyResult(result)
+	}
+	if openResult {
+		defer openLink(result.URL)
+	}
+	if outputFormat != "" {
+		writeResult(result)
+		return
+	}
+
+	if showLyrics {
+		displayOpts.Lyrics, displayOpts.LyricsErr = trackLyrics(track)
+	}
+	display.DisplayTrack(*track, client, displayOpts)
+}
+
+// showAlbum displays an album card or writes it in the output format
+func showAlbum(album *spotify.Album) {
+	useServingProvider()
+
+	result := export.FromAlbum(*album, displayOpts.Source)
+	recordLookup(result)
+	if htmlPath != "" {
+		htmlResults = append(htmlResults, result)
+	}
+	if copyField != "" {
+		defer copyResult(result)
+	}
+	if openResult {
+		defer openLink(result.URL)
+	}
+	if outputFormat != "" {
+		writeResult(result)
+		return
+	}
+	display.DisplayAlbum(*album, client, displayOpts)
+}
+
+// showArtist displays an artist card or writes it in the output format
+func showArtist(artist *spotify.Artist) {
+	useServingProvider()
+
+	result := export.FromArtist(*artist, displayOpts.Source)
+	recordLookup(result)
+	if htmlPath != "" {
+		htmlResults = append(htmlResults, result)
+	}
+	if copyField != "" {
+		defer copyResult(result)
+	}
+	if openResult {
+		defer openLink(result.URL)
+	}
+	if outputFormat != "" {
+		writeResult(result)
+		return
+	}
+	display.DisplayArtist(*artist, client, displayOpts)
+}
+
+// pickOutputFormat returns the export format whose flag is set, or an empty
+// string to render the card; setting several is an error
+func pickOutputFormat(cmd *cobra.Command) (string, error) {
+	var picked []string
+	for _, name := range export.FormatNames() {
+		if set, err := cmd.Flags().GetBool(name); err == nil && set {
+			picked = append(picked, name)
+		}
+	}
+
+	switch len(picked) {
+	case 0:
+		return "", nil
+	case 1:
+		return picked[0], nil
+	}
+	return "", fmt.Errorf("choose only one of --%s", strings.Join(picked, ", --"))
+}
+
+// writeResult prints a result in the selected machine-readable format
+func writeResult(result *export.Result) {
+	displayOpts.Spinner.Stop()
+	if outputFormat == "html" {
+		return // Collected for saveHTML
+	}
+	if err := resultWriter.Write(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// messageOutput is where notices like "No results found" go; stderr when
+// stdout carries exported data
+func messageOutput() io.Writer {
+	if outputFormat != "" || outputPath != "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// readBatch reads queries from path (or stdin for "-"), one per line,
+// skipping blank lines and # comments
+func readBatch(path string) ([]string, error) {
+	in := os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var queries []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		queries = append(queries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no queries in %s", path)
+	}
+	return queries, nil
+}
+
+// saveHTML writes the collected results to the --html file
+func saveHTML() {
+	if htmlPath == "" || len(htmlResults) == 0 {
+		return
+	}
+
+	f, err := createOutput(htmlPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save HTML: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err := export.WriteHTML(f, htmlResults); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save HTML: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Saved %s\n", htmlPath)
+}
+
+// createOutput creates the file at path, along with any missing parent
+// directories
+func createOutput(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+// isHTMLPath reports whether path names an HTML file
+func isHTMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".html" || ext == ".htm"
+}
+
+// copyFields are the accepted --copy values
+var copyFields = []string{"link", "isrc", "upc"}
+
+// copyResult puts the part of result picked with --copy on the clipboard
+func copyResult(result *export.Result) {
+	switch copyField {
+	case "isrc":
+		copyText("ISRC", result.ISRC)
+	case "upc":
+		copyText("UPC", result.UPC)
+	default:
+		copyText("link", result.URL)
+	}
+}
+
+// copyText puts text on the clipboard: through the terminal with OSC 52
+// when one is attached, and with the native clipboard tool unless over SSH.
+// name says what it is in messages.
+func copyText(name, text string) {
+	if text == "" {
+		fmt.Fprintf(os.Stderr, "No %s to copy\n", name)
+		return
+	}
+
+	copied := false
+	if platform.IsTerminal(os.Stderr) {
+		copied = platform.WriteOSC52(os.Stderr, text) == nil
+	}
+	if os.Getenv("SSH_CONNECTION") == "" {
+		copied = platform.CopyToClipboard(text) == nil || copied
+	}
+
+	if !copied {
+		fmt.Fprintf(os.Stderr, "Failed to copy %s: no terminal or clipboard tool available\n", name)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Copied %s\n", text)
+}
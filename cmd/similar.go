@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/lastfm"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// similarLimit is the number of similar artists listed
+var similarLimit int
+
+// similarCmd lists artists similar to an artist
+var similarCmd = &cobra.Command{
+	Use:   `similar "<artist>"`,
+	Short: "List artists similar to an artist",
+	Long: `List related artists with their popularity and genres. Spotify's related
+artists are used where your app can still access them; otherwise similar
+artists come from Last.fm, which needs a key set with 'mufetch auth lastfm'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if similarLimit < 1 || similarLimit > 50 {
+			fmt.Println("Limit must be between 1 and 50")
+			os.Exit(1)
+		}
+
+		loadConfig()
+
+		// Artists are found and filled in on Spotify
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		sp := p.(*provider.Spotify)
+
+		tty := setupListDisplay()
+		finishCards := startCards(tty)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching " + args[0] + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		artist, err := findArtist(sp, args[0])
+		var similar []spotify.Artist
+		if err == nil {
+			similar, err = similarArtists(sp, artist)
+		}
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, provider.ErrNotFound) || errors.Is(err, lastfm.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No similar artists found for: %s\n", args[0])
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Failed to get similar artists: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		if len(similar) > similarLimit {
+			similar = similar[:similarLimit]
+		}
+		display.DisplaySimilar(*artist, similar, displayOpts)
+		finishCards()
+	},
+}
+
+// similarArtists returns Spotify's related artists, falling back to
+// Last.fm's similar artists matched back to Spotify
+func similarArtists(sp *provider.Spotify, artist *spotify.Artist) ([]spotify.Artist, error) {
+	related, err := sp.Client.GetRelatedArtists(ctx, artist.ID)
+	if err == nil && len(related.Artists) > 0 {
+		return related.Artists, nil
+	}
+	if err == nil {
+		err = provider.ErrNotFound
+	}
+	if exitCode(err) == exitNetwork || exitCode(err) == exitRateLimited {
+		return nil, err
+	}
+
+	if cfg.LastFM.APIKey == "" {
+		return nil, fmt.Errorf("%w\nSpotify no longer serves related artists to this app; run 'mufetch auth lastfm' to use Last.fm (https://www.last.fm/api/account/create)", err)
+	}
+
+	displayOpts.Spinner.SetMessage("Fetching similar artists from Last.fm...")
+	found, err := lastfm.NewClient(cfg.LastFM.APIKey).SimilarArtists(ctx, artist.Name, similarLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match names back to Spotify for genres and popularity, a few at a
+	// time to stay clear of the rate limit
+	similar := make([]spotify.Artist, len(found))
+	slots := make(chan struct{}, 4)
+	var wg sync.WaitGroup
+	for i, s := range found {
+		similar[i] = spotify.Artist{Name: s.Name, ExternalURL: spotify.ExternalURL{Web: s.URL}}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			result, err := sp.Client.Search(ctx, name, "artist")
+			if err == nil && len(result.Artists.Items) > 0 && strings.EqualFold(result.Artists.Items[0].Name, name) {
+				similar[i] = result.Artists.Items[0]
+			}
+		}(i, s.Name)
+	}
+	wg.Wait()
+	return similar, nil
+}
+
+// init registers the similar command
+func init() {
+	similarCmd.Flags().IntVar(&similarLimit, "limit", 20, "Number of similar artists to list (1-50)")
+	similarCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	similarCmd.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+
+	rootCmd.AddCommand(similarCmd)
+}
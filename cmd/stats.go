@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/spf13/cobra"
+)
+
+// statsGroups are the release types included in the stats
+var statsGroups []string
+
+// statsCmd summarizes an artist's discography
+var statsCmd = &cobra.Command{
+	Use:   `stats "<artist>"`,
+	Short: "Summarize an artist's discography",
+	Long: `Aggregate an artist's releases: counts by type, total tracks and running
+time, average track length and popularity, first and latest releases, and
+the artists they collaborate with most. The artist can be a name or a
+Spotify ID or link.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, g := range statsGroups {
+			if !isOneOf(g, []string{"album", "single", "compilation", "appears_on"}) {
+				fmt.Printf("Unknown release type: %s\n", g)
+				fmt.Println("Available types: album, single, compilation, appears_on")
+				os.Exit(1)
+			}
+		}
+
+		loadConfig()
+
+		// Discographies come from Spotify's artist albums endpoint
+		p, err := newProvider("spotify", cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+		sp := p.(*provider.Spotify)
+
+		tty := setupListDisplay()
+		finishCards := startCards(tty)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching " + args[0] + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		artist, err := findArtist(sp, args[0])
+		var albums []spotify.Album
+		if err == nil {
+			albums, err = sp.Client.GetAllArtistAlbums(ctx, artist.ID, strings.Join(statsGroups, ","))
+		}
+		if err == nil {
+			displayOpts.Spinner.SetMessage(fmt.Sprintf("Fetching %d releases...", len(albums)))
+			albums, err = fullAlbums(sp.Client, albums)
+		}
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			if errors.Is(err, provider.ErrNotFound) {
+				fmt.Fprintf(os.Stderr, "No artists found for: %s\n", args[0])
+				finishCards()
+				exitStatus = exitNotFound
+				return
+			}
+			fmt.Printf("Failed to get stats: %v\n", err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		display.DisplayArtistStats(*artist, albums, displayOpts)
+		finishCards()
+	},
+}
+
+// fullAlbums fetches the full details and tracklists of listed albums,
+// twenty per request through Spotify's batch endpoint
+func fullAlbums(c *spotify.Client, albums []spotify.Album) ([]spotify.Album, error) {
+	ids := make([]string, len(albums))
+	for i, a := range albums {
+		ids[i] = a.ID
+	}
+	found, err := c.GetAlbums(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	full := make([]spotify.Album, 0, len(found))
+	for i, album := range found {
+		// Keep the listed copy of anything that vanished in between
+		if album == nil {
+			album = &albums[i]
+		}
+		// Only the artist's listing says how the album relates to them
+		a := *album
+		a.AlbumGroup = albums[i].AlbumGroup
+		full = append(full, a)
+	}
+	return full, nil
+}
+
+// init registers the stats command
+func init() {
+	statsCmd.Flags().StringSliceVar(&statsGroups, "include", []string{"album", "single", "compilation"}, "Release types to include: album, single, compilation, appears_on")
+	statsCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep colors and links even when output is piped")
+	statsCmd.Flags().BoolVar(&noPager, "no-pager", false, "Never send long output through the pager")
+
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/config"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+)
+
+// themeName is the built-in theme picked with --theme
+var themeName string
+
+// buildTheme applies the config's color overrides on top of the built-in
+// theme picked with --theme or theme.preset
+func buildTheme(tc config.ThemeConfig) (*display.Theme, error) {
+	name := tc.Preset
+	if themeName != "" {
+		name = themeName
+	}
+	theme, err := display.LookupTheme(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tc.ChipColors) > 0 {
+		theme.ChipColors = make([]string, len(tc.ChipColors))
+		for i, spec := range tc.ChipColors {
+			color, err := display.ParseColor(spec, true)
+			if err != nil {
+				return nil, err
+			}
+			theme.ChipColors[i] = color
+		}
+	}
+
+	if len(tc.Fields) > 0 {
+		// Overrides are added to the preset's field colors
+		fieldColors := make(map[string]string, len(theme.FieldColors)+len(tc.Fields))
+		for key, color := range theme.FieldColors {
+			fieldColors[key] = color
+		}
+		theme.FieldColors = fieldColors
+		for key, spec := range tc.Fields {
+			if !display.IsValidField(key) {
+				return nil, fmt.Errorf("unknown field %q in theme colors", key)
+			}
+			color, err := display.ParseColor(spec, false)
+			if err != nil {
+				return nil, err
+			}
+			theme.FieldColors[key] = color
+		}
+	}
+
+	for _, style := range []string{tc.Frame, tc.ArtFrame} {
+		if style != "" && !isOneOf(style, display.FrameNames) {
+			return nil, fmt.Errorf("unknown frame style %q (use %s)", style, strings.Join(display.FrameNames, ", "))
+		}
+	}
+	theme.Frame = tc.Frame
+	theme.ArtFrame = tc.ArtFrame
+
+	for _, status := range []struct {
+		spec  string
+		color *string
+	}{{tc.Good, &theme.Good}, {tc.Bad, &theme.Bad}} {
+		if status.spec == "" {
+			continue
+		}
+		color, err := display.ParseColor(status.spec, false)
+		if err != nil {
+			return nil, err
+		}
+		*status.color = color
+	}
+
+	return &theme, nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/cache"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// tokenMargin is how long before its expiry a saved token stops being used,
+// so it doesn't run out mid-lookup
+const tokenMargin = time.Minute
+
+// savedToken is a client-credentials token kept between runs
+type savedToken struct {
+	App         string    `json:"app"` // See tokenApp
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// tokenPath returns where the token is kept, next to the other caches
+func tokenPath() (string, error) {
+	c, err := cache.Default()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(c.Dir, "token.json"), nil
+}
+
+// useTokenCache starts c with the token saved by an earlier run, if it's
+// for the same app and still valid, and saves the tokens c fetches. Status
+// bars that run mufetch every few seconds skip the token exchange this way.
+func useTokenCache(c *spotify.Client) {
+	path, err := tokenPath()
+	if err != nil {
+		return
+	}
+
+	var saved savedToken
+	if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &saved) == nil {
+		if saved.App == tokenApp(c) && time.Until(saved.Expiry) > tokenMargin {
+			c.AccessToken = saved.AccessToken
+			c.TokenExpiry = saved.Expiry.Add(-tokenMargin)
+			slog.Debug("reusing saved access token", "expires", saved.Expiry.Format(time.RFC3339))
+		}
+	}
+
+	c.OnToken = func(accessToken string, expiry time.Time) {
+		saveToken(path, savedToken{App: tokenApp(c), AccessToken: accessToken, Expiry: expiry})
+	}
+}
+
+// tokenApp identifies the credentials a token was issued for without
+// storing them, so changing either the client ID or secret drops the token
+func tokenApp(c *spotify.Client) string {
+	sum := sha256.Sum256([]byte(c.ClientID + ":" + c.ClientSecret))
+	return hex.EncodeToString(sum[:8])
+}
+
+// saveToken writes the token readable only by the user. Failing to save
+// only costs the next run a token exchange, so errors are ignored.
+func saveToken(path string, token savedToken) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/spf13/cobra"
+)
+
+// variables for the top command
+var (
+	topRange string
+	topLimit int
+)
+
+// topRanges maps --range to Spotify's time_range values
+var topRanges = map[string]string{
+	"short":  "short_term",  // About the last 4 weeks
+	"medium": "medium_term", // About the last 6 months
+	"long":   "long_term",   // About the last year
+}
+
+// topCmd shows the user's most played artists or tracks
+var topCmd = &cobra.Command{
+	Use:       "top [artists|tracks]",
+	Short:     "Show your most played artists or tracks",
+	Long:      `Show your most played artists (the default) or tracks on Spotify as a grid with small art. Needs you to sign in with 'mufetch auth --user'.`,
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"artists", "tracks"},
+	Run: func(cmd *cobra.Command, args []string) {
+		kind := "artists"
+		if len(args) == 1 {
+			kind = args[0]
+		}
+
+		timeRange, ok := topRanges[topRange]
+		if !ok {
+			fmt.Printf("Unknown range: %s\n", topRange)
+			fmt.Println("Available ranges: short (4 weeks), medium (6 months), long (1 year)")
+			os.Exit(1)
+		}
+		if topLimit < 1 || topLimit > 50 {
+			fmt.Println("Limit must be between 1 and 50")
+			os.Exit(1)
+		}
+
+		loadConfig()
+
+		user, err := userClient()
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		tty := setupDisplay(cmd)
+		finishCards := startCards(tty)
+
+		displayOpts.Spinner = display.NewSpinner("Fetching your top " + kind + "...")
+		displayOpts.Spinner.Start()
+		defer displayOpts.Spinner.Stop()
+
+		var items []display.GridItem
+		if kind == "tracks" {
+			page, e := user.GetTopTracks(ctx, timeRange, topLimit)
+			if err = e; err == nil {
+				items = display.TrackGridItems(page.Items)
+			}
+		} else {
+			page, e := user.GetTopArtists(ctx, timeRange, topLimit)
+			if err = e; err == nil {
+				items = display.ArtistGridItems(page.Items)
+			}
+		}
+		if err != nil {
+			displayOpts.Spinner.Stop()
+			fmt.Printf("Failed to get your top %s: %v\n", kind, err)
+			outputPager.Stop()
+			os.Exit(exitCode(err))
+		}
+
+		if len(items) == 0 {
+			displayOpts.Spinner.Stop()
+			fmt.Fprintf(os.Stderr, "Not enough listening history for top %s yet\n", kind)
+			finishCards()
+			exitStatus = exitNotFound
+			return
+		}
+
+		display.DisplayGrid(items, displayOpts)
+		finishCards()
+	},
+}
+
+// init registers the top command
+func init() {
+	topCmd.Flags().StringVar(&topRange, "range", "medium", "Time range: short (4 weeks), medium (6 months), or long (1 year)")
+	topCmd.Flags().IntVar(&topLimit, "limit", 10, "Number of items to show (1-50)")
+	addDisplayFlags(topCmd)
+
+	rootCmd.AddCommand(topCmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ashish0kumar/mufetch/internal/tui"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd starts the interactive browser
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse music interactively",
+	Long: `Open a full-screen browser: search for tracks, albums, or artists, see each
+result's card, and move from artists to albums to tracks with the keyboard`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+
+		var err error
+		prov, err = buildProvider(cmd.Flags().Changed("source"), cfg)
+		if err != nil {
+			printError(err)
+			os.Exit(exitCode(err))
+		}
+
+		if !setupDisplay(cmd) {
+			fmt.Println("mufetch tui needs an interactive terminal")
+			os.Exit(1)
+		}
+
+		// Graphics protocols chafa may pick can't be redrawn in place
+		if displayOpts.Renderer == display.RendererAuto {
+			displayOpts.Renderer = display.RendererTrueColor
+		}
+
+		useServingProvider()
+		if err := tui.Run(prov, client, displayOpts); err != nil {
+			fmt.Printf("TUI failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// init registers the tui command
+func init() {
+	addSourceFlag(tuiCmd)
+	addDisplayFlags(tuiCmd)
+
+	rootCmd.AddCommand(tuiCmd)
+}
@@ -0,0 +1,103 @@
+// Package bookmark stores named shortcuts to lookups, so `mufetch fav` can
+// stand in for a long query or a Spotify link.
+package bookmark
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Bookmark is a named lookup: a search query or a Spotify ID, URI or link
+type Bookmark struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Type   string `json:"type,omitempty"` // Search or ID type; empty means auto
+}
+
+// Store reads and writes bookmarks as a JSON file
+type Store struct {
+	Path string
+}
+
+// NewStore creates a store for bookmarks.json in dir
+func NewStore(dir string) *Store {
+	return &Store{Path: filepath.Join(dir, "bookmarks.json")}
+}
+
+// Load returns every bookmark sorted by name. A missing file means there
+// are none yet.
+func (s *Store) Load() ([]Bookmark, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []Bookmark
+	if err := json.Unmarshal(data, &bookmarks); err != nil {
+		return nil, err
+	}
+	sort.Slice(bookmarks, func(i, j int) bool { return bookmarks[i].Name < bookmarks[j].Name })
+	return bookmarks, nil
+}
+
+// Get returns the bookmark with a name, or nil if there's none
+func (s *Store) Get(name string) (*Bookmark, error) {
+	bookmarks, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bookmarks {
+		if b.Name == name {
+			return &b, nil
+		}
+	}
+	return nil, nil
+}
+
+// Set saves a bookmark, replacing any with the same name
+func (s *Store) Set(b Bookmark) error {
+	bookmarks, err := s.Load()
+	if err != nil {
+		return err
+	}
+	for i := range bookmarks {
+		if bookmarks[i].Name == b.Name {
+			bookmarks[i] = b
+			return s.write(bookmarks)
+		}
+	}
+	return s.write(append(bookmarks, b))
+}
+
+// Remove deletes a bookmark, reporting false if it didn't exist
+func (s *Store) Remove(name string) (bool, error) {
+	bookmarks, err := s.Load()
+	if err != nil {
+		return false, err
+	}
+	for i, b := range bookmarks {
+		if b.Name == name {
+			return true, s.write(append(bookmarks[:i], bookmarks[i+1:]...))
+		}
+	}
+	return false, nil
+}
+
+// write replaces the file with bookmarks
+func (s *Store) write(bookmarks []Bookmark) error {
+	data, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
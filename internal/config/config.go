@@ -0,0 +1,339 @@
+// Package config loads and writes the mufetch config file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds provider credentials and display preferences
+type Config struct {
+	Spotify           SpotifyConfig `mapstructure:"spotify"`
+	Jamendo           JamendoConfig `mapstructure:"jamendo"`
+	FMA               FMAConfig     `mapstructure:"fma"`
+	LastFM            LastFMConfig  `mapstructure:"lastfm"`
+	SecretStore       string        `mapstructure:"secret_store"` // config or keyring
+	ProviderPriority  []string      `mapstructure:"provider_priority"`
+	Source            string        `mapstructure:"source"` // Default --source
+	Locale            string        `mapstructure:"locale"`
+	FormatLocale      string        `mapstructure:"format_locale"` // Dates and counts; empty follows LC_ALL, LC_TIME or LANG
+	Market            string        `mapstructure:"market"`        // Country for the markets field, top tracks and releases
+	Fields            []string      `mapstructure:"fields"`
+	ImageSize         int           `mapstructure:"image_size"`
+	Renderer          string        `mapstructure:"renderer"`
+	Dither            string        `mapstructure:"dither"`
+	Crop              string        `mapstructure:"crop"`
+	Aspect            float64       `mapstructure:"aspect"`      // Cell height/width; 0 measures the terminal
+	Layout            string        `mapstructure:"layout"`      // card or grid, for search results
+	SearchType        string        `mapstructure:"search_type"` // Default --type for search
+	NoImage           bool          `mapstructure:"no_image"`
+	Icons             bool          `mapstructure:"icons"`
+	MaxWidth          int           `mapstructure:"max_width"`
+	Wrap              bool          `mapstructure:"wrap"`
+	Swatches          bool          `mapstructure:"swatches"`
+	LyricsProvider    string        `mapstructure:"lyrics_provider"`
+	LyricsURL         string        `mapstructure:"lyrics_url"`
+	NowBackend        string        `mapstructure:"now_backend"`
+	MPRISPlayer       string        `mapstructure:"mpris_player"`
+	MPDHost           string        `mapstructure:"mpd_host"`
+	History           bool          `mapstructure:"history"`
+	OpenWith          string        `mapstructure:"open_with"`
+	ReleaseHook       string        `mapstructure:"release_hook"`        // Run with new releases as JSON on stdin
+	ImageCacheSize    int           `mapstructure:"image_cache_size"`    // MiB; 0 turns the image cache off
+	ResponseCacheSize int           `mapstructure:"response_cache_size"` // MiB; 0 turns the response cache off
+	Theme             ThemeConfig   `mapstructure:"theme"`
+}
+
+// SpotifyConfig is the spotify section: app credentials, plus the user
+// sign-in from `auth --user` or a hand-set access token
+type SpotifyConfig struct {
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	RefreshToken string `mapstructure:"refresh_token"`
+	UserToken    string `mapstructure:"user_token"`
+}
+
+// JamendoConfig is the jamendo section
+type JamendoConfig struct {
+	ClientID string `mapstructure:"client_id"`
+}
+
+// FMAConfig is the fma section; APIURL is required and points at a mirror
+// of the retired public API
+type FMAConfig struct {
+	APIKey string `mapstructure:"api_key"`
+	APIURL string `mapstructure:"api_url"`
+}
+
+// LastFMConfig is the lastfm section
+type LastFMConfig struct {
+	APIKey string `mapstructure:"api_key"`
+}
+
+// legacyKeys maps each provider setting to the top-level key it used to
+// be stored under, still read from older config files
+var legacyKeys = map[string]string{
+	"spotify.client_id":     "spotify_client_id",
+	"spotify.client_secret": "spotify_client_secret",
+	"spotify.refresh_token": "spotify_refresh_token",
+	"spotify.user_token":    "spotify_user_token",
+	"jamendo.client_id":     "jamendo_client_id",
+	"fma.api_key":           "fma_api_key",
+	"fma.api_url":           "fma_api_url",
+	"lastfm.api_key":        "lastfm_api_key",
+}
+
+// ThemeConfig holds user color overrides (names, #rrggbb or 0-255)
+type ThemeConfig struct {
+	Preset     string            `mapstructure:"preset"` // Built-in theme the rest apply on top of
+	ChipColors []string          `mapstructure:"chip_colors"`
+	Fields     map[string]string `mapstructure:"fields"`
+	Frame      string            `mapstructure:"frame"`
+	ArtFrame   string            `mapstructure:"art_frame"`
+	Good       string            `mapstructure:"good"` // Color for good news like "available"
+	Bad        string            `mapstructure:"bad"`  // Color for bad news like "explicit"
+}
+
+// Dir returns the config directory, ~/.config/mufetch or %APPDATA%\mufetch
+// on Windows
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	legacy := filepath.Join(home, ".config", "mufetch")
+	if runtime.GOOS != "windows" {
+		return legacy, nil
+	}
+
+	// %APPDATA%\mufetch, unless an older version already set up ~/.config
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy, nil
+	}
+	appData, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appData, "mufetch"), nil
+}
+
+// InitConfig sets default values and reads the config file, if there is
+// one. Without a file mufetch runs from defaults and MUFETCH_ environment
+// variables; the file is only created when something is saved to it. A
+// non-empty path replaces ~/.config/mufetch/config.yaml.
+func InitConfig(path string) error {
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+
+	// The file holds API secrets and the refresh token
+	viper.SetConfigPermissions(0600)
+
+	// Set default empty values for credentials
+	viper.SetDefault("spotify.client_id", "")
+	viper.SetDefault("spotify.client_secret", "")
+	viper.SetDefault("spotify.refresh_token", "")
+	viper.SetDefault("spotify.user_token", "")
+	viper.SetDefault("jamendo.client_id", "")
+	viper.SetDefault("fma.api_key", "")
+	viper.SetDefault("fma.api_url", "")
+	viper.SetDefault("lastfm.api_key", "")
+	viper.SetDefault("secret_store", StoreConfig)
+	viper.SetDefault("no_image", false)
+	viper.SetDefault("image_size", 20)
+	viper.SetDefault("renderer", "auto")
+	viper.SetDefault("dither", "")
+	viper.SetDefault("crop", "center")
+	viper.SetDefault("layout", "card")
+	viper.SetDefault("search_type", "auto")
+	viper.SetDefault("source", "spotify")
+	viper.SetDefault("icons", false)
+	viper.SetDefault("max_width", 0)
+	viper.SetDefault("wrap", false)
+	viper.SetDefault("swatches", false)
+	viper.SetDefault("lyrics_provider", "lrclib")
+	viper.SetDefault("lyrics_url", "")
+	viper.SetDefault("now_backend", "auto")
+	viper.SetDefault("mpris_player", "")
+	viper.SetDefault("mpd_host", "")
+	viper.SetDefault("history", true)
+	viper.SetDefault("open_with", "browser")
+	viper.SetDefault("release_hook", "")
+	viper.SetDefault("locale", "")
+	viper.SetDefault("market", "US")
+	viper.SetDefault("format_locale", "")
+	viper.SetDefault("image_cache_size", 100)
+	viper.SetDefault("response_cache_size", 20)
+
+	configDir, err := Dir()
+	if err == nil {
+		// Create config directory in user's home/.config/mufetch for history
+		// and bookmarks; a read-only home just means they can't be saved
+		os.MkdirAll(configDir, 0755)
+	}
+
+	switch {
+	case path != "":
+		viper.SetConfigFile(path)
+	case err == nil:
+		viper.AddConfigPath(configDir)
+	default:
+		return nil // No home directory, e.g. in a container
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return nil
+		}
+		if path != "" && errors.Is(err, fs.ErrNotExist) {
+			return nil // Created on the first save
+		}
+		return err
+	}
+
+	// Settings from before the provider sections move into them; the next
+	// write saves them there
+	for key, legacy := range legacyKeys {
+		if viper.GetString(key) == "" && viper.GetString(legacy) != "" {
+			viper.Set(key, viper.GetString(legacy))
+		}
+	}
+
+	return nil
+}
+
+// profile is the profile chosen with UseProfile, if any
+var profile string
+
+// UseProfile makes GetConfig read, and the Set functions write, the
+// profiles.<name> section, whose settings override the top-level ones
+func UseProfile(name string) {
+	profile = strings.ToLower(name) // Viper keys are case-insensitive
+}
+
+// Profile returns the name passed to UseProfile
+func Profile() string {
+	return profile
+}
+
+// Profiles lists the profiles defined in the config file
+func Profiles() []string {
+	var names []string
+	for name := range profileSettings() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileSettings returns the profiles section. AllSettings is needed to
+// see both the file and values set since it was read: viper.Get would
+// return only the latter once one of them is set.
+func profileSettings() map[string]any {
+	profiles, _ := viper.AllSettings()["profiles"].(map[string]any)
+	return profiles
+}
+
+// profilePrefix is the key prefix of the profile's section, or "" without
+// a profile
+func profilePrefix() string {
+	if profile == "" {
+		return ""
+	}
+	return "profiles." + profile + "."
+}
+
+// GetConfig unmarshals configuration into Config struct
+func GetConfig() (*Config, error) {
+	var config Config
+	if err := viper.Unmarshal(&config); err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		// Decoding over the top-level settings replaces only the keys the
+		// profile sets
+		if settings, ok := profileSettings()[profile].(map[string]any); ok {
+			sub := viper.New()
+			sub.MergeConfigMap(settings)
+			if err := sub.Unmarshal(&config); err != nil {
+				return nil, fmt.Errorf("profile %s: %w", profile, err)
+			}
+		}
+	}
+	if err := loadSecrets(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SetProvider saves settings into a provider's section of the config file,
+// e.g. SetProvider("lastfm", map[string]string{"api_key": key}), within the
+// current profile. Secrets go to the OS keyring instead when secret_store is
+// keyring.
+func SetProvider(name string, settings map[string]string) error {
+	for key, value := range settings {
+		key = profilePrefix() + name + "." + key
+		saved, err := saveSecret(key, value)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			viper.Set(key, value)
+		}
+	}
+	return save()
+}
+
+// save writes the config file, creating it on the first save. Files from
+// older versions that anyone could read are made private to the user.
+func save() error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		dir, err := Dir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(dir, "config.yaml")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := viper.WriteConfigAs(path); err != nil {
+		return err
+	}
+	viper.SetConfigFile(path)
+	return os.Chmod(path, 0600)
+}
+
+// SetCredentials saves Spotify API credentials to config file
+func SetCredentials(clientID, clientSecret string) error {
+	return SetProvider("spotify", map[string]string{"client_id": clientID, "client_secret": clientSecret})
+}
+
+// SetRefreshToken saves the Spotify refresh token from `auth --user`
+func SetRefreshToken(refreshToken string) error {
+	return SetProvider("spotify", map[string]string{"refresh_token": refreshToken})
+}
+
+// HasCredentials checks if valid Spotify credentials are configured
+func HasCredentials() bool {
+	config, err := GetConfig()
+	if err != nil {
+		return false
+	}
+	return config.Spotify.ClientID != "" && config.Spotify.ClientSecret != ""
+}
+
+// init initializes the viper configuration
+func init() {
+	viper.AutomaticEnv()
+	viper.SetEnvPrefix("MUFETCH") // MUFETCH_SPOTIFY_CLIENT_ID, MUFETCH_SPOTIFY_CLIENT_SECRET
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+)
+
+// Values for secret_store
+const (
+	StoreConfig  = "config"  // Plaintext in config.yaml
+	StoreKeyring = "keyring" // Keychain, Secret Service or Windows Credential Manager
+)
+
+// keyringService names mufetch's entries in the OS keyring
+const keyringService = "mufetch"
+
+// secretKeys are the settings kept in the OS keyring when secret_store is
+// keyring; the rest stay in the config file
+var secretKeys = []string{
+	"spotify.client_secret",
+	"spotify.refresh_token",
+	"spotify.user_token",
+	"fma.api_key",
+	"lastfm.api_key",
+}
+
+// UsesKeyring reports whether secrets are stored in the OS keyring
+func UsesKeyring() bool {
+	return viper.GetString("secret_store") == StoreKeyring
+}
+
+// KeyringAvailable checks that the OS keyring can be reached, e.g. that a
+// Secret Service is running on Linux
+func KeyringAvailable() error {
+	_, err := keyring.Get(keyringService, secretKeys[0])
+	if err == nil || errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// UseKeyring switches secret_store to keyring and moves the secrets saved
+// in the config file, including every profile's, into it. Keyring entries
+// are named after the setting, e.g. profiles.work.spotify.client_secret.
+func UseKeyring() error {
+	if err := KeyringAvailable(); err != nil {
+		return fmt.Errorf("the OS keyring isn't available: %w", err)
+	}
+	prefixes := []string{""}
+	for _, name := range Profiles() {
+		prefixes = append(prefixes, "profiles."+name+".")
+	}
+	for _, prefix := range prefixes {
+		for _, key := range secretKeys {
+			key = prefix + key
+
+			// Values from the environment aren't ours to save
+			value := viper.GetString(key)
+			if value == "" || (prefix == "" && FromEnv(key)) {
+				continue
+			}
+			if err := keyring.Set(keyringService, key, value); err != nil {
+				return fmt.Errorf("failed to save %s to the keyring: %w", key, err)
+			}
+			viper.Set(key, "")
+			if legacy := legacyKeys[key]; viper.InConfig(legacy) {
+				viper.Set(legacy, "")
+			}
+		}
+	}
+	viper.Set("secret_store", StoreKeyring)
+	return save()
+}
+
+// saveSecret stores a secret setting in the keyring and blanks it in the
+// config, reporting false for settings that belong in the config file
+func saveSecret(key, value string) (bool, error) {
+	if !UsesKeyring() || !isSecret(strings.TrimPrefix(key, profilePrefix())) {
+		return false, nil
+	}
+	if err := keyring.Set(keyringService, key, value); err != nil {
+		return true, fmt.Errorf("failed to save %s to the keyring: %w", key, err)
+	}
+	viper.Set(key, "")
+	return true, nil
+}
+
+// loadSecrets fills secrets missing from the config file and environment
+// from the keyring, preferring the current profile's
+func loadSecrets(c *Config) error {
+	if !UsesKeyring() {
+		return nil
+	}
+	fields := map[string]*string{
+		"spotify.client_secret": &c.Spotify.ClientSecret,
+		"spotify.refresh_token": &c.Spotify.RefreshToken,
+		"spotify.user_token":    &c.Spotify.UserToken,
+		"fma.api_key":           &c.FMA.APIKey,
+		"lastfm.api_key":        &c.LastFM.APIKey,
+	}
+	for key, field := range fields {
+		if *field != "" {
+			continue
+		}
+		value, err := keyringValue(profilePrefix() + key)
+		if err == nil && value == "" && profile != "" {
+			value, err = keyringValue(key)
+		}
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// keyringValue reads a setting from the keyring, "" if it isn't there
+func keyringValue(key string) (string, error) {
+	value, err := keyring.Get(keyringService, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from the keyring: %w", key, err)
+	}
+	return value, nil
+}
+
+// isSecret reports whether key is one of secretKeys
+func isSecret(key string) bool {
+	for _, k := range secretKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// FromEnv reports whether a setting like spotify.client_id comes from its
+// MUFETCH_ environment variable rather than the config file
+func FromEnv(key string) bool {
+	return os.Getenv("MUFETCH_"+strings.ToUpper(strings.ReplaceAll(key, ".", "_"))) != ""
+}
@@ -0,0 +1,154 @@
+// Package daemon lets one long-running mufetch process answer lookups for
+// others over a local socket, so status bars that run mufetch every few
+// seconds reuse its token, cache and now-playing poller.
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/export"
+)
+
+// dialTimeout bounds how long clients wait for a daemon before doing the
+// lookup themselves
+const dialTimeout = 500 * time.Millisecond
+
+// Operations a daemon answers
+const (
+	OpSearch = "search" // Look up Query as Type
+	OpNow    = "now"    // The song the poller last saw
+	OpPing   = "ping"
+)
+
+// Request is one line sent by a client
+type Request struct {
+	Op    string `json:"op"`
+	Query string `json:"query,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// Response is the daemon's answer: a result, or an error with the exit code
+// the client should use
+type Response struct {
+	Result *export.Result `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+	Code   int            `json:"code,omitempty"`
+}
+
+// SocketPath returns where the daemon listens: $XDG_RUNTIME_DIR/mufetch.sock,
+// or mufetch.sock in a per-user directory under the temp directory
+func SocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "mufetch.sock")
+	}
+	name := "mufetch"
+	if uid := os.Getuid(); uid >= 0 { // -1 on Windows, where the temp dir is per-user
+		name = fmt.Sprintf("mufetch-%d", uid)
+	}
+	return filepath.Join(os.TempDir(), name, "mufetch.sock")
+}
+
+// Ask sends a request to the daemon at path and waits up to timeout for
+// the answer. It fails quickly when no daemon is running, and refuses
+// sockets that belong to another user.
+func Ask(path string, req Request, timeout time.Duration) (*Response, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !ownedByUser(fi) {
+		return nil, fmt.Errorf("%s belongs to another user", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	var resp Response
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Listen opens the socket at path, replacing a stale one left by a daemon
+// that didn't shut down cleanly. It fails if another daemon is running or
+// the socket's directory isn't private to the user.
+func Listen(path string) (net.Listener, error) {
+	if err := privateDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if _, err := Ask(path, Request{Op: OpPing}, time.Second); err == nil {
+		return nil, fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	os.Remove(path)
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// Only the user's own processes may ask
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// privateDir creates dir with mode 0700 if needed, and fails unless it's a
+// directory owned by the user that nobody else can write to
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !fi.IsDir():
+		return fmt.Errorf("%s isn't a directory", dir)
+	case !ownedByUser(fi):
+		return fmt.Errorf("%s belongs to another user", dir)
+	case writableByOthers(fi):
+		return fmt.Errorf("%s is writable by other users", dir)
+	}
+	return nil
+}
+
+// Serve answers requests on l with handle until l is closed. Each
+// connection carries one request per line.
+func Serve(l net.Listener, handle func(Request) Response) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			dec := json.NewDecoder(conn)
+			enc := json.NewEncoder(conn)
+			for {
+				var req Request
+				if err := dec.Decode(&req); err != nil {
+					return
+				}
+				if err := enc.Encode(handle(req)); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
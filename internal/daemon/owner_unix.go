@@ -0,0 +1,20 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownedByUser reports whether the file belongs to the current user
+func ownedByUser(fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid()
+}
+
+// writableByOthers reports whether users besides the owner can write to
+// the file
+func writableByOthers(fi os.FileInfo) bool {
+	return fi.Mode().Perm()&0022 != 0
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package daemon
+
+import "os"
+
+// ownedByUser reports whether the file belongs to the current user. Windows
+// has no uids and its temp directory is already per-user.
+func ownedByUser(fi os.FileInfo) bool {
+	return true
+}
+
+// writableByOthers reports whether users besides the owner can write to
+// the file. Windows leaves that to ACLs, which file modes don't show.
+func writableByOthers(fi os.FileInfo) bool {
+	return false
+}
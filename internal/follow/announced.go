@@ -0,0 +1,60 @@
+package follow
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Announced remembers which releases the release hook was already given,
+// so each one is only announced once however often `mufetch releases` runs
+type Announced struct {
+	Path string
+}
+
+// NewAnnounced creates a store for announced.json in dir
+func NewAnnounced(dir string) *Announced {
+	return &Announced{Path: filepath.Join(dir, "announced.json")}
+}
+
+// Load returns the IDs of the releases already announced. A missing file
+// means none have been.
+func (a *Announced) Load() (map[string]bool, error) {
+	data, err := os.ReadFile(a.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return seen, nil
+}
+
+// Add records releases as announced
+func (a *Announced) Add(ids ...string) error {
+	seen, err := a.Load()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	all := make([]string, 0, len(seen))
+	for id := range seen {
+		all = append(all, id)
+	}
+	sort.Strings(all)
+	return writeJSON(a.Path, all)
+}
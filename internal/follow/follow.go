@@ -0,0 +1,99 @@
+// Package follow keeps the local list of followed artists that `mufetch
+// releases` checks for new music.
+package follow
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Artist is a followed artist
+type Artist struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Added time.Time `json:"added"`
+}
+
+// Store reads and writes the followed artists as a JSON file
+type Store struct {
+	Path string
+}
+
+// NewStore creates a store for follows.json in dir
+func NewStore(dir string) *Store {
+	return &Store{Path: filepath.Join(dir, "follows.json")}
+}
+
+// Load returns the followed artists in the order they were added. A
+// missing file means nobody is followed yet.
+func (s *Store) Load() ([]Artist, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var artists []Artist
+	if err := json.Unmarshal(data, &artists); err != nil {
+		return nil, err
+	}
+	return artists, nil
+}
+
+// Add follows an artist, reporting false if they were already followed
+func (s *Store) Add(a Artist) (bool, error) {
+	artists, err := s.Load()
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range artists {
+		if existing.ID == a.ID {
+			return false, nil
+		}
+	}
+	return true, s.write(append(artists, a))
+}
+
+// Remove unfollows the artist with the given ID or name (ignoring case),
+// returning the removed artist or nil if nobody matched
+func (s *Store) Remove(idOrName string) (*Artist, error) {
+	artists, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	for i, a := range artists {
+		if a.ID == idOrName || strings.EqualFold(a.Name, idOrName) {
+			removed := a
+			return &removed, s.write(append(artists[:i], artists[i+1:]...))
+		}
+	}
+	return nil, nil
+}
+
+// write replaces the file with artists
+func (s *Store) write(artists []Artist) error {
+	return writeJSON(s.Path, artists)
+}
+
+// writeJSON replaces the file at path with v as indented JSON, creating
+// its directory if needed
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
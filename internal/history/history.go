@@ -0,0 +1,115 @@
+// Package history keeps a local log of successful lookups so they can be
+// listed and run again.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxEntries is how many lookups are kept; older ones are dropped
+const maxEntries = 1000
+
+// Entry is one successful lookup
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Query  string    `json:"query"`            // What was searched for, or the ID given to get
+	Type   string    `json:"type"`             // Search type: auto, track, album, artist or id
+	Result string    `json:"result"`           // Type of the result: track, album or artist
+	Name   string    `json:"name"`             // Name of the result
+	Artist string    `json:"artist,omitempty"` // Artists of a track or album
+	URL    string    `json:"url,omitempty"`
+}
+
+// Store reads and appends entries in a JSON lines file
+type Store struct {
+	Path string
+}
+
+// NewStore creates a store for history.jsonl in dir
+func NewStore(dir string) *Store {
+	return &Store{Path: filepath.Join(dir, "history.jsonl")}
+}
+
+// Add appends an entry, trimming the file once it grows past maxEntries.
+// The file is kept private to the user, since it shows what they listen to.
+func (s *Store) Add(e Entry) error {
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	// Files from older versions were readable by anyone
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(e); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	entries, err := s.Load()
+	if err != nil || len(entries) <= maxEntries*11/10 {
+		return err
+	}
+	return s.write(entries[len(entries)-maxEntries:])
+}
+
+// Load returns every entry, oldest first. A missing file is an empty history.
+func (s *Store) Load() ([]Entry, error) {
+	f, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip lines cut off by a crash
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Clear deletes the history
+func (s *Store) Clear() error {
+	err := os.Remove(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// write replaces the file with entries
+func (s *Store) write(entries []Entry) error {
+	tmp := s.Path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
@@ -0,0 +1,121 @@
+// Package pager sends output taller than the terminal through $PAGER (or
+// less -R), the way git does.
+package pager
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+)
+
+// Pager buffers everything written to os.Stdout until Stop decides whether
+// to page it or print it directly
+type Pager struct {
+	stdout *os.File
+	writer *os.File
+	buf    bytes.Buffer
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Start redirects os.Stdout into a buffer. It returns nil, leaving stdout
+// alone, when stdout isn't a terminal or paging is disabled with PAGER=cat.
+func Start() *Pager {
+	info, err := os.Stdout.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+	if command() == "cat" {
+		return nil
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+
+	p := &Pager{stdout: os.Stdout, writer: w, done: make(chan struct{})}
+	go func() {
+		io.Copy(&p.buf, r)
+		r.Close()
+		close(p.done)
+	}()
+
+	os.Stdout = w
+	return p
+}
+
+// Stop restores os.Stdout and shows the buffered output, through the pager
+// when it has more lines than the terminal. Safe to call on a nil Pager and
+// more than once.
+func (p *Pager) Stop() {
+	if p == nil {
+		return
+	}
+	p.once.Do(p.flush)
+}
+
+// flush restores os.Stdout and writes or pages the buffered output
+func (p *Pager) flush() {
+	os.Stdout = p.stdout
+	p.writer.Close()
+	<-p.done
+
+	output := p.buf.Bytes()
+	size, err := platform.GetWindowSize()
+	if err != nil || size.Rows <= 0 || bytes.Count(output, []byte("\n")) < size.Rows {
+		p.stdout.Write(output)
+		return
+	}
+
+	if err := p.page(stripCursorCodes(output)); err != nil {
+		p.stdout.Write(output)
+	}
+}
+
+// page runs the pager with output on its stdin
+func (p *Pager) page(output []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command())
+	} else {
+		cmd = exec.Command("sh", "-c", command())
+	}
+	cmd.Stdin = bytes.NewReader(output)
+	cmd.Stdout = p.stdout
+	cmd.Stderr = os.Stderr
+
+	// Same defaults as git: quit if it fits, keep colors, don't clear
+	cmd.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+
+	return cmd.Run()
+}
+
+// command returns the pager to run, honoring MUFETCH_PAGER then PAGER
+func command() string {
+	for _, env := range []string{"MUFETCH_PAGER", "PAGER"} {
+		if pager := strings.TrimSpace(os.Getenv(env)); pager != "" {
+			return pager
+		}
+	}
+	return "less -R"
+}
+
+// cursorCodes matches escape sequences other than colors (SGR) and links,
+// such as cursor movement, which pagers display as garbage
+var cursorCodes = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-ln-z]`)
+
+// stripCursorCodes removes cursor control sequences from output
+func stripCursorCodes(output []byte) []byte {
+	return cursorCodes.ReplaceAll(output, nil)
+}
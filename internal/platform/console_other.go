@@ -0,0 +1,14 @@
+//go:build !windows
+
+package platform
+
+// enableVT has nothing to do: terminals handle escape codes themselves
+func enableVT() error {
+	return nil
+}
+
+// consoleTrueColor is false; outside Windows only the environment says
+// whether truecolor works
+func consoleTrueColor() bool {
+	return false
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package platform
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// vtEnabled records whether enableVT switched the console to VT mode
+var vtEnabled bool
+
+// enableVT turns on virtual terminal processing for stdout and stderr.
+// Handles that aren't consoles, like pipes, are left alone.
+func enableVT() error {
+	for _, f := range []*os.File{stdout, os.Stderr} {
+		handle := windows.Handle(f.Fd())
+		var mode uint32
+		if err := windows.GetConsoleMode(handle, &mode); err != nil {
+			continue
+		}
+		if err := windows.SetConsoleMode(handle, mode|windows.ENABLE_PROCESSED_OUTPUT|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+			return err
+		}
+		vtEnabled = true
+	}
+	return nil
+}
+
+// consoleTrueColor reports whether the console host draws 24-bit color,
+// which conhost does in VT mode from Windows 10 1703 (build 15063)
+func consoleTrueColor() bool {
+	return vtEnabled && windows.RtlGetVersion().BuildNumber >= 15063
+}
@@ -0,0 +1,23 @@
+package platform
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WriteOSC52 asks the terminal to put text on the clipboard with an OSC 52
+// escape sequence. This works over SSH since the local terminal does the
+// copy, but terminals that don't support it silently ignore the request.
+func WriteOSC52(w io.Writer, text string) error {
+	seq := fmt.Sprintf("\033]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+
+	// tmux only forwards escape sequences wrapped in a DCS passthrough
+	if os.Getenv("TMUX") != "" {
+		seq = "\033Ptmux;\033" + seq + "\033\\"
+	}
+
+	_, err := io.WriteString(w, seq)
+	return err
+}
@@ -0,0 +1,95 @@
+// Package platform wraps the OS-specific pieces mufetch relies on: opening
+// URLs, writing to the clipboard, desktop notifications and terminal size
+// detection, and what the terminal supports. Each operation has a
+// build-tagged implementation per platform.
+package platform
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnsupported is returned when an operation has no implementation on the
+// current platform or none of the required helper programs are installed
+var ErrUnsupported = errors.New("operation not supported on this platform")
+
+// WindowSize describes the terminal dimensions in cells and, when the
+// terminal reports them, pixels
+type WindowSize struct {
+	Cols        int
+	Rows        int
+	PixelWidth  int
+	PixelHeight int
+}
+
+// OpenURL opens a URL (or URI such as spotify:track:...) with the system's
+// default handler without waiting for it to exit
+func OpenURL(url string) error {
+	cmd := openCommand(url)
+	if cmd == nil {
+		return ErrUnsupported
+	}
+	return cmd.Start()
+}
+
+// CopyToClipboard writes text to the system clipboard
+func CopyToClipboard(text string) error {
+	cmd := clipboardCommand()
+	if cmd == nil {
+		return ErrUnsupported
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// Notify shows a desktop notification with the given title and body
+func Notify(title, body string) error {
+	cmd := notifyCommand(title, body)
+	if cmd == nil {
+		return ErrUnsupported
+	}
+	return cmd.Run()
+}
+
+// stdout is the process's real stdout, captured at startup so size detection
+// keeps working while os.Stdout is redirected (e.g. into the pager)
+var stdout = os.Stdout
+
+// GetWindowSize reports the size of the terminal attached to stdout
+func GetWindowSize() (WindowSize, error) {
+	return windowSize()
+}
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// CellSize returns the pixel width and height of a single terminal cell
+func CellSize() (int, int, error) {
+	ws, err := windowSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	if ws.Cols == 0 || ws.Rows == 0 || ws.PixelWidth == 0 || ws.PixelHeight == 0 {
+		return 0, 0, ErrUnsupported
+	}
+	return ws.PixelWidth / ws.Cols, ws.PixelHeight / ws.Rows, nil
+}
+
+// lookPath finds helper programs; tests replace it to pretend some are
+// installed
+var lookPath = exec.LookPath
+
+// firstAvailable returns a command for the first program found on PATH
+func firstAvailable(candidates ...[]string) *exec.Cmd {
+	for _, args := range candidates {
+		if _, err := lookPath(args[0]); err == nil {
+			return exec.Command(args[0], args[1:]...)
+		}
+	}
+	return nil
+}
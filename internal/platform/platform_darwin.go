@@ -0,0 +1,25 @@
+//go:build darwin
+
+package platform
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// openCommand uses open(1) which handles both http and spotify: URIs
+func openCommand(url string) *exec.Cmd {
+	return exec.Command("open", url)
+}
+
+// clipboardCommand pipes stdin into the pasteboard
+func clipboardCommand() *exec.Cmd {
+	return exec.Command("pbcopy")
+}
+
+// notifyCommand posts a notification through AppleScript
+func notifyCommand(title, body string) *exec.Cmd {
+	script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(body), strconv.Quote(title))
+	return exec.Command("osascript", "-e", script)
+}
@@ -0,0 +1,23 @@
+package platform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"open", args(openCommand("spotify:track:1")), "open spotify:track:1"},
+		{"clipboard", args(clipboardCommand()), "pbcopy"},
+		{"notify", args(notifyCommand("New \"music\"", "Burial")), `osascript -e display notification "Burial" with title "New \"music\""`},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tt.args, " "); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package platform
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// fakePath makes only the named programs look installed until the test ends
+func fakePath(t *testing.T, installed ...string) {
+	t.Helper()
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+	lookPath = func(name string) (string, error) {
+		for _, p := range installed {
+			if p == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+// args returns a command's arguments, or nil for no command
+func args(cmd *exec.Cmd) []string {
+	if cmd == nil {
+		return nil
+	}
+	return cmd.Args
+}
+
+func TestWriteOSC52(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte("Jóga\nBjörk"))
+	tests := []struct {
+		name string
+		tmux string
+		want string
+	}{
+		{"plain", "", "\033]52;c;" + encoded + "\a"},
+		{"tmux", "/tmp/tmux-1000/default,1,0", "\033Ptmux;\033\033]52;c;" + encoded + "\a\033\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMUX", tt.tmux)
+			var buf bytes.Buffer
+			if err := WriteOSC52(&buf, "Jóga\nBjörk"); err != nil {
+				t.Fatalf("WriteOSC52: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstAvailable(t *testing.T) {
+	fakePath(t, "xsel", "wl-copy")
+
+	cmd := firstAvailable([]string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard"}, []string{"wl-copy"})
+	if got := strings.Join(args(cmd), " "); got != "xsel --clipboard" {
+		t.Errorf("got %q, want the first installed program", got)
+	}
+	if cmd := firstAvailable([]string{"pbcopy"}); cmd != nil {
+		t.Errorf("got %v for a missing program", cmd.Args)
+	}
+}
+
+func TestMissingHelpers(t *testing.T) {
+	fakePath(t)
+	if openCommand("https://open.spotify.com") != nil {
+		t.Skip("this platform's helpers are always installed")
+	}
+	if err := OpenURL("https://open.spotify.com"); err != ErrUnsupported {
+		t.Errorf("OpenURL: got %v, want ErrUnsupported", err)
+	}
+	if err := CopyToClipboard("Jóga"); err != ErrUnsupported {
+		t.Errorf("CopyToClipboard: got %v, want ErrUnsupported", err)
+	}
+	if err := Notify("New music", "Burial"); err != ErrUnsupported {
+		t.Errorf("Notify: got %v, want ErrUnsupported", err)
+	}
+}
+
+// notTerminal points size detection at a regular file for the test
+func notTerminal(t *testing.T) {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := stdout
+	t.Cleanup(func() {
+		stdout = orig
+		f.Close()
+	})
+	stdout = f
+}
+
+func TestWindowSizeWithoutTerminal(t *testing.T) {
+	notTerminal(t)
+
+	ws, err := GetWindowSize()
+	if err == nil {
+		t.Errorf("GetWindowSize succeeded with %+v", ws)
+	}
+	if ws != (WindowSize{}) {
+		t.Errorf("got %+v, want zero size", ws)
+	}
+
+	w, h, err := CellSize()
+	if err == nil || w != 0 || h != 0 {
+		t.Errorf("CellSize: got %dx%d, %v", w, h, err)
+	}
+	if IsTerminal(stdout) {
+		t.Error("a regular file was taken for a terminal")
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !darwin && !windows
+
+package platform
+
+import (
+	"os"
+	"os/exec"
+)
+
+// openCommand uses xdg-open, the freedesktop default handler
+func openCommand(url string) *exec.Cmd {
+	return firstAvailable(
+		[]string{"xdg-open", url},
+		[]string{"gio", "open", url},
+	)
+}
+
+// clipboardCommand picks a Wayland or X11 clipboard tool, whichever exists
+func clipboardCommand() *exec.Cmd {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if cmd := firstAvailable([]string{"wl-copy"}); cmd != nil {
+			return cmd
+		}
+	}
+	return firstAvailable(
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+		[]string{"wl-copy"},
+	)
+}
+
+// notifyCommand uses notify-send from libnotify
+func notifyCommand(title, body string) *exec.Cmd {
+	return firstAvailable([]string{"notify-send", "--app-name=mufetch", title, body})
+}
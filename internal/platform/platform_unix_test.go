@@ -0,0 +1,58 @@
+//go:build !darwin && !windows
+
+package platform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenCommand(t *testing.T) {
+	tests := []struct {
+		installed []string
+		want      string
+	}{
+		{[]string{"xdg-open", "gio"}, "xdg-open spotify:track:1"},
+		{[]string{"gio"}, "gio open spotify:track:1"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		fakePath(t, tt.installed...)
+		if got := strings.Join(args(openCommand("spotify:track:1")), " "); got != tt.want {
+			t.Errorf("with %v: got %q, want %q", tt.installed, got, tt.want)
+		}
+	}
+}
+
+func TestClipboardCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		wayland   string
+		installed []string
+		want      string
+	}{
+		{"x11", "", []string{"xclip", "xsel", "wl-copy"}, "xclip -selection clipboard"},
+		{"x11 xsel", "", []string{"xsel"}, "xsel --clipboard --input"},
+		{"wayland", "wayland-0", []string{"xclip", "wl-copy"}, "wl-copy"},
+		{"wayland without wl-copy", "wayland-0", []string{"xclip"}, "xclip -selection clipboard"},
+		{"none", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WAYLAND_DISPLAY", tt.wayland)
+			fakePath(t, tt.installed...)
+			if got := strings.Join(args(clipboardCommand()), " "); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyCommand(t *testing.T) {
+	fakePath(t, "notify-send")
+	got := args(notifyCommand("New music", "Burial - Dreamfear"))
+	want := []string{"notify-send", "--app-name=mufetch", "New music", "Burial - Dreamfear"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
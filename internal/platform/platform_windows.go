@@ -0,0 +1,36 @@
+//go:build windows
+
+package platform
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// openCommand hands the URL to the shell's registered protocol handler
+func openCommand(url string) *exec.Cmd {
+	return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+}
+
+// clipboardCommand uses clip.exe which reads the text from stdin
+func clipboardCommand() *exec.Cmd {
+	return exec.Command("clip")
+}
+
+// notifyCommand shows a balloon notification through PowerShell
+func notifyCommand(title, body string) *exec.Cmd {
+	script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, '%s', '%s', 'None')
+Start-Sleep -Seconds 5
+$n.Dispose()`, psQuote(title), psQuote(body))
+	return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+}
+
+// psQuote escapes a value for use inside a single-quoted PowerShell string
+func psQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
@@ -0,0 +1,19 @@
+package platform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	if got := strings.Join(args(openCommand("spotify:track:1")), " "); got != "rundll32 url.dll,FileProtocolHandler spotify:track:1" {
+		t.Errorf("open: got %q", got)
+	}
+	if got := strings.Join(args(clipboardCommand()), " "); got != "clip" {
+		t.Errorf("clipboard: got %q", got)
+	}
+	script := args(notifyCommand("It's new", "Burial"))
+	if len(script) == 0 || !strings.Contains(script[len(script)-1], "'It''s new'") {
+		t.Errorf("notify: title not quoted for PowerShell in %q", script)
+	}
+}
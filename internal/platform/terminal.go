@@ -0,0 +1,48 @@
+package platform
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// EnableVT makes the console interpret color codes, cursor movement and
+// other escape sequences. Only Windows consoles need it; it fails on
+// consoles older than Windows 10, which can't show colors at all.
+func EnableVT() error {
+	return enableVT()
+}
+
+// SupportsTrueColor guesses whether the terminal shows 24-bit color
+func SupportsTrueColor() bool {
+	colorterm := os.Getenv("COLORTERM")
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return true
+	}
+	if os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("WT_SESSION") != "" {
+		return true
+	}
+	return consoleTrueColor()
+}
+
+// SupportsHyperlinks reports whether the terminal is known to handle OSC 8
+// links
+func SupportsHyperlinks() bool {
+	term := os.Getenv("TERM")
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if term == "xterm-kitty" || term == "foot" || os.Getenv("WT_SESSION") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	vte, _ := strconv.Atoi(os.Getenv("VTE_VERSION"))
+	return vte >= 5000 // GNOME Terminal and other VTE-based terminals
+}
+
+// ShowHyperlinks reports whether to write OSC 8 links. Most terminals skip
+// links they don't understand, but the classic Windows console prints them
+// as text, so there they're only written where they're known to work.
+func ShowHyperlinks() bool {
+	return runtime.GOOS != "windows" || SupportsHyperlinks()
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package platform
+
+// windowSize is unavailable without a terminal ioctl
+func windowSize() (WindowSize, error) {
+	return WindowSize{}, ErrUnsupported
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package platform
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// windowSize queries the terminal through the TIOCGWINSZ ioctl
+func windowSize() (WindowSize, error) {
+	ws, err := unix.IoctlGetWinsize(int(stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return WindowSize{}, err
+	}
+	return WindowSize{
+		Cols:        int(ws.Col),
+		Rows:        int(ws.Row),
+		PixelWidth:  int(ws.Xpixel),
+		PixelHeight: int(ws.Ypixel),
+	}, nil
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package platform
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// windowSize reads the visible console window; pixel sizes are not exposed
+func windowSize() (WindowSize, error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(stdout.Fd()), &info); err != nil {
+		return WindowSize{}, err
+	}
+	return WindowSize{
+		Cols: int(info.Window.Right-info.Window.Left) + 1,
+		Rows: int(info.Window.Bottom-info.Window.Top) + 1,
+	}, nil
+}
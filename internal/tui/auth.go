@@ -0,0 +1,342 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCanceled is returned by RunAuth when the form is closed without saving
+var ErrCanceled = errors.New("canceled")
+
+// AuthField is one setting the auth form asks for
+type AuthField struct {
+	Key      string // Key within the provider's config section
+	Label    string
+	Value    string // Current value, shown for editing; kept if a secret is left blank
+	Optional bool
+	Secret   bool // Masked while typing
+}
+
+// AuthProvider is a provider the auth form can set up
+type AuthProvider struct {
+	Name   string // Config section, e.g. lastfm
+	Title  string // Display name, e.g. Last.fm
+	Help   string // Where to get the credentials
+	Fields []AuthField
+}
+
+// AuthTest checks entered settings with a real request, e.g. for a token
+type AuthTest func(ctx context.Context, provider string, values map[string]string) error
+
+// authStep is where the form is
+type authStep int
+
+const (
+	stepProvider authStep = iota // Choosing a provider
+	stepFields                   // Typing credentials
+	stepTesting                  // Waiting for the test request
+	stepFailed                   // The test failed
+	stepDone
+)
+
+// testedMsg delivers the result of the connection test
+type testedMsg struct{ err error }
+
+// authModel is the bubbletea model of the auth form
+type authModel struct {
+	ctx       context.Context
+	providers []AuthProvider
+	test      AuthTest
+	fixed     bool // The provider was chosen up front, so esc doesn't go back
+
+	step     authStep
+	cursor   int // Provider under the cursor
+	inputs   []textinput.Model
+	focus    int
+	status   string
+	canceled bool
+}
+
+// RunAuth shows the auth form: pick a provider (unless selected names one),
+// enter or paste its credentials, and test them before they're saved. It
+// returns the chosen provider and the entered values by field key.
+func RunAuth(ctx context.Context, providers []AuthProvider, selected string, test AuthTest) (string, map[string]string, error) {
+	m := authModel{ctx: ctx, providers: providers, test: test}
+	for i, p := range providers {
+		if p.Name == selected {
+			m.cursor, m.fixed = i, true
+			m = m.showFields()
+		}
+	}
+
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return "", nil, err
+	}
+	m = final.(authModel)
+	if m.canceled {
+		return "", nil, ErrCanceled
+	}
+	return m.providers[m.cursor].Name, m.values(), nil
+}
+
+// Init starts the cursor blinking when the form opens on the fields
+func (m authModel) Init() tea.Cmd {
+	if m.step == stepFields {
+		return textinput.Blink
+	}
+	return nil
+}
+
+// Update handles keys and the test result
+func (m authModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case testedMsg:
+		if msg.err != nil {
+			m.step = stepFailed
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.step = stepDone
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.canceled = true
+			return m, tea.Quit
+		}
+		switch m.step {
+		case stepProvider:
+			return m.updateProvider(msg)
+		case stepFields:
+			return m.updateFields(msg)
+		case stepFailed:
+			return m.updateFailed(msg)
+		}
+	}
+
+	if m.step == stepFields {
+		return m.updateInput(msg)
+	}
+	return m, nil
+}
+
+// updateProvider handles keys on the provider list
+func (m authModel) updateProvider(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.providers)-1 {
+			m.cursor++
+		}
+	case "enter":
+		m = m.showFields()
+		return m, textinput.Blink
+	case "esc", "q":
+		m.canceled = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// updateFields handles keys while typing credentials
+func (m authModel) updateFields(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.fixed {
+			m.canceled = true
+			return m, tea.Quit
+		}
+		m.step = stepProvider
+		m.status = ""
+		return m, nil
+	case "tab", "down":
+		return m, m.focusField(m.focus + 1)
+	case "shift+tab", "up":
+		return m, m.focusField(m.focus - 1)
+	case "enter":
+		if m.focus < len(m.inputs)-1 {
+			return m, m.focusField(m.focus + 1)
+		}
+		return m.startTest()
+	case "ctrl+s":
+		if m.status = m.missing(); m.status != "" {
+			return m, nil
+		}
+		m.step = stepDone
+		return m, tea.Quit
+	}
+	return m.updateInput(msg)
+}
+
+// updateFailed handles keys after a failed test
+func (m authModel) updateFailed(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "r":
+		return m.startTest()
+	case "e", "esc":
+		m.step = stepFields
+		return m, textinput.Blink
+	case "s":
+		m.step = stepDone
+		return m, tea.Quit
+	case "q":
+		m.canceled = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// updateInput passes a message to the focused field
+func (m authModel) updateInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if len(m.inputs) == 0 {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+// showFields builds the inputs for the provider under the cursor
+func (m authModel) showFields() authModel {
+	fields := m.providers[m.cursor].Fields
+	m.inputs = make([]textinput.Model, len(fields))
+	for i, f := range fields {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Width = 40
+		switch {
+		case f.Secret && f.Value != "":
+			// Typing or pasting into a hidden value would go unnoticed
+			input.Placeholder = "saved, leave blank to keep"
+		case f.Optional:
+			input.Placeholder = "optional"
+		}
+		if f.Secret {
+			input.EchoMode = textinput.EchoPassword
+		} else {
+			input.SetValue(f.Value)
+		}
+		m.inputs[i] = input
+	}
+	m.step = stepFields
+	m.status = ""
+	m.focus = 0
+	m.focusField(0)
+	return m
+}
+
+// focusField moves the cursor to field i, wrapping around
+func (m *authModel) focusField(i int) tea.Cmd {
+	if len(m.inputs) == 0 {
+		return nil
+	}
+	m.inputs[m.focus].Blur()
+	m.focus = (i + len(m.inputs)) % len(m.inputs)
+	return m.inputs[m.focus].Focus()
+}
+
+// startTest checks the required fields and runs the connection test
+func (m authModel) startTest() (tea.Model, tea.Cmd) {
+	if m.status = m.missing(); m.status != "" {
+		return m, nil
+	}
+	if m.test == nil {
+		m.step = stepDone
+		return m, tea.Quit
+	}
+
+	m.step = stepTesting
+	ctx, test := m.ctx, m.test
+	name, values := m.providers[m.cursor].Name, m.values()
+	return m, func() tea.Msg {
+		return testedMsg{err: test(ctx, name, values)}
+	}
+}
+
+// missing names the first required field left empty, or returns ""
+func (m authModel) missing() string {
+	for i, f := range m.providers[m.cursor].Fields {
+		if !f.Optional && f.Value == "" && strings.TrimSpace(m.inputs[i].Value()) == "" {
+			return f.Label + " is required"
+		}
+	}
+	return ""
+}
+
+// values returns the entered settings by key, leaving out empty optional
+// ones and keeping secrets left blank
+func (m authModel) values() map[string]string {
+	values := make(map[string]string)
+	for i, f := range m.providers[m.cursor].Fields {
+		v := strings.TrimSpace(m.inputs[i].Value())
+		if v == "" && f.Secret {
+			v = f.Value
+		}
+		if v != "" {
+			values[f.Key] = v
+		}
+	}
+	return values
+}
+
+// View draws the current step
+func (m authModel) View() string {
+	if m.step == stepDone || m.canceled {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("mufetch auth") + "\n\n")
+
+	if m.step == stepProvider {
+		for i, p := range m.providers {
+			line := fmt.Sprintf(" %-10s ", p.Title)
+			if i == m.cursor {
+				b.WriteString(selectedStyle.Render(line))
+			} else {
+				b.WriteString(line)
+			}
+			b.WriteString(" " + subtleStyle.Render(p.Help) + "\n")
+		}
+		b.WriteString("\n" + subtleStyle.Render("↑/↓ choose · enter select · esc quit") + "\n")
+		return b.String()
+	}
+
+	p := m.providers[m.cursor]
+	b.WriteString(titleStyle.Render(p.Title) + " " + subtleStyle.Render(p.Help) + "\n\n")
+	labelWidth := 0
+	for _, f := range p.Fields {
+		labelWidth = max(labelWidth, len(f.Label))
+	}
+	for i, f := range p.Fields {
+		marker := "  "
+		if i == m.focus && m.step == stepFields {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%-*s  %s\n", marker, labelWidth, f.Label, m.inputs[i].View())
+	}
+	b.WriteString("\n")
+
+	switch m.step {
+	case stepFields:
+		if m.status != "" {
+			b.WriteString(errorStyle.Render(m.status) + "\n")
+		}
+		b.WriteString(subtleStyle.Render("tab next field · enter test and save · ctrl+s save without testing · esc back") + "\n")
+	case stepTesting:
+		b.WriteString("Testing connection...\n")
+	case stepFailed:
+		b.WriteString(errorStyle.Render("Test failed: "+m.status) + "\n")
+		b.WriteString(subtleStyle.Render("enter retry · e edit · s save anyway · q quit") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,581 @@
+// Package tui is an interactive browser for mufetch: search, pick a result
+// to see its card, and drill from artists to albums to tracks without
+// rerunning the CLI. It also has the form `mufetch auth` shows in a terminal.
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+	"github.com/ashish0kumar/mufetch/pkg/display"
+	"github.com/ashish0kumar/mufetch/pkg/provider"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	searchLimit = 20                     // Results fetched per search
+	listWidth   = 42                     // Width of the results column
+	detailDelay = 150 * time.Millisecond // Pause before loading a card, so scrolling stays fast
+)
+
+// searchTypes are cycled with tab
+var searchTypes = []string{"track", "album", "artist"}
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("2"))
+	subtleStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// item is a row in a results list
+type item struct {
+	kind     string // track, album or artist
+	title    string
+	subtitle string
+	track    *spotify.Track
+	album    *spotify.Album
+	artist   *spotify.Artist
+}
+
+// key identifies the item for the card cache
+func (it item) key() string {
+	switch it.kind {
+	case "track":
+		return "track:" + it.track.ID + it.title
+	case "album":
+		return "album:" + it.album.ID + it.title
+	}
+	return "artist:" + it.artist.ID + it.title
+}
+
+// url returns the item's page
+func (it item) url() string {
+	switch it.kind {
+	case "track":
+		return it.track.ExternalURL.URL()
+	case "album":
+		return it.album.ExternalURL.URL()
+	}
+	return it.artist.ExternalURL.URL()
+}
+
+// page is one level of browsing, e.g. search results or an album's tracks
+type page struct {
+	title  string
+	items  []item
+	cursor int
+}
+
+// resultsMsg delivers a new page, or the error that prevented it
+type resultsMsg struct {
+	page page
+	push bool // Stack on top of the current page instead of replacing all
+	err  error
+}
+
+// detailTickMsg fires once the cursor has rested on an item
+type detailTickMsg struct{ key string }
+
+// detailMsg delivers a rendered card
+type detailMsg struct{ key, text string }
+
+// Model is the bubbletea model of the browser
+type Model struct {
+	prov   provider.Provider
+	client *spotify.Client // For drilling into artists and albums; nil without Spotify
+	opts   display.Options
+
+	input      textinput.Model
+	searchType int
+	pages      []page
+	details    map[string]string
+	loading    bool
+	status     string
+	width      int
+	height     int
+}
+
+// New creates the browser. client may be nil, in which case only search
+// results can be viewed.
+func New(prov provider.Provider, client *spotify.Client, opts display.Options) Model {
+	input := textinput.New()
+	input.Placeholder = "Search for music"
+	input.Focus()
+
+	opts.Spinner = nil
+	opts.Out = nil
+	opts.PNGPath = ""
+	opts.Lyrics = nil
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+
+	return Model{
+		prov:    prov,
+		client:  client,
+		opts:    opts,
+		input:   input,
+		details: make(map[string]string),
+	}
+}
+
+// Run starts the browser in the terminal's alternate screen
+func Run(prov provider.Provider, client *spotify.Client, opts display.Options) error {
+	_, err := tea.NewProgram(New(prov, client, opts), tea.WithAltScreen()).Run()
+	return err
+}
+
+// Init starts the cursor blinking in the search box
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles keys and finished background work
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case resultsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.status = ""
+		if msg.push {
+			m.pages = append(m.pages, msg.page)
+		} else {
+			m.pages = []page{msg.page}
+		}
+		return m, m.scheduleDetail()
+
+	case detailTickMsg:
+		if it, ok := m.selected(); ok && it.key() == msg.key {
+			if _, requested := m.details[msg.key]; !requested {
+				m.details[msg.key] = ""
+				return m, m.renderDetail(it)
+			}
+		}
+		return m, nil
+
+	case detailMsg:
+		m.details[msg.key] = msg.text
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.input.Focused() {
+			return m.updateSearch(msg)
+		}
+		return m.updateBrowse(msg)
+	}
+
+	return m, nil
+}
+
+// updateSearch handles keys while the search box has focus
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "tab":
+		m.searchType = (m.searchType + 1) % len(searchTypes)
+		return m, nil
+	case "esc":
+		if len(m.pages) > 0 {
+			m.input.Blur()
+		}
+		return m, nil
+	case "enter":
+		query := strings.TrimSpace(m.input.Value())
+		if query == "" {
+			return m, nil
+		}
+		m.input.Blur()
+		m.loading = true
+		return m, m.search(query, searchTypes[m.searchType])
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// updateBrowse handles keys while moving through the results
+func (m Model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "/":
+		m.input.Focus()
+		m.input.SetValue("")
+		return m, textinput.Blink
+	case "tab":
+		m.searchType = (m.searchType + 1) % len(searchTypes)
+		return m, nil
+	case "up", "k":
+		return m, m.move(-1)
+	case "down", "j":
+		return m, m.move(1)
+	case "pgup":
+		return m, m.move(-m.listHeight())
+	case "pgdown":
+		return m, m.move(m.listHeight())
+	case "esc", "backspace", "h", "left":
+		if len(m.pages) > 1 {
+			m.pages = m.pages[:len(m.pages)-1]
+			m.status = ""
+			return m, m.scheduleDetail()
+		}
+		return m, nil
+	case "enter", "l", "right":
+		if it, ok := m.selected(); ok && !m.loading {
+			if cmd := m.drill(it); cmd != nil {
+				m.loading = true
+				return m, cmd
+			}
+		}
+		return m, nil
+	case "a":
+		if it, ok := m.selected(); ok && !m.loading {
+			if cmd := m.openArtist(it); cmd != nil {
+				m.loading = true
+				return m, cmd
+			}
+		}
+		return m, nil
+	case "o":
+		if it, ok := m.selected(); ok && it.url() != "" {
+			if err := platform.OpenURL(it.url()); err != nil {
+				m.status = "Failed to open link: " + err.Error()
+			}
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// move shifts the cursor by delta, clamped to the list
+func (m *Model) move(delta int) tea.Cmd {
+	if len(m.pages) == 0 {
+		return nil
+	}
+	p := &m.pages[len(m.pages)-1]
+	p.cursor += delta
+	if p.cursor >= len(p.items) {
+		p.cursor = len(p.items) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	return m.scheduleDetail()
+}
+
+// selected returns the item under the cursor
+func (m Model) selected() (item, bool) {
+	if len(m.pages) == 0 {
+		return item{}, false
+	}
+	p := m.pages[len(m.pages)-1]
+	if p.cursor >= len(p.items) {
+		return item{}, false
+	}
+	return p.items[p.cursor], true
+}
+
+// scheduleDetail asks for the selected item's card after a short pause
+func (m Model) scheduleDetail() tea.Cmd {
+	it, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	if _, requested := m.details[it.key()]; requested {
+		return nil
+	}
+	key := it.key()
+	return tea.Tick(detailDelay, func(time.Time) tea.Msg {
+		return detailTickMsg{key: key}
+	})
+}
+
+// search runs a search in the background
+func (m Model) search(query, sType string) tea.Cmd {
+	prov, ctx := m.prov, m.opts.Context
+	return func() tea.Msg {
+		var items []item
+		var err error
+
+		switch sType {
+		case "track":
+			var tracks []spotify.Track
+			if tracks, err = provider.ListTracks(ctx, prov, query, searchLimit); err == nil {
+				items = trackItems(tracks, nil)
+			}
+		case "album":
+			var albums []spotify.Album
+			if albums, err = provider.ListAlbums(ctx, prov, query, searchLimit); err == nil {
+				items = albumItems(albums)
+			}
+		case "artist":
+			var artists []spotify.Artist
+			if artists, err = provider.ListArtists(ctx, prov, query, searchLimit); err == nil {
+				items = artistItems(artists)
+			}
+		}
+
+		if err != nil {
+			return resultsMsg{err: fmt.Errorf("%s: %w", query, err)}
+		}
+		return resultsMsg{page: page{title: fmt.Sprintf("%ss for %q", titleCase(sType), query), items: items}}
+	}
+}
+
+// drill opens the level below an item: an artist's albums or an album's
+// tracks. Tracks have no level below.
+func (m Model) drill(it item) tea.Cmd {
+	switch it.kind {
+	case "artist":
+		return m.artistAlbums(it.artist.ID, it.artist.Name)
+	case "album":
+		if m.client == nil {
+			return m.needsSpotify()
+		}
+		client, ctx, id := m.client, m.opts.Context, it.album.ID
+		return func() tea.Msg {
+			album, err := client.GetAlbum(ctx, id)
+			if err != nil {
+				return resultsMsg{err: err}
+			}
+			return resultsMsg{page: page{title: album.Name, items: trackItems(album.Tracks.Items, album)}, push: true}
+		}
+	}
+	return nil
+}
+
+// openArtist jumps from a track or album to its artist's albums
+func (m Model) openArtist(it item) tea.Cmd {
+	var artists []spotify.Artist
+	switch it.kind {
+	case "track":
+		artists = it.track.Artists
+	case "album":
+		artists = it.album.Artists
+	default:
+		return nil
+	}
+	if len(artists) == 0 {
+		return nil
+	}
+	return m.artistAlbums(artists[0].ID, artists[0].Name)
+}
+
+// artistAlbums loads an artist's albums and singles as a new page
+func (m Model) artistAlbums(id, name string) tea.Cmd {
+	if m.client == nil {
+		return m.needsSpotify()
+	}
+	client, ctx := m.client, m.opts.Context
+	return func() tea.Msg {
+		albums, err := client.GetArtistAlbums(ctx, id, "album,single,compilation")
+		if err != nil {
+			return resultsMsg{err: err}
+		}
+		return resultsMsg{page: page{title: name, items: albumItems(albums.Items)}, push: true}
+	}
+}
+
+// needsSpotify reports that browsing needs Spotify's artist and album APIs
+func (m Model) needsSpotify() tea.Cmd {
+	return func() tea.Msg {
+		return resultsMsg{err: fmt.Errorf("browsing artists and albums needs the Spotify source")}
+	}
+}
+
+// renderDetail renders an item's card in the background
+func (m Model) renderDetail(it item) tea.Cmd {
+	opts := m.opts
+	client := m.client
+	if size := m.height - 6; size < opts.ImageSize {
+		opts.ImageSize = max(size, 8)
+	}
+
+	return func() tea.Msg {
+		var buf bytes.Buffer
+		opts.Out = &buf
+		switch it.kind {
+		case "track":
+			display.DisplayTrack(*it.track, client, opts)
+		case "album":
+			display.DisplayAlbum(*it.album, client, opts)
+		case "artist":
+			display.DisplayArtist(*it.artist, client, opts)
+		}
+		// lipgloss can't measure OSC 8 links, so the card goes in without them
+		return detailMsg{key: it.key(), text: ansi.StripLinks(buf.String())}
+	}
+}
+
+// listHeight is the number of result rows that fit on screen
+func (m Model) listHeight() int {
+	return max(m.height-5, 1)
+}
+
+// View draws the search box, the results list, the card and the key help
+func (m Model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf(" [%s] ", searchTypes[m.searchType])))
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	left := m.viewList()
+	right := ""
+	if it, ok := m.selected(); ok {
+		right = m.details[it.key()]
+		if right == "" {
+			right = subtleStyle.Render(" Loading...")
+		}
+	}
+
+	bodyHeight := m.height - 4
+	leftPane := lipgloss.NewStyle().Width(listWidth).Height(bodyHeight).MaxHeight(bodyHeight).Render(left)
+	rightPane := lipgloss.NewStyle().MaxWidth(max(m.width-listWidth-2, 0)).MaxHeight(bodyHeight).Render(right)
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, leftPane, "  ", rightPane))
+	b.WriteString("\n")
+
+	switch {
+	case m.loading:
+		b.WriteString(subtleStyle.Render(" Loading..."))
+	case m.status != "":
+		b.WriteString(errorStyle.Render(" " + m.status))
+	case m.input.Focused():
+		b.WriteString(subtleStyle.Render(" enter search · tab type · esc results · ctrl+c quit"))
+	default:
+		b.WriteString(subtleStyle.Render(" / search · tab type · enter open · a artist · esc back · o browser · q quit"))
+	}
+	return b.String()
+}
+
+// viewList draws the current page's title and the visible rows
+func (m Model) viewList() string {
+	if len(m.pages) == 0 {
+		return subtleStyle.Render(" Type a query and press enter")
+	}
+
+	p := m.pages[len(m.pages)-1]
+	lines := []string{titleStyle.Render(" " + runewidth.Truncate(p.title, listWidth-1, "..."))}
+	if len(p.items) == 0 {
+		return strings.Join(append(lines, subtleStyle.Render(" No results")), "\n")
+	}
+
+	// Keep the cursor in view
+	rows := m.listHeight() - 1
+	start := 0
+	if p.cursor >= rows {
+		start = p.cursor - rows + 1
+	}
+	end := min(start+rows, len(p.items))
+
+	for i := start; i < end; i++ {
+		it := p.items[i]
+
+		// Explicit tracks keep their badge however long the title is
+		mark := ""
+		if it.track != nil && it.track.Explicit {
+			mark = " [E]"
+		}
+		title := runewidth.Truncate(it.title, listWidth-3-len(mark), "...")
+		subtitle := runewidth.Truncate(it.subtitle, max(listWidth-4-runewidth.StringWidth(title+mark), 0), "...")
+		if i == p.cursor {
+			line := " " + title + mark
+			if subtitle != "" {
+				line += " · " + subtitle
+			}
+			lines = append(lines, selectedStyle.Render(runewidth.FillRight(line, listWidth)))
+			continue
+		}
+		line := " " + title
+		if mark != "" {
+			line += errorStyle.Render(mark)
+		}
+		if subtitle != "" {
+			line += subtleStyle.Render(" · " + subtitle)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trackItems lists tracks; album fills in the album of tracklist entries,
+// which Spotify leaves out
+func trackItems(tracks []spotify.Track, album *spotify.Album) []item {
+	items := make([]item, len(tracks))
+	for i := range tracks {
+		t := tracks[i]
+		if album != nil {
+			t.Album = *album
+			t.Album.Tracks = spotify.TracksPage{}
+		}
+		items[i] = item{kind: "track", title: t.Name, subtitle: artistNames(t.Artists), track: &t}
+	}
+	return items
+}
+
+// albumItems lists albums with their release year
+func albumItems(albums []spotify.Album) []item {
+	items := make([]item, len(albums))
+	for i := range albums {
+		a := albums[i]
+		subtitle := artistNames(a.Artists)
+		if len(a.ReleaseDate) >= 4 {
+			subtitle = a.ReleaseDate[:4] + " " + a.AlbumType
+		}
+		items[i] = item{kind: "album", title: a.Name, subtitle: subtitle, album: &a}
+	}
+	return items
+}
+
+// artistItems lists artists with their top genre
+func artistItems(artists []spotify.Artist) []item {
+	items := make([]item, len(artists))
+	for i := range artists {
+		a := artists[i]
+		subtitle := ""
+		if len(a.Genres) > 0 {
+			subtitle = a.Genres[0]
+		}
+		items[i] = item{kind: "artist", title: a.Name, subtitle: subtitle, artist: &a}
+	}
+	return items
+}
+
+// artistNames joins artist names with commas
+func artistNames(artists []spotify.Artist) string {
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// titleCase capitalizes the first letter of s
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
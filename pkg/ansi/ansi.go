@@ -0,0 +1,96 @@
+// Package ansi measures and cuts strings that contain terminal escape codes:
+// SGR colors and OSC 8 hyperlinks count as zero width, and are never split,
+// so colored or clickable text lines up in columns like plain text.
+package ansi
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	reset     = "\033[0m"
+	linkClose = "\033]8;;\033\\"
+)
+
+// pattern matches CSI sequences such as colors, and OSC sequences such as
+// hyperlinks, ended by ST or BEL
+var pattern = regexp.MustCompile("\033\\[[0-9;?]*[A-Za-z]|\033\\][^\033\007]*(?:\033\\\\|\007)")
+
+// linkPattern matches just OSC 8 hyperlinks
+var linkPattern = regexp.MustCompile("\033\\]8;[^\033\007]*(?:\033\\\\|\007)")
+
+// Strip removes every escape sequence from s
+func Strip(s string) string {
+	return pattern.ReplaceAllString(s, "")
+}
+
+// StripLinks removes OSC 8 hyperlinks from s, keeping the link text and
+// colors
+func StripLinks(s string) string {
+	return linkPattern.ReplaceAllString(s, "")
+}
+
+// Width counts the terminal cells s occupies, ignoring escape sequences
+func Width(s string) int {
+	return runewidth.StringWidth(Strip(s))
+}
+
+// Pad appends spaces to s until it's width cells wide
+func Pad(s string, width int) string {
+	if w := Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// Truncate shortens s to at most width cells, ending it with tail. Escape
+// sequences are kept whole, and a link or color still open at the cut is
+// closed so it doesn't run on into whatever follows.
+func Truncate(s string, width int, tail string) string {
+	if Width(s) <= width {
+		return s
+	}
+	limit := max(width-runewidth.StringWidth(tail), 0)
+
+	var b strings.Builder
+	cells := 0
+	linked, styled := false, false
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			if loc := pattern.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				seq := s[i : i+loc[1]]
+				switch {
+				case linkPattern.MatchString(seq):
+					linked = seq != linkClose && seq != "\033]8;;\007"
+				case strings.HasSuffix(seq, "m"):
+					styled = seq != reset && seq != "\033[m"
+				}
+				b.WriteString(seq)
+				i += loc[1]
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runewidth.RuneWidth(r)
+		if cells+w > limit {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		cells += w
+		i += size
+	}
+
+	if linked {
+		b.WriteString(linkClose)
+	}
+	b.WriteString(tail)
+	if styled {
+		b.WriteString(reset)
+	}
+	return b.String()
+}
@@ -0,0 +1,211 @@
+// Package cache manages mufetch's on-disk caches of API responses and
+// downloaded images.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Cache kinds, each stored in its own subdirectory
+const (
+	Responses = "responses"
+	Images    = "images"
+)
+
+// Kinds lists every cache kind
+var Kinds = []string{Responses, Images}
+
+// Usage summarizes the files in one cache kind
+type Usage struct {
+	Kind   string
+	Files  int
+	Bytes  int64
+	Oldest time.Time // Zero when the cache is empty
+}
+
+// Cache is a directory holding one subdirectory per kind
+type Cache struct {
+	Dir string
+}
+
+// New creates a cache rooted at dir
+func New(dir string) *Cache {
+	return &Cache{Dir: dir}
+}
+
+// Default returns the cache in the user's cache directory, e.g.
+// ~/.cache/mufetch on Linux
+func Default() (*Cache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(filepath.Join(dir, "mufetch")), nil
+}
+
+// KindDir returns the directory of a cache kind
+func (c *Cache) KindDir(kind string) string {
+	return filepath.Join(c.Dir, kind)
+}
+
+// Get returns the entry stored under key, or an fs.ErrNotExist error. A hit
+// marks the entry as recently used, so Trim drops it last.
+func (c *Cache) Get(kind, key string) ([]byte, error) {
+	path := c.path(kind, key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return data, nil
+}
+
+// Put stores data under key, replacing any previous entry
+func (c *Cache) Put(kind, key string, data []byte) error {
+	path := c.path(kind, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so concurrent readers never see half an entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path returns the file for a key: its SHA-256, fanned out over
+// subdirectories by the first two hex digits
+func (c *Cache) path(kind, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.KindDir(kind), name[:2], name)
+}
+
+// Stats returns the usage of every kind
+func (c *Cache) Stats() ([]Usage, error) {
+	usage := make([]Usage, len(Kinds))
+	for i, kind := range Kinds {
+		usage[i].Kind = kind
+		err := c.walk(kind, func(path string, info fs.FileInfo) error {
+			usage[i].Files++
+			usage[i].Bytes += info.Size()
+			if usage[i].Oldest.IsZero() || info.ModTime().Before(usage[i].Oldest) {
+				usage[i].Oldest = info.ModTime()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return usage, nil
+}
+
+// Clear deletes every file of the given kinds, returning how many files and
+// bytes were removed
+func (c *Cache) Clear(kinds ...string) (int, int64, error) {
+	return c.remove(kinds, func(fs.FileInfo) bool { return true })
+}
+
+// Prune deletes files of the given kinds last written before cutoff
+func (c *Cache) Prune(cutoff time.Time, kinds ...string) (int, int64, error) {
+	return c.remove(kinds, func(info fs.FileInfo) bool { return info.ModTime().Before(cutoff) })
+}
+
+// Trim deletes the least recently used files of a kind until it takes up
+// at most maxBytes
+func (c *Cache) Trim(kind string, maxBytes int64) (int, int64, error) {
+	type entry struct {
+		path string
+		info fs.FileInfo
+	}
+	var entries []entry
+	var total int64
+	err := c.walk(kind, func(path string, info fs.FileInfo) error {
+		entries = append(entries, entry{path, info})
+		total += info.Size()
+		return nil
+	})
+	if err != nil || total <= maxBytes {
+		return 0, 0, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].info.ModTime().Before(entries[j].info.ModTime())
+	})
+	files, bytes := 0, int64(0)
+	for _, e := range entries {
+		if total-bytes <= maxBytes {
+			break
+		}
+		if err := os.Remove(e.path); err != nil {
+			return files, bytes, err
+		}
+		files++
+		bytes += e.info.Size()
+	}
+	return files, bytes, nil
+}
+
+// remove deletes the files that match and reports what was freed
+func (c *Cache) remove(kinds []string, match func(fs.FileInfo) bool) (int, int64, error) {
+	files, bytes := 0, int64(0)
+	for _, kind := range kinds {
+		err := c.walk(kind, func(path string, info fs.FileInfo) error {
+			if !match(info) {
+				return nil
+			}
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			files++
+			bytes += info.Size()
+			return nil
+		})
+		if err != nil {
+			return files, bytes, err
+		}
+	}
+	return files, bytes, nil
+}
+
+// walk calls fn for each regular file of a kind. A missing directory is an
+// empty cache.
+func (c *Cache) walk(kind string, fn func(path string, info fs.FileInfo) error) error {
+	err := filepath.WalkDir(c.KindDir(kind), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, info)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
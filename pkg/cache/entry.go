@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Entry is a cached download with the validators needed to ask the server
+// whether it has changed
+type Entry struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Checked      time.Time `json:"checked"` // When the server last confirmed Body
+	Body         []byte    `json:"-"`
+}
+
+// errBadEntry is returned for files that aren't entries, e.g. art cached
+// by older versions
+var errBadEntry = errors.New("not a cache entry")
+
+// GetEntry returns the entry stored under key with PutEntry
+func (c *Cache) GetEntry(kind, key string) (*Entry, error) {
+	data, err := c.Get(kind, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// A line of JSON metadata, then the body as is
+	meta, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, errBadEntry
+	}
+	var e Entry
+	if err := json.Unmarshal(meta, &e); err != nil {
+		return nil, errBadEntry
+	}
+	e.Body = body
+	return &e, nil
+}
+
+// PutEntry stores e under key
+func (c *Cache) PutEntry(kind, key string, e *Entry) error {
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data := make([]byte, 0, len(meta)+1+len(e.Body))
+	data = append(append(append(data, meta...), '\n'), e.Body...)
+	return c.Put(kind, key, data)
+}
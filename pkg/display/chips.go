@@ -0,0 +1,58 @@
+package display
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// chipWrapWidth is the visible width after which chips wrap onto a new line
+const chipWrapWidth = 40
+
+// chipsField renders values as colored tags wrapping across lines, with the
+// label on the first line and continuation lines aligned under the values
+func (o Options) chipsField(key, label string, values []string) field {
+	colors := o.theme().ChipColors
+	if len(colors) == 0 {
+		colors = DefaultTheme.ChipColors
+	}
+
+	var rows []string
+	var row strings.Builder
+	width := 0
+
+	for i, value := range values {
+		chipWidth := runewidth.StringWidth(value) + 2
+		if width > 0 && width+1+chipWidth > o.chipWrapWidth() {
+			rows = append(rows, row.String())
+			row.Reset()
+			width = 0
+		}
+		if width > 0 {
+			row.WriteString(" ")
+			width++
+		}
+
+		bg := colors[i%len(colors)]
+		row.WriteString(bg + o.theme().ChipText + " " + value + " " + ColorReset)
+		width += chipWidth
+	}
+	rows = append(rows, row.String())
+
+	lines := make([]string, len(rows))
+	lines[0] = formatLabel(label) + rows[0]
+	indent := strings.Repeat(" ", labelColumnWidth)
+	for i := 1; i < len(rows); i++ {
+		lines[i] = indent + rows[i]
+	}
+
+	return field{key: key, lines: lines}
+}
+
+// chipWrapWidth returns the configured value width or the chip default
+func (o Options) chipWrapWidth() int {
+	if o.MaxWidth > 0 {
+		return o.MaxWidth
+	}
+	return chipWrapWidth
+}
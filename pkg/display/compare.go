@@ -0,0 +1,149 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+const (
+	compareColumnWidth = 30 // Width of each artist's column
+	compareTopTracks   = 5  // Top tracks listed per artist
+)
+
+// compareRow is a labeled row with one block of lines per artist
+type compareRow struct {
+	label string
+	a, b  []string
+}
+
+// DisplayComparison renders two artists in side-by-side columns: photo,
+// followers, popularity, genres and top tracks, with the larger numbers
+// highlighted
+func DisplayComparison(a, b spotify.Artist, topA, topB []spotify.Track, opts Options) {
+	var thumbs [2][]string
+	if !opts.NoImage {
+		opts.ImageSize = gridThumbSize
+		var wg sync.WaitGroup
+		for i, artist := range []spotify.Artist{a, b} {
+			wg.Add(1)
+			go func(i int, images []spotify.Image) {
+				defer wg.Done()
+				thumbs[i] = opts.gridThumb(images)
+			}(i, artist.Images)
+		}
+		wg.Wait()
+	}
+
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	followersA, followersB := opts.compareNumbers(a.Followers.Total, b.Followers.Total, opts.formatCount)
+	popularityA, popularityB := opts.compareNumbers(a.Popularity, b.Popularity, func(n int) string {
+		return fmt.Sprintf("%d%%", n)
+	})
+
+	rows := []compareRow{
+		{"", thumbs[0], thumbs[1]},
+		{"", []string{compareName(a)}, []string{compareName(b)}},
+		{"Followers", []string{followersA}, []string{followersB}},
+		{"Popularity", []string{popularityA}, []string{popularityB}},
+		{"Genres", compareGenres(a.Genres), compareGenres(b.Genres)},
+	}
+	if shared := sharedGenres(a.Genres, b.Genres); len(shared) > 0 {
+		rows = append(rows, compareRow{"In Common", compareGenres(shared), nil})
+	}
+	rows = append(rows, compareRow{"Top Tracks", compareTracks(topA), compareTracks(topB)})
+
+	for _, row := range rows {
+		height := len(row.a)
+		if len(row.b) > height {
+			height = len(row.b)
+		}
+
+		for i := 0; i < height; i++ {
+			label := strings.Repeat(" ", labelColumnWidth)
+			if i == 0 && row.label != "" {
+				label = formatLabel(row.label)
+			}
+			line := " " + label + ansi.Pad(lineAt(row.a, i), compareColumnWidth) + "  " + lineAt(row.b, i)
+			opts.println(strings.TrimRight(line, " "))
+		}
+	}
+	opts.println("")
+}
+
+// compareName is the bold, linked artist name heading a column
+func compareName(artist spotify.Artist) string {
+	name := createClickableLink(artist.ExternalURL.URL(), truncate(artist.Name, compareColumnWidth))
+	return ColorBold + name + ColorReset
+}
+
+// compareNumbers formats both values, coloring the larger one like good news
+func (o Options) compareNumbers(a, b int, format func(int) string) (string, string) {
+	colorA, colorB := ColorWhite, ColorWhite
+	switch {
+	case a > b:
+		colorA = o.goodColor() + ColorBold
+	case b > a:
+		colorB = o.goodColor() + ColorBold
+	}
+	return colorA + format(a) + ColorReset, colorB + format(b) + ColorReset
+}
+
+// compareGenres wraps a genre list to the column width
+func compareGenres(genres []string) []string {
+	if len(genres) == 0 {
+		return []string{ColorWhite + "N/A" + ColorReset}
+	}
+	lines := wrapText(strings.Join(genres, ", "), compareColumnWidth)
+	for i, line := range lines {
+		lines[i] = ColorCyan + line + ColorReset
+	}
+	return lines
+}
+
+// compareTracks numbers the first few top tracks
+func compareTracks(tracks []spotify.Track) []string {
+	if len(tracks) == 0 {
+		return []string{ColorWhite + "N/A" + ColorReset}
+	}
+	if len(tracks) > compareTopTracks {
+		tracks = tracks[:compareTopTracks]
+	}
+
+	lines := make([]string, len(tracks))
+	for i, track := range tracks {
+		name := truncate(fmt.Sprintf("%d. %s", i+1, track.Name), compareColumnWidth)
+		lines[i] = ColorYellow + createClickableLink(track.ExternalURL.URL(), name) + ColorReset
+	}
+	return lines
+}
+
+// sharedGenres returns the genres of a that b also has
+func sharedGenres(a, b []string) []string {
+	seen := make(map[string]bool, len(b))
+	for _, g := range b {
+		seen[g] = true
+	}
+
+	var shared []string
+	for _, g := range a {
+		if seen[g] {
+			shared = append(shared, g)
+		}
+	}
+	return shared
+}
+
+// lineAt returns lines[i], or "" past the end
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/mattn/go-runewidth"
+)
+
+// Credit is a person credited on a track under a role
+type Credit struct {
+	Role string
+	Name string
+}
+
+// DisplayCredits prints a track's credits under a header with its name,
+// artists and album, one role per line with everyone credited for it
+func DisplayCredits(track spotify.Track, credits []Credit, opts Options) {
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	name := createClickableLink(track.ExternalURL.URL(), track.Name)
+	opts.println(fmt.Sprintf(" %s%s%s%s", ColorBold, ColorGreen, name, ColorReset))
+	opts.println(fmt.Sprintf(" %s%s%s %s· %s%s", ColorYellow, artistNames(track.Artists), ColorReset, ColorBlue, track.Album.Name, ColorReset))
+	opts.println("")
+
+	// Group names by role, keeping the order roles first appear in
+	var roles []string
+	names := make(map[string][]string)
+	roleWidth := 0
+	for _, c := range credits {
+		if _, ok := names[c.Role]; !ok {
+			roles = append(roles, c.Role)
+			roleWidth = max(roleWidth, runewidth.StringWidth(c.Role))
+		}
+		if !containsString(names[c.Role], c.Name) {
+			names[c.Role] = append(names[c.Role], c.Name)
+		}
+	}
+
+	indent := strings.Repeat(" ", roleWidth+3)
+	for _, role := range roles {
+		lines := wrapText(strings.Join(names[role], ", "), opts.maxWidth())
+		padding := strings.Repeat(" ", roleWidth-runewidth.StringWidth(role))
+		for i, line := range lines {
+			if i == 0 {
+				opts.println(fmt.Sprintf(" %s%s%s%s  %s", ColorCyan, role, padding, ColorReset, line))
+				continue
+			}
+			opts.println(indent + line)
+		}
+	}
+	opts.println("")
+}
+
+// containsString reports whether list has s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
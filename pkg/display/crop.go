@@ -0,0 +1,177 @@
+package display
+
+import (
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// Crop modes for non-square art
+const (
+	CropCenter = "center" // Keep the middle of the image
+	CropSmart  = "smart"  // Favor faces and detail when choosing the window
+	CropNone   = "none"   // Squash the whole image into the art area
+)
+
+// CropNames lists the accepted --crop values
+var CropNames = []string{CropCenter, CropSmart, CropNone}
+
+// defaultCellAspect is the height-to-width ratio assumed for a terminal
+// cell when the terminal doesn't report its size in pixels
+const defaultCellAspect = 2.0
+
+// cellAspect returns the height-to-width ratio of a terminal cell
+func (r *ImageRenderer) cellAspect() float64 {
+	if r.aspect > 0 {
+		return r.aspect
+	}
+	return defaultCellAspect
+}
+
+// setAspect sets the cell shape, giving the art as many rows as keep it
+// square on screen: fewer on fonts with tall cells, more on squat ones
+func (r *ImageRenderer) setAspect(aspect float64) {
+	r.aspect = aspect
+	r.height = max(int(math.Round(float64(r.width*2)/r.cellAspect())), 1)
+}
+
+// artAspect returns the width/height ratio of the art area in pixels; each
+// image pixel is drawn two cells wide so it comes out square on screen
+func (r *ImageRenderer) artAspect() float64 {
+	return float64(r.width*2) / (float64(r.height) * r.cellAspect())
+}
+
+// cropToAspect trims img to the given width/height ratio so resizing
+// doesn't distort it, choosing the window according to mode
+func cropToAspect(img image.Image, aspect float64, mode string) image.Image {
+	if mode == CropNone {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return img
+	}
+
+	cropW, cropH := w, h
+	if float64(w)/float64(h) > aspect {
+		cropW = int(math.Round(float64(h) * aspect))
+	} else {
+		cropH = int(math.Round(float64(w) / aspect))
+	}
+	if cropW == w && cropH == h {
+		return img
+	}
+
+	if mode != CropSmart {
+		return imaging.CropCenter(img, cropW, cropH)
+	}
+
+	x, y := smartOffset(img, cropW, cropH)
+	rect := image.Rect(x, y, x+cropW, y+cropH).Add(bounds.Min)
+	return imaging.Crop(img, rect)
+}
+
+// smartOffset slides the crop window along the long axis and returns the
+// position that covers the most skin tones and edge detail
+func smartOffset(img image.Image, cropW, cropH int) (int, int) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// Analyse a small copy; the weights only need to be coarse
+	const analysisSize = 64
+	scale := float64(analysisSize) / math.Max(float64(w), float64(h))
+	if scale > 1 {
+		scale = 1
+	}
+	small := imaging.Resize(img, int(math.Max(1, float64(w)*scale)), int(math.Max(1, float64(h)*scale)), imaging.Box)
+	sw, sh := small.Bounds().Dx(), small.Bounds().Dy()
+
+	horizontal := cropW < w
+	length := sh
+	if horizontal {
+		length = sw
+	}
+
+	// Project pixel weights onto the axis the window moves along
+	profile := make([]float64, length)
+	for y := 0; y < sh; y++ {
+		for x := 0; x < sw; x++ {
+			weight := pixelWeight(small, x, y)
+			if horizontal {
+				profile[x] += weight
+			} else {
+				profile[y] += weight
+			}
+		}
+	}
+
+	window := int(math.Round(float64(cropH) * scale))
+	if horizontal {
+		window = int(math.Round(float64(cropW) * scale))
+	}
+	if window < 1 || window >= length {
+		return (w - cropW) / 2, (h - cropH) / 2
+	}
+
+	// Running window sum, keeping the best start position
+	sum := 0.0
+	for i := 0; i < window; i++ {
+		sum += profile[i]
+	}
+	best, bestSum := 0, sum
+	for start := 1; start+window <= length; start++ {
+		sum += profile[start+window-1] - profile[start-1]
+		if sum > bestSum {
+			best, bestSum = start, sum
+		}
+	}
+
+	offset := int(math.Round(float64(best) / scale))
+	if horizontal {
+		return clampInt(offset, 0, w-cropW), 0
+	}
+	return 0, clampInt(offset, 0, h-cropH)
+}
+
+// pixelWeight scores a pixel by local contrast, boosted for skin tones
+func pixelWeight(img *image.NRGBA, x, y int) float64 {
+	c := img.NRGBAAt(x, y)
+	r, g, b := float64(c.R), float64(c.G), float64(c.B)
+
+	weight := 0.0
+	if x+1 < img.Bounds().Dx() {
+		n := img.NRGBAAt(x+1, y)
+		weight += math.Abs(r-float64(n.R)) + math.Abs(g-float64(n.G)) + math.Abs(b-float64(n.B))
+	}
+	if y+1 < img.Bounds().Dy() {
+		n := img.NRGBAAt(x, y+1)
+		weight += math.Abs(r-float64(n.R)) + math.Abs(g-float64(n.G)) + math.Abs(b-float64(n.B))
+	}
+
+	if isSkinTone(r, g, b) {
+		weight += 200
+	}
+
+	return weight
+}
+
+// isSkinTone applies a classic RGB skin detection rule
+func isSkinTone(r, g, b float64) bool {
+	maxC := math.Max(r, math.Max(g, b))
+	minC := math.Min(r, math.Min(g, b))
+	return r > 95 && g > 40 && b > 20 && maxC-minC > 15 && math.Abs(r-g) > 15 && r > g && r > b
+}
+
+// clampInt keeps v within [lo, hi]
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
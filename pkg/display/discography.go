@@ -0,0 +1,148 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/mattn/go-runewidth"
+)
+
+// albumTypeColors color the release type column
+var albumTypeColors = map[string]string{
+	"album":       ColorGreen,
+	"single":      ColorYellow,
+	"compilation": ColorPurple,
+}
+
+// discographyGroups are the release groups in the order they're counted;
+// appearances on other artists' releases get their own section
+var discographyGroups = []struct{ group, noun string }{
+	{"album", "album"},
+	{"single", "single"},
+	{"compilation", "compilation"},
+	{"appears_on", "appearance"},
+}
+
+// DisplayDiscography lists an artist's releases oldest first, grouped by
+// year, with each release's date, type and track count. Releases the
+// artist only appears on follow in a section of their own.
+func DisplayDiscography(artist spotify.Artist, albums []spotify.Album, opts Options) {
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	sorted := make([]spotify.Album, len(albums))
+	copy(sorted, albums)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ReleaseDate < sorted[j].ReleaseDate
+	})
+
+	var own, appearances []spotify.Album
+	counts := make(map[string]int)
+	for _, album := range sorted {
+		group := releaseGroup(album)
+		counts[group]++
+		if group == "appears_on" {
+			appearances = append(appearances, album)
+		} else {
+			own = append(own, album)
+		}
+	}
+
+	var parts []string
+	for _, g := range discographyGroups {
+		if counts[g.group] > 0 {
+			parts = append(parts, pluralize(counts[g.group], g.noun))
+		}
+	}
+	summary := pluralize(len(sorted), "release")
+	if len(parts) > 1 {
+		summary += " (" + strings.Join(parts, ", ") + ")"
+	}
+
+	name := createClickableLink(artist.ExternalURL.URL(), artist.Name)
+	opts.println(fmt.Sprintf(" %s%s%s %s· %s%s", ColorBold, name, ColorReset, ColorCyan, summary, ColorReset))
+
+	opts.printReleasesByYear(own)
+	if len(appearances) > 0 {
+		opts.println("")
+		opts.println(fmt.Sprintf(" %sAppears On%s", ColorBold, ColorReset))
+		opts.printReleasesByYear(appearances)
+	}
+	opts.println("")
+}
+
+// releaseGroup is how the release relates to the artist (album, single,
+// compilation or appears_on), falling back to its type
+func releaseGroup(album spotify.Album) string {
+	if album.AlbumGroup != "" {
+		return album.AlbumGroup
+	}
+	if album.AlbumType != "" {
+		return album.AlbumType
+	}
+	return "album"
+}
+
+// printReleasesByYear lists releases under a heading per year
+func (o Options) printReleasesByYear(albums []spotify.Album) {
+	width := o.maxWidth()
+	var currentYear string
+	for _, album := range albums {
+		year := album.ReleaseDate
+		if len(year) > 4 {
+			year = year[:4]
+		}
+		if year != currentYear {
+			currentYear = year
+			o.println("")
+			o.println(fmt.Sprintf(" %s%s%s", ColorBold, year, ColorReset))
+		}
+
+		title := runewidth.Truncate(album.Name, width, "...")
+		padding := strings.Repeat(" ", width-runewidth.StringWidth(title))
+		title = createClickableLink(album.ExternalURL.URL(), title)
+
+		kind := album.AlbumType
+		if kind == "" {
+			kind = "album"
+		}
+		color, ok := albumTypeColors[kind]
+		if !ok {
+			color = ColorWhite
+		}
+
+		o.println(fmt.Sprintf("   %s%-6s%s  %s%s  %s%-11s%s  %s",
+			ColorCyan, releaseMonthDay(album), ColorReset,
+			title, padding,
+			color, kind, ColorReset,
+			pluralize(album.TotalTracks, "track")))
+	}
+}
+
+// releaseMonthDay formats the part of the release date below the year
+// ("Jun 05"), blank when Spotify only knows the year
+func releaseMonthDay(album spotify.Album) string {
+	switch datePrecision(album.ReleaseDate, album.ReleaseDatePrecision) {
+	case "month":
+		if t, err := time.Parse("2006-01", album.ReleaseDate); err == nil {
+			return t.Format("Jan")
+		}
+	case "day":
+		if t, err := time.Parse("2006-01-02", album.ReleaseDate); err == nil {
+			return t.Format("Jan 02")
+		}
+	}
+	return ""
+}
+
+// pluralize formats a count with its noun ("1 track", "12 tracks")
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
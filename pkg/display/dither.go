@@ -0,0 +1,266 @@
+package display
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// Renderer modes for album art
+const (
+	RendererAuto      = "auto"      // chafa when installed, truecolor blocks otherwise
+	RendererChafa     = "chafa"     // external chafa binary
+	RendererTrueColor = "truecolor" // 24-bit colored blocks
+	Renderer256       = "256"       // xterm 256-color blocks
+	Renderer16        = "16"        // basic 16-color blocks
+	RendererBraille   = "braille"   // braille dot patterns
+)
+
+// Dithering algorithms used when quantizing art
+const (
+	DitherNone           = "none"
+	DitherOrdered        = "ordered"
+	DitherFloydSteinberg = "floyd-steinberg"
+)
+
+// RendererNames lists the accepted --renderer values
+var RendererNames = []string{RendererAuto, RendererChafa, RendererTrueColor, Renderer256, Renderer16, RendererBraille}
+
+// DitherNames lists the accepted --dither values
+var DitherNames = []string{DitherNone, DitherOrdered, DitherFloydSteinberg}
+
+// rgb is a color with float channels so quantization error can go negative
+type rgb struct {
+	r, g, b float64
+}
+
+// palette is a fixed set of terminal colors art gets quantized to
+type palette struct {
+	colors []rgb
+	codes  []string // Background escape sequence per color
+	spread float64  // Ordered dither amplitude, roughly the gap between levels
+}
+
+// bayer4 is the 4x4 Bayer threshold matrix for ordered dithering
+var bayer4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// cubeLevels are the channel intensities of the xterm 6x6x6 color cube
+var cubeLevels = []float64{0, 95, 135, 175, 215, 255}
+
+// palette256 covers the xterm color cube and grayscale ramp (16-255); the
+// first 16 colors are skipped since terminal themes redefine them
+var palette256 = func() *palette {
+	p := &palette{spread: 40}
+	for i := 16; i < 256; i++ {
+		p.colors = append(p.colors, xterm256(i))
+		p.codes = append(p.codes, fmt.Sprintf("\033[48;5;%dm", i))
+	}
+	return p
+}()
+
+// palette16 uses the xterm default values of the basic ANSI colors
+var palette16 = func() *palette {
+	values := [16][3]float64{
+		{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+		{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+		{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+
+	p := &palette{spread: 128}
+	for i, v := range values {
+		p.colors = append(p.colors, rgb{v[0], v[1], v[2]})
+		if i < 8 {
+			p.codes = append(p.codes, fmt.Sprintf("\033[%dm", 40+i))
+		} else {
+			p.codes = append(p.codes, fmt.Sprintf("\033[%dm", 100+i-8))
+		}
+	}
+	return p
+}()
+
+// paletteMono is used to decide which braille dots are lit
+var paletteMono = &palette{
+	colors: []rgb{{0, 0, 0}, {255, 255, 255}},
+	spread: 255,
+}
+
+// xterm256 returns the RGB value of an xterm 256-color index (16-255)
+func xterm256(i int) rgb {
+	if i >= 232 {
+		v := float64(8 + (i-232)*10)
+		return rgb{v, v, v}
+	}
+	i -= 16
+	return rgb{cubeLevels[i/36], cubeLevels[(i/6)%6], cubeLevels[i%6]}
+}
+
+// nearest returns the index of the palette color closest to c
+func (p *palette) nearest(c rgb) int {
+	best, bestDist := 0, math.MaxFloat64
+	for i, pc := range p.colors {
+		dr, dg, db := c.r-pc.r, c.g-pc.g, c.b-pc.b
+		// Weighted distance roughly matching perceived brightness
+		dist := 2*dr*dr + 4*dg*dg + 3*db*db
+		if dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// quantize maps every pixel to a palette index using the given dithering
+func (p *palette) quantize(img image.Image, method string) [][]int {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	buf := make([][]rgb, h)
+	for y := range buf {
+		buf[y] = make([]rgb, w)
+		for x := range buf[y] {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			buf[y][x] = rgb{float64(r >> 8), float64(g >> 8), float64(b >> 8)}
+		}
+	}
+
+	out := make([][]int, h)
+	for y := 0; y < h; y++ {
+		out[y] = make([]int, w)
+		for x := 0; x < w; x++ {
+			c := buf[y][x]
+
+			if method == DitherOrdered {
+				offset := ((bayer4[y%4][x%4]+0.5)/16 - 0.5) * p.spread
+				c = rgb{c.r + offset, c.g + offset, c.b + offset}
+			}
+
+			idx := p.nearest(clampRGB(c))
+			out[y][x] = idx
+
+			if method == DitherFloydSteinberg {
+				q := p.colors[idx]
+				diffuse(buf, x, y, w, h, rgb{c.r - q.r, c.g - q.g, c.b - q.b})
+			}
+		}
+	}
+
+	return out
+}
+
+// diffuse spreads quantization error to unvisited neighbours (Floyd–Steinberg)
+func diffuse(buf [][]rgb, x, y, w, h int, e rgb) {
+	add := func(dx, dy int, weight float64) {
+		nx, ny := x+dx, y+dy
+		if nx < 0 || nx >= w || ny >= h {
+			return
+		}
+		c := &buf[ny][nx]
+		c.r += e.r * weight
+		c.g += e.g * weight
+		c.b += e.b * weight
+	}
+
+	add(1, 0, 7.0/16)
+	add(-1, 1, 3.0/16)
+	add(0, 1, 5.0/16)
+	add(1, 1, 1.0/16)
+}
+
+// clampRGB keeps channels within 0-255
+func clampRGB(c rgb) rgb {
+	clamp := func(v float64) float64 {
+		return math.Max(0, math.Min(255, v))
+	}
+	return rgb{clamp(c.r), clamp(c.g), clamp(c.b)}
+}
+
+// getPalettedLines renders the image as blocks limited to a palette
+func (r *ImageRenderer) getPalettedLines(img image.Image, p *palette) []string {
+	resized := imaging.Resize(img, r.width, r.height, imaging.Lanczos)
+	indexes := p.quantize(resized, r.ditherMethod())
+
+	lines := make([]string, 0, len(indexes))
+	for _, row := range indexes {
+		var line strings.Builder
+		line.WriteString(" ") // Left padding
+
+		for _, idx := range row {
+			line.WriteString(p.codes[idx] + "  " + ColorReset)
+		}
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
+
+// brailleDots maps a dot position within a 2x4 cell to its braille bit
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// getBrailleLines renders the image as braille dots, each cell colored with
+// the nearest 256-color to the average of its lit pixels
+func (r *ImageRenderer) getBrailleLines(img image.Image) []string {
+	// Each cell covers 2x4 dots and the art spans width*2 columns
+	cols, rows := r.width*2, r.height
+	resized := imaging.Resize(img, cols*2, rows*4, imaging.Lanczos)
+	dots := paletteMono.quantize(resized, r.ditherMethod())
+
+	lines := make([]string, 0, rows)
+	for cy := 0; cy < rows; cy++ {
+		var line strings.Builder
+		line.WriteString(" ") // Left padding
+
+		for cx := 0; cx < cols; cx++ {
+			var cell rune
+			var sum rgb
+			lit := 0
+
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					px, py := cx*2+dx, cy*4+dy
+					if dots[py][px] == 0 {
+						continue
+					}
+					cell |= brailleDots[dy][dx]
+					r8, g8, b8, _ := resized.At(px, py).RGBA()
+					sum.r += float64(r8 >> 8)
+					sum.g += float64(g8 >> 8)
+					sum.b += float64(b8 >> 8)
+					lit++
+				}
+			}
+
+			if lit == 0 {
+				line.WriteString(" ")
+				continue
+			}
+
+			avg := rgb{sum.r / float64(lit), sum.g / float64(lit), sum.b / float64(lit)}
+			fg := 16 + palette256.nearest(avg)
+			line.WriteString(fmt.Sprintf("\033[38;5;%dm%c%s", fg, 0x2800+cell, ColorReset))
+		}
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
+
+// ditherMethod resolves the dithering to use, defaulting to Floyd–Steinberg
+func (r *ImageRenderer) ditherMethod() string {
+	if r.dither == "" {
+		return DitherFloydSteinberg
+	}
+	return r.dither
+}
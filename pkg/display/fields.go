@@ -0,0 +1,284 @@
+package display
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+	"github.com/ashish0kumar/mufetch/pkg/cache"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// FieldNames lists every selectable info field across tracks, albums and artists
+var FieldNames = []string{
+	"name", "artist", "album", "type", "duration", "track", "tracks", "explicit",
+	"released", "popularity", "followers", "genres", "label", "albums", "singles",
+	"top_tracks", "tracklist", "show", "publisher", "progress", "quality", "license",
+	"venue", "taper", "recording", "markets", "rights", "isrc", "upc",
+	"compilations", "appears_on",
+}
+
+// optionalFields are only shown when named in Options.Fields or with
+// Options.Full
+var optionalFields = map[string]bool{
+	"markets": true,
+	"rights":  true,
+	"isrc":    true,
+	"upc":     true,
+
+	// Each costs the artist card another request
+	"compilations": true,
+	"appears_on":   true,
+}
+
+// Options controls what gets rendered and how
+type Options struct {
+	ImageSize int
+	Fields    []string  // Field keys in display order; empty shows the defaults
+	NoImage   bool      // Skip downloading and rendering art entirely
+	Theme     *Theme    // Colors to render with; nil uses DefaultTheme
+	Renderer  string    // Art renderer mode, see RendererNames
+	Dither    string    // Dithering for low-color renderers, see DitherNames
+	Crop      string    // How non-square art is cropped, see CropNames
+	Aspect    float64   // Height-to-width ratio of a terminal cell; 0 assumes 2
+	Spinner   *Spinner  // Stopped right before the card is printed
+	Source    string    // Display name of the provider, used for page links
+	Logo      []string  // Text-art lines shown instead of the art, see LoadLogo
+	Icons     bool      // Prefix fields with Nerd Font glyphs
+	MaxWidth  int       // Longest value before it's cut; 0 uses defaultMaxWidth
+	Wrap      bool      // Wrap long values onto more lines instead of cutting them
+	Full      bool      // Also show the optional fields, like markets and rights
+	Tracks    bool      // List every track of an album with its number and length
+	Swatches  bool      // Show a strip of the art's dominant colors under it
+	Lyrics    []string  // Lyrics excerpt shown beside or below the card
+	LyricsErr error     // Why the lyrics failed to load; marks the panel unavailable
+	PNGPath   string    // Also rasterize the card to this PNG file
+	NoColor   bool      // Print without colors, links or other escape codes
+	NoLinks   bool      // Print link text without the OSC 8 hyperlink around it
+	Locale    string    // Formats dates and counts, e.g. de-DE; empty uses English
+	Market    string    // Country code availability is checked for; empty uses US
+	Out       io.Writer // Where cards are printed; nil uses stdout
+
+	ImageCache *cache.Cache    // Keeps downloaded art on disk; nil always downloads
+	Context    context.Context // Cancels API calls and art downloads; nil never cancels
+}
+
+// ctx returns the context for requests made while rendering
+func (o Options) ctx() context.Context {
+	if o.Context == nil {
+		return context.Background()
+	}
+	return o.Context
+}
+
+// field is a named block of info lines that can be selected and reordered
+type field struct {
+	key   string
+	lines []string
+}
+
+// IsValidField reports whether name refers to a known info field
+func IsValidField(name string) bool {
+	for _, f := range FieldNames {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// wants reports whether the field with the given key will be displayed
+func (o Options) wants(key string) bool {
+	if len(o.Fields) == 0 {
+		return o.Full || !optionalFields[key]
+	}
+	for _, f := range o.Fields {
+		if f == key {
+			return true
+		}
+	}
+	return false
+}
+
+// selectFields flattens fields into info lines honoring the requested order
+func (o Options) selectFields(fields []field) []string {
+	var lines []string
+
+	if o.Icons {
+		for i := range fields {
+			fields[i] = withIcon(fields[i])
+		}
+	}
+
+	if len(o.Fields) == 0 {
+		for _, f := range fields {
+			if o.wants(f.key) {
+				lines = append(lines, f.lines...)
+			}
+		}
+		return lines
+	}
+
+	// Fields the entity doesn't have (e.g. "followers" on a track) are skipped
+	for _, key := range o.Fields {
+		for _, f := range fields {
+			if f.key == key {
+				lines = append(lines, f.lines...)
+				break
+			}
+		}
+	}
+	return lines
+}
+
+// defaultMaxWidth is the value width used when Options.MaxWidth is unset
+const defaultMaxWidth = 50
+
+// infoField creates a label-value field in the field's theme color, cutting
+// or wrapping values wider than the configured width
+func (o Options) infoField(key, label, value, color string) field {
+	color = o.fieldColor(key, color)
+
+	// Wrapping would break links across lines, so linked values are cut
+	// like in the default mode instead
+	if !o.Wrap || ansi.Strip(value) != value {
+		value = truncate(value, o.maxWidth())
+		return field{key: key, lines: []string{formatInfoLine(label, value, color)}}
+	}
+
+	rows := wrapText(value, o.maxWidth())
+	lines := []string{formatInfoLine(label, rows[0], color)}
+	indent := strings.Repeat(" ", labelColumnWidth)
+	for _, row := range rows[1:] {
+		lines = append(lines, indent+color+row+ColorReset)
+	}
+	return field{key: key, lines: lines}
+}
+
+// maxWidth resolves the value width limit
+func (o Options) maxWidth() int {
+	if o.MaxWidth > 0 {
+		return o.MaxWidth
+	}
+	return defaultMaxWidth
+}
+
+// fieldColor returns the theme's color override for a field or the fallback
+func (o Options) fieldColor(key, fallback string) string {
+	if color, ok := o.theme().FieldColors[key]; ok {
+		return color
+	}
+	return fallback
+}
+
+// trackLinksField lists up to 5 tracks as clickable links under a heading,
+// as the field with the given key
+func (o Options) trackLinksField(key, heading string, tracks []spotify.Track) field {
+	color := o.fieldColor(key, ColorGreen)
+	lines := []string{"", fmt.Sprintf("%s%s%s", ColorBold, heading, ColorReset)}
+
+	for i, track := range tracks {
+		if i >= 5 {
+			break
+		}
+		trackLink := createClickableLink(track.ExternalURL.URL(), track.Name)
+		lines = append(lines, fmt.Sprintf("%s%s%s%s", color, trackLink, ColorReset, o.explicitMark(track)))
+	}
+
+	return field{key: key, lines: lines}
+}
+
+// explicitMark tags explicit tracks in lists with " [E]", like Spotify's
+// badge, and is empty for clean ones
+func (o Options) explicitMark(track spotify.Track) string {
+	if !track.Explicit {
+		return ""
+	}
+	return " " + o.badColor() + "[E]" + ColorReset
+}
+
+// marketsField says whether the item can be played in opts.Market and in
+// how many markets it's available ("Yes in US · 184 markets")
+func (o Options) marketsField(markets []string, restrictions spotify.Restrictions) field {
+	market := strings.ToUpper(o.Market)
+	if market == "" {
+		market = "US"
+	}
+
+	available := restrictions.Reason == ""
+	if available {
+		available = false
+		for _, m := range markets {
+			if m == market {
+				available = true
+				break
+			}
+		}
+	}
+
+	value := fmt.Sprintf("Yes in %s", market)
+	color := o.goodColor()
+	if !available {
+		value = fmt.Sprintf("No in %s", market)
+		color = o.badColor()
+		if restrictions.Reason != "" {
+			value += fmt.Sprintf(" (%s restriction)", restrictions.Reason)
+		}
+	}
+	return o.infoField("markets", "Available", value+" · "+pluralize(len(markets), "market"), color)
+}
+
+// rightsField lists the ℗ and © lines under a heading, along with the
+// label unless the label field already shows it
+func (o Options) rightsField(label string, copyrights []spotify.Copyright) field {
+	color := o.fieldColor("rights", ColorWhite)
+	lines := []string{"", fmt.Sprintf("%sRights%s", ColorBold, ColorReset)}
+
+	if label != "" && !o.wants("label") {
+		lines = append(lines, o.infoField("rights", "Label", label, color).lines...)
+	}
+	for _, c := range copyrights {
+		symbol, text := copyrightSymbol(c)
+		lines = append(lines, o.infoField("rights", symbol, text, color).lines...)
+	}
+	return field{key: "rights", lines: lines}
+}
+
+// copyrightSymbol returns ℗ for sound recording and © for other
+// copyrights, with any "(P)" or "©" the text starts with removed
+func copyrightSymbol(c spotify.Copyright) (string, string) {
+	symbol := "©"
+	if c.Type == "P" {
+		symbol = "℗"
+	}
+	text := strings.TrimSpace(c.Text)
+	for _, prefix := range []string{"℗", "©", "(P)", "(C)", "(p)", "(c)"} {
+		text = strings.TrimSpace(strings.TrimPrefix(text, prefix))
+	}
+	return symbol, text
+}
+
+// unavailableField marks a field whose data failed to load, logging why
+func (o Options) unavailableField(key, label string, err error) field {
+	slog.Debug("field unavailable", "field", key, "err", err)
+	return field{key: key, lines: []string{formatInfoLine(label, "unavailable", ColorDim)}}
+}
+
+// unavailableSection marks a headed section like the top tracks whose data
+// failed to load, logging why
+func (o Options) unavailableSection(key, heading string, err error) field {
+	slog.Debug("section unavailable", "field", key, "err", err)
+	return field{key: key, lines: []string{"", ColorBold + heading + ColorReset, ColorDim + "unavailable" + ColorReset}}
+}
+
+// out returns the writer cards are printed to
+func (o Options) out() io.Writer {
+	if o.Out != nil {
+		return o.Out
+	}
+	return os.Stdout
+}
@@ -1,17 +1,29 @@
+// Package display renders tracks, albums and artists as cards with cover
+// art, writing to Options.Out or stdout.
 package display
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"image"
+	"image/png"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+	"github.com/ashish0kumar/mufetch/pkg/cache"
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
 	"github.com/ashish0kumar/mufetch/pkg/spotify"
 	"github.com/disintegration/imaging"
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/sync/errgroup"
 )
 
 // ANSI color codes for terminal output
@@ -25,12 +37,21 @@ const (
 	ColorCyan   = "\033[36m"
 	ColorWhite  = "\033[37m"
 	ColorBold   = "\033[1m"
+	ColorDim    = "\033[2m"
 )
 
 // ImageRenderer handles terminal image rendering using chafa if available
 type ImageRenderer struct {
 	width  int
 	height int
+	mode   string  // One of the Renderer* modes; empty means auto
+	dither string  // One of the Dither* methods; empty uses the mode's default
+	crop   string  // One of the Crop* modes; empty means center
+	aspect float64 // Height-to-width ratio of a cell; 0 uses defaultCellAspect
+	swatch bool    // Show the dominant colors under the art
+
+	cache *cache.Cache    // Keeps downloaded art on disk; nil always downloads
+	ctx   context.Context // Cancels the download; nil never cancels
 }
 
 // NewImageRenderer creates an image renderer with specified size
@@ -47,17 +68,39 @@ func (r *ImageRenderer) RenderImageLines(imageURL string) []string {
 		return r.getPlaceholderLines()
 	}
 
-	// Try chafa first if available
-	if r.isChafaAvailable() {
-		if lines := r.renderWithChafa(imageURL); lines != nil {
+	img, err := r.downloadImage(imageURL)
+	if err != nil {
+		slog.Debug("art unavailable", "url", imageURL, "err", err)
+		return r.placeholderLines("ART", "UNAVAILABLE", ColorDim)
+	}
+
+	// Crop to the art area's shape instead of squashing non-square images
+	img = cropToAspect(img, r.artAspect(), r.crop)
+
+	lines := r.renderArt(img)
+	if r.swatch {
+		lines = append(lines, r.getSwatchLines(img)...)
+	}
+	return lines
+}
+
+// renderArt draws the image with the configured renderer
+func (r *ImageRenderer) renderArt(img image.Image) []string {
+	// Try chafa first if available (or explicitly requested)
+	if (r.mode == "" || r.mode == RendererAuto || r.mode == RendererChafa) && r.isChafaAvailable() {
+		if lines := r.renderWithChafa(img); lines != nil {
 			return lines
 		}
 	}
 
 	// Fallback to enhanced ANSI block art
-	img, err := r.downloadImage(imageURL)
-	if err != nil {
-		return r.getPlaceholderLines()
+	switch r.mode {
+	case Renderer256:
+		return r.getPalettedLines(img, palette256)
+	case Renderer16:
+		return r.getPalettedLines(img, palette16)
+	case RendererBraille:
+		return r.getBrailleLines(img)
 	}
 
 	return r.getBlockArtLines(img)
@@ -70,8 +113,8 @@ func (r *ImageRenderer) isChafaAvailable() bool {
 }
 
 // renderWithChafa uses chafa command to render image
-func (r *ImageRenderer) renderWithChafa(imageURL string) []string {
-	tempFile, err := r.downloadToTemp(imageURL)
+func (r *ImageRenderer) renderWithChafa(img image.Image) []string {
+	tempFile, err := r.writeToTemp(img)
 	if err != nil {
 		return nil
 	}
@@ -79,7 +122,8 @@ func (r *ImageRenderer) renderWithChafa(imageURL string) []string {
 
 	cmd := exec.Command("chafa",
 		"--size", fmt.Sprintf("%dx%d", r.width*2, r.height),
-		"--dither", "ordered", // Slightly smoother gradients
+		"--font-ratio", fmt.Sprintf("%.3f", 1/r.cellAspect()),
+		"--dither", r.chafaDither(),
 		tempFile)
 
 	output, err := cmd.Output()
@@ -105,27 +149,26 @@ func (r *ImageRenderer) renderWithChafa(imageURL string) []string {
 	return lines
 }
 
-// downloadToTemp downloads image to a temporary file
-func (r *ImageRenderer) downloadToTemp(imageURL string) (string, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Get(imageURL)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+// chafaDither maps the dither setting to chafa's names
+func (r *ImageRenderer) chafaDither() string {
+	switch r.dither {
+	case DitherNone:
+		return "none"
+	case DitherFloydSteinberg:
+		return "diffusion"
 	}
+	return "ordered" // Slightly smoother gradients
+}
 
-	tempFile, err := os.CreateTemp("", "mufetch-*.jpg")
+// writeToTemp saves the (cropped) image as a temporary PNG for chafa
+func (r *ImageRenderer) writeToTemp(img image.Image) (string, error) {
+	tempFile, err := os.CreateTemp("", "mufetch-*.png")
 	if err != nil {
 		return "", err
 	}
 	defer tempFile.Close()
 
-	_, err = io.Copy(tempFile, resp.Body)
-	if err != nil {
+	if err := png.Encode(tempFile, img); err != nil {
 		os.Remove(tempFile.Name())
 		return "", err
 	}
@@ -133,15 +176,83 @@ func (r *ImageRenderer) downloadToTemp(imageURL string) (string, error) {
 	return tempFile.Name(), nil
 }
 
-// downloadImage fetches and decodes image from URL
+// maxImageBytes caps a cover download; Spotify's largest art is well below it
+const maxImageBytes = 20 << 20
+
+// imageRecheck is how long cached art is used before asking the server
+// whether it changed
+const imageRecheck = 7 * 24 * time.Hour
+
+// downloadImage fetches and decodes image from URL, from the image cache when
+// it has been shown before
 func (r *ImageRenderer) downloadImage(url string) (image.Image, error) {
-	resp, err := http.Get(url)
+	var cached *cache.Entry
+	if r.cache != nil {
+		if e, err := r.cache.GetEntry(cache.Images, url); err == nil {
+			cached = e
+			if time.Since(e.Checked) < imageRecheck {
+				if img, _, err := image.Decode(bytes.NewReader(e.Body)); err == nil {
+					slog.Debug("image cache hit", "url", url)
+					return img, nil
+				}
+				cached = nil
+			}
+		}
+	}
+
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
+	if cached != nil {
+		httpclient.Revalidate(req, cached)
+	}
+	resp, err := httpclient.Client.Do(req)
+	if err != nil {
+		// Old art beats no art when offline
+		if cached != nil {
+			return decodeImage(cached.Body, err)
+		}
+		return nil, err
+	}
 	defer resp.Body.Close()
 
-	img, _, err := image.Decode(resp.Body)
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		httpclient.Refresh(cached, resp)
+		r.cache.PutEntry(cache.Images, url, cached)
+		return decodeImage(cached.Body, nil)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Only art that decoded is worth keeping; a failed write just means
+	// downloading it again next time
+	if r.cache != nil {
+		r.cache.PutEntry(cache.Images, url, httpclient.NewEntry(resp, data))
+	}
+	return img, nil
+}
+
+// decodeImage decodes cached art, returning fetchErr instead if that fails
+func decodeImage(data []byte, fetchErr error) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil && fetchErr != nil {
+		return nil, fetchErr
+	}
 	return img, err
 }
 
@@ -176,23 +287,35 @@ func (r *ImageRenderer) getBlockArtLines(img image.Image) []string {
 
 // getPlaceholderLines creates a placeholder when no image is available
 func (r *ImageRenderer) getPlaceholderLines() []string {
-	lines := []string{
-		fmt.Sprintf(" %s┌───────────────────────────────┐%s", ColorWhite, ColorReset),
-		fmt.Sprintf(" %s│                               │%s", ColorWhite, ColorReset),
-		fmt.Sprintf(" %s│                               │%s", ColorWhite, ColorReset),
-		fmt.Sprintf(" %s│           NO IMAGE            │%s", ColorWhite, ColorReset),
-		fmt.Sprintf(" %s│           AVAILABLE           │%s", ColorWhite, ColorReset),
-		fmt.Sprintf(" %s│                               │%s", ColorWhite, ColorReset),
-		fmt.Sprintf(" %s│                               │%s", ColorWhite, ColorReset),
-		fmt.Sprintf(" %s└───────────────────────────────┘%s", ColorWhite, ColorReset),
+	return r.placeholderLines("NO IMAGE", "AVAILABLE", ColorWhite)
+}
+
+// placeholderLines draws a box with two centered lines of text, filling
+// the same cells as the art would at the renderer's size
+func (r *ImageRenderer) placeholderLines(top, bottom, color string) []string {
+	inner := max(r.width*2-2, 0)
+	center := func(text string) string {
+		text = runewidth.Truncate(text, inner, "")
+		left := (inner - runewidth.StringWidth(text)) / 2
+		return strings.Repeat(" ", left) + text + strings.Repeat(" ", inner-left-runewidth.StringWidth(text))
+	}
+	row := func(content string) string {
+		return fmt.Sprintf(" %s│%s│%s", color, content, ColorReset)
 	}
 
-	// Pad to match image height
-	for len(lines) < 20 {
-		lines = append(lines, strings.Repeat(" ", 31))
+	// Text sits in the middle of the rows between the borders
+	rows := make([]string, max(r.height-2, 0))
+	for i := range rows {
+		rows[i] = row(strings.Repeat(" ", inner))
+	}
+	if mid := (len(rows) - 2) / 2; mid >= 0 {
+		rows[mid] = row(center(top))
+		rows[mid+1] = row(center(bottom))
 	}
 
-	return lines
+	lines := []string{fmt.Sprintf(" %s┌%s┐%s", color, strings.Repeat("─", inner), ColorReset)}
+	lines = append(lines, rows...)
+	return append(lines, fmt.Sprintf(" %s└%s┘%s", color, strings.Repeat("─", inner), ColorReset))
 }
 
 // createClickableLink creates terminal hyperlink using ANSI escape codes
@@ -201,81 +324,84 @@ func createClickableLink(url, text string) string {
 }
 
 // DisplayTrack renders track information with album art
-func DisplayTrack(track spotify.Track, client *spotify.Client, imageSize int) {
-	renderer := NewImageRenderer(imageSize)
-
-	var imageLines []string
-	if len(track.Album.Images) > 0 {
-		imageLines = renderer.RenderImageLines(track.Album.Images[0].URL)
-	} else {
-		imageLines = renderer.getPlaceholderLines()
-	}
+func DisplayTrack(track spotify.Track, client *spotify.Client, opts Options) {
+	imageLines := opts.artLines(track.Album.Images)
 
 	// Create clickable artist links
 	artistNames := make([]string, len(track.Artists))
 	for i, artist := range track.Artists {
-		artistNames[i] = createClickableLink(artist.ExternalURL.Spotify, artist.Name)
+		artistNames[i] = createClickableLink(artist.ExternalURL.URL(), artist.Name)
 	}
 
 	duration := time.Duration(track.Duration) * time.Millisecond
 
 	// Get genres from album or fallback to artist genres
 	genres := track.Album.Genres
-	if len(genres) == 0 && len(track.Artists) > 0 && client != nil {
-		if artist, err := client.GetArtist(track.Artists[0].ID); err == nil {
+	var genresErr error
+	if len(genres) == 0 && len(track.Artists) > 0 && client != nil && opts.wants("genres") {
+		var artist *spotify.Artist
+		if artist, genresErr = client.GetArtist(opts.ctx(), track.Artists[0].ID); genresErr == nil {
 			genres = artist.Genres
 		}
 	}
 
 	// Create clickable album name
-	albumName := createClickableLink(track.Album.ExternalURL.Spotify, track.Album.Name)
+	albumName := createClickableLink(track.Album.ExternalURL.URL(), track.Album.Name)
+
+	fields := []field{
+		opts.infoField("name", "Name", track.Name, ColorGreen),
+		opts.infoField("artist", "Artist", strings.Join(artistNames, ", "), ColorYellow),
+		opts.infoField("album", "Album", albumName, ColorBlue),
+		opts.infoField("duration", "Duration", formatDuration(duration), ColorWhite),
+		opts.infoField("track", "Track", fmt.Sprintf("%d", track.TrackNumber), ColorCyan),
+		opts.infoField("explicit", "Explicit", formatBool(track.Explicit), ColorRed),
+		opts.infoField("released", "Released", opts.formatDate(track.Album.ReleaseDate, track.Album.ReleaseDatePrecision), ColorCyan),
+		opts.infoField("popularity", "Popularity", fmt.Sprintf("%d%%", track.Popularity), ColorPurple),
+	}
+
+	if track.License != nil {
+		fields = append(fields, opts.licenseField(*track.License))
+	}
+
+	if track.Quality != nil {
+		fields = append(fields, opts.infoField("quality", "Quality", formatQuality(*track.Quality), ColorCyan))
+	}
+
+	if isrc := track.ExternalIDs.ISRC; isrc != "" {
+		fields = append(fields, opts.infoField("isrc", "ISRC", isrc, ColorWhite))
+	}
 
-	infoLines := []string{
-		formatInfoLine("Name", track.Name, ColorGreen),
-		formatInfoLine("Artist", strings.Join(artistNames, ", "), ColorYellow),
-		formatInfoLine("Album", albumName, ColorBlue),
-		formatInfoLine("Duration", formatDuration(duration), ColorWhite),
-		formatInfoLine("Track", fmt.Sprintf("%d", track.TrackNumber), ColorCyan),
-		formatInfoLine("Explicit", formatBool(track.Explicit), ColorRed),
-		formatInfoLine("Released", formatOrdinalDate(track.Album.ReleaseDate), ColorCyan),
-		formatInfoLine("Popularity", fmt.Sprintf("%d%%", track.Popularity), ColorPurple),
+	// Only Spotify reports where a track can be played
+	if len(track.AvailableMarkets) > 0 || track.Restrictions.Reason != "" {
+		fields = append(fields, opts.marketsField(track.AvailableMarkets, track.Restrictions))
 	}
 
 	if len(genres) > 0 {
-		// Show at most 2 genres
-		displayGenres := genres
-		if len(displayGenres) > 2 {
-			displayGenres = displayGenres[:2]
-		}
-		genreString := strings.Join(displayGenres, ", ")
-		infoLines = append(infoLines, formatInfoLine("Genres", genreString, ColorRed))
+		fields = append(fields, opts.chipsField("genres", "Genres", genres))
+	} else if genresErr != nil {
+		fields = append(fields, opts.unavailableField("genres", "Genres", genresErr))
 	}
 
+	infoLines := opts.selectFields(fields)
+
 	// Prepare clickable links for bottom placement
 	var links []string
 	if len(track.Album.Images) > 0 {
 		links = append(links, fmt.Sprintf("%s%s%s", ColorBlue, createClickableLink(track.Album.Images[0].URL, "Album Cover"), ColorReset))
 	}
-	links = append(links, fmt.Sprintf("%s%s%s", ColorGreen, createClickableLink(track.ExternalURL.Spotify, "Spotify"), ColorReset))
+	links = append(links, fmt.Sprintf("%s%s%s", ColorGreen, createClickableLink(track.ExternalURL.URL(), opts.sourceLabel()), ColorReset))
 
-	displaySideBySideWithLinks(imageLines, infoLines, links)
+	opts.render(imageLines, infoLines, links)
 }
 
 // DisplayAlbum renders album information with cover art
-func DisplayAlbum(album spotify.Album, client *spotify.Client, imageSize int) {
-	renderer := NewImageRenderer(imageSize)
-
-	var imageLines []string
-	if len(album.Images) > 0 {
-		imageLines = renderer.RenderImageLines(album.Images[0].URL)
-	} else {
-		imageLines = renderer.getPlaceholderLines()
-	}
+func DisplayAlbum(album spotify.Album, client *spotify.Client, opts Options) {
+	imageLines := opts.artLines(album.Images)
 
 	// Create clickable artist links
 	artistNames := make([]string, len(album.Artists))
 	for i, artist := range album.Artists {
-		artistNames[i] = createClickableLink(artist.ExternalURL.Spotify, artist.Name)
+		artistNames[i] = createClickableLink(artist.ExternalURL.URL(), artist.Name)
 	}
 
 	// Calculate total duration from all tracks
@@ -286,173 +412,375 @@ func DisplayAlbum(album spotify.Album, client *spotify.Client, imageSize int) {
 
 	// Get genres from album or fallback to artist genres
 	genres := album.Genres
-	if len(genres) == 0 && len(album.Artists) > 0 && client != nil {
-		if artist, err := client.GetArtist(album.Artists[0].ID); err == nil {
+	var genresErr error
+	if len(genres) == 0 && len(album.Artists) > 0 && client != nil && opts.wants("genres") {
+		var artist *spotify.Artist
+		if artist, genresErr = client.GetArtist(opts.ctx(), album.Artists[0].ID); genresErr == nil {
 			genres = artist.Genres
 		}
 	}
 
-	infoLines := []string{
-		formatInfoLine("Name", album.Name, ColorGreen),
-		formatInfoLine("Artist", strings.Join(artistNames, ", "), ColorYellow),
-		formatInfoLine("Type", album.AlbumType, ColorBlue),
-		formatInfoLine("Released", formatOrdinalDate(album.ReleaseDate), ColorCyan),
-		formatInfoLine("Tracks", fmt.Sprintf("%d", album.TotalTracks), ColorPurple),
-		formatInfoLine("Duration", formatDuration(time.Duration(totalDuration)*time.Millisecond), ColorWhite),
-		formatInfoLine("Popularity", fmt.Sprintf("%d%%", album.Popularity), ColorPurple),
+	fields := []field{
+		opts.infoField("name", "Name", album.Name, ColorGreen),
+		opts.infoField("artist", "Artist", strings.Join(artistNames, ", "), ColorYellow),
+		opts.infoField("type", "Type", album.AlbumType, ColorBlue),
+		opts.infoField("released", "Released", opts.formatDate(album.ReleaseDate, album.ReleaseDatePrecision), ColorCyan),
+		opts.infoField("tracks", "Tracks", fmt.Sprintf("%d", album.TotalTracks), ColorPurple),
+		opts.explicitSummaryField(album.Tracks.Items),
+		opts.infoField("duration", "Duration", formatDuration(time.Duration(totalDuration)*time.Millisecond), ColorWhite),
+		opts.infoField("popularity", "Popularity", fmt.Sprintf("%d%%", album.Popularity), ColorPurple),
 	}
 
 	if len(genres) > 0 {
-		// Show at most 2 genres
-		displayGenres := genres
-		if len(displayGenres) > 2 {
-			displayGenres = displayGenres[:2]
-		}
-		genreString := strings.Join(displayGenres, ", ")
-		infoLines = append(infoLines, formatInfoLine("Genres", genreString, ColorRed))
+		fields = append(fields, opts.chipsField("genres", "Genres", genres))
+	} else if genresErr != nil {
+		fields = append(fields, opts.unavailableField("genres", "Genres", genresErr))
 	}
 
 	if len(album.Label) > 0 {
-		infoLines = append(infoLines, formatInfoLine("Label", formatString(album.Label), ColorWhite))
+		fields = append(fields, opts.infoField("label", "Label", formatString(album.Label), ColorWhite))
 	}
 
-	// Add top tracks with clickable links
-	if len(album.Tracks.Items) > 0 {
-		infoLines = append(infoLines, "")
-		infoLines = append(infoLines, fmt.Sprintf("%sTop Tracks%s", ColorBold, ColorReset))
+	if album.License != nil {
+		fields = append(fields, opts.licenseField(*album.License))
+	}
 
-		for i, track := range album.Tracks.Items {
-			if i >= 5 {
-				break
-			}
-			trackLink := createClickableLink(track.ExternalURL.Spotify, track.Name)
-			infoLines = append(infoLines, fmt.Sprintf("%s%s%s", ColorGreen, trackLink, ColorReset))
+	if upc := album.ExternalIDs.UPC; upc != "" {
+		fields = append(fields, opts.infoField("upc", barcodeLabel(upc), upc, ColorWhite))
+	}
+
+	if len(album.AvailableMarkets) > 0 || album.Restrictions.Reason != "" {
+		fields = append(fields, opts.marketsField(album.AvailableMarkets, album.Restrictions))
+	}
+
+	if album.Label != "" || len(album.Copyrights) > 0 {
+		fields = append(fields, opts.rightsField(album.Label, album.Copyrights))
+	}
+
+	if rec := album.Recording; rec != nil {
+		venue := rec.Venue
+		if rec.Location != "" {
+			venue = strings.TrimPrefix(venue+", "+rec.Location, ", ")
+		}
+		if venue != "" {
+			fields = append(fields, opts.infoField("venue", "Venue", venue, ColorCyan))
+		}
+		if rec.Taper != "" {
+			fields = append(fields, opts.infoField("taper", "Taper", rec.Taper, ColorYellow))
 		}
+		if rec.Source != "" {
+			fields = append(fields, opts.infoField("recording", "Source", rec.Source, ColorWhite))
+		}
+	}
+
+	// Add the tracklist with clickable links
+	if len(album.Tracks.Items) > 0 {
+		fields = append(fields, opts.albumTracksField(album.Tracks.Items))
 	}
 
+	infoLines := opts.selectFields(fields)
+
 	// Prepare clickable links for bottom placement
 	var links []string
 	if len(album.Images) > 0 {
 		links = append(links, fmt.Sprintf("%s%s%s", ColorBlue, createClickableLink(album.Images[0].URL, "Album Cover"), ColorReset))
 	}
-	links = append(links, fmt.Sprintf("%s%s%s", ColorGreen, createClickableLink(album.ExternalURL.Spotify, "Spotify"), ColorReset))
+	links = append(links, fmt.Sprintf("%s%s%s", ColorGreen, createClickableLink(album.ExternalURL.URL(), opts.sourceLabel()), ColorReset))
 
-	displaySideBySideWithLinks(imageLines, infoLines, links)
+	opts.render(imageLines, infoLines, links)
 }
 
 // DisplayArtist renders artist information with profile image
-func DisplayArtist(artist spotify.Artist, client *spotify.Client, imageSize int) {
-	renderer := NewImageRenderer(imageSize)
-
-	var imageLines []string
-	if len(artist.Images) > 0 {
-		imageLines = renderer.RenderImageLines(artist.Images[0].URL)
-	} else {
-		imageLines = renderer.getPlaceholderLines()
-	}
-
+func DisplayArtist(artist spotify.Artist, client *spotify.Client, opts Options) {
 	// Fetch additional artist data from API
+	var imageLines []string
 	var topTracks *spotify.TopTracksResponse
 	var albums *spotify.ArtistAlbumsResponse
 	var singles *spotify.ArtistAlbumsResponse
+	var compilations *spotify.ArtistAlbumsResponse
+	var appearsOn *spotify.ArtistAlbumsResponse
+	var topTracksErr, albumsErr, singlesErr, compilationsErr, appearsOnErr error
+
+	// The art and each section are fetched at once; a section that fails
+	// is marked unavailable instead of failing the card
+	var g errgroup.Group
+	g.Go(func() error {
+		imageLines = opts.artLines(artist.Images)
+		return nil
+	})
 
+	// Only hit the API for sections that will actually be shown
 	if client != nil {
-		topTracks, _ = client.GetArtistTopTracks(artist.ID)
-		albums, _ = client.GetArtistAlbums(artist.ID, "album")
-		singles, _ = client.GetArtistAlbums(artist.ID, "single")
+		if opts.wants("top_tracks") {
+			g.Go(func() error {
+				topTracks, topTracksErr = client.GetArtistTopTracks(opts.ctx(), artist.ID)
+				return nil
+			})
+		}
+		if opts.wants("albums") {
+			g.Go(func() error {
+				albums, albumsErr = client.GetArtistAlbums(opts.ctx(), artist.ID, "album")
+				return nil
+			})
+		}
+		if opts.wants("singles") {
+			g.Go(func() error {
+				singles, singlesErr = client.GetArtistAlbums(opts.ctx(), artist.ID, "single")
+				return nil
+			})
+		}
+		if opts.wants("compilations") {
+			g.Go(func() error {
+				compilations, compilationsErr = client.GetArtistAlbums(opts.ctx(), artist.ID, "compilation")
+				return nil
+			})
+		}
+		if opts.wants("appears_on") {
+			g.Go(func() error {
+				appearsOn, appearsOnErr = client.GetArtistAlbums(opts.ctx(), artist.ID, "appears_on")
+				return nil
+			})
+		}
 	}
+	g.Wait()
 
-	infoLines := []string{
-		formatInfoLine("Name", artist.Name, ColorGreen),
-		formatInfoLine("Followers", formatNumber(artist.Followers.Total), ColorYellow),
-		formatInfoLine("Popularity", fmt.Sprintf("%d%%", artist.Popularity), ColorPurple),
+	fields := []field{
+		opts.infoField("name", "Name", artist.Name, ColorGreen),
+		opts.infoField("followers", "Followers", opts.formatCount(artist.Followers.Total), ColorYellow),
+		opts.infoField("popularity", "Popularity", fmt.Sprintf("%d%%", artist.Popularity), ColorPurple),
 	}
 
 	if len(artist.Genres) > 0 {
-		displayGenres := artist.Genres
-		if len(displayGenres) > 2 {
-			displayGenres = displayGenres[:2]
-		}
-		genreString := strings.Join(displayGenres, ", ")
-		infoLines = append(infoLines, formatInfoLine("Genres", genreString, ColorRed))
+		fields = append(fields, opts.chipsField("genres", "Genres", artist.Genres))
 	}
 
 	if albums != nil {
-		infoLines = append(infoLines, formatInfoLine("Albums", fmt.Sprintf("%d", albums.Total), ColorGreen))
+		fields = append(fields, opts.infoField("albums", "Albums", fmt.Sprintf("%d", albums.Total), ColorGreen))
+	} else if albumsErr != nil {
+		fields = append(fields, opts.unavailableField("albums", "Albums", albumsErr))
 	}
 
 	if singles != nil {
-		infoLines = append(infoLines, formatInfoLine("Singles", fmt.Sprintf("%d", singles.Total), ColorYellow))
+		fields = append(fields, opts.infoField("singles", "Singles", fmt.Sprintf("%d", singles.Total), ColorYellow))
+	} else if singlesErr != nil {
+		fields = append(fields, opts.unavailableField("singles", "Singles", singlesErr))
+	}
+
+	if compilations != nil {
+		fields = append(fields, opts.infoField("compilations", "Compilations", fmt.Sprintf("%d", compilations.Total), ColorPurple))
+	} else if compilationsErr != nil {
+		fields = append(fields, opts.unavailableField("compilations", "Compilations", compilationsErr))
+	}
+
+	if appearsOn != nil {
+		fields = append(fields, opts.infoField("appears_on", "Appears On", fmt.Sprintf("%d", appearsOn.Total), ColorCyan))
+	} else if appearsOnErr != nil {
+		fields = append(fields, opts.unavailableField("appears_on", "Appears On", appearsOnErr))
 	}
 
 	// Add top tracks with clickable links
 	if topTracks != nil && len(topTracks.Tracks) > 0 {
-		infoLines = append(infoLines, "")
-		infoLines = append(infoLines, fmt.Sprintf("%sTop Tracks%s", ColorBold, ColorReset))
-
-		for i, track := range topTracks.Tracks {
-			if i >= 5 {
-				break
-			}
-			trackLink := createClickableLink(track.ExternalURL.Spotify, track.Name)
-			infoLines = append(infoLines, fmt.Sprintf("%s%s%s", ColorGreen, trackLink, ColorReset))
-		}
+		fields = append(fields, opts.trackLinksField("top_tracks", "Top Tracks", topTracks.Tracks))
+	} else if topTracksErr != nil {
+		fields = append(fields, opts.unavailableSection("top_tracks", "Top Tracks", topTracksErr))
 	}
 
+	infoLines := opts.selectFields(fields)
+
 	// Prepare clickable links for bottom placement
 	var links []string
-	links = append(links, fmt.Sprintf("%s%s%s", ColorGreen, createClickableLink(artist.ExternalURL.Spotify, "Spotify"), ColorReset))
+	links = append(links, fmt.Sprintf("%s%s%s", ColorGreen, createClickableLink(artist.ExternalURL.URL(), opts.sourceLabel()), ColorReset))
 	if len(artist.Images) > 0 {
 		links = append(links, fmt.Sprintf("%s%s%s", ColorBlue, createClickableLink(artist.Images[0].URL, "Artist Photo"), ColorReset))
 	}
 
-	displaySideBySideWithLinks(imageLines, infoLines, links)
+	opts.render(imageLines, infoLines, links)
 }
 
-// displaySideBySideWithLinks renders image and info side-by-side with links at bottom
-func displaySideBySideWithLinks(imageLines, infoLines, links []string) {
+// DisplayEpisode renders a podcast episode with show art and playback progress
+func DisplayEpisode(episode spotify.Episode, progress time.Duration, opts Options) {
+	// Episodes usually carry their own art, otherwise use the show's
+	images := episode.Images
+	if len(images) == 0 {
+		images = episode.Show.Images
+	}
+
+	imageLines := opts.artLines(images)
+
+	duration := time.Duration(episode.Duration) * time.Millisecond
+	showName := createClickableLink(episode.Show.ExternalURL.URL(), episode.Show.Name)
+
+	fields := []field{
+		opts.infoField("name", "Episode", episode.Name, ColorGreen),
+		opts.infoField("show", "Show", showName, ColorYellow),
+		opts.infoField("publisher", "Publisher", formatString(episode.Show.Publisher), ColorBlue),
+		opts.infoField("progress", "Progress", formatProgress(progress, duration), ColorWhite),
+		opts.infoField("released", "Released", opts.formatDate(episode.ReleaseDate, episode.ReleaseDatePrecision), ColorCyan),
+		opts.infoField("explicit", "Explicit", formatBool(episode.Explicit), ColorRed),
+	}
+
+	infoLines := opts.selectFields(fields)
+
+	// Prepare clickable links for bottom placement
+	var links []string
+	if len(images) > 0 {
+		links = append(links, fmt.Sprintf("%s%s%s", ColorBlue, createClickableLink(images[0].URL, "Cover"), ColorReset))
+	}
+	links = append(links, fmt.Sprintf("%s%s%s", ColorGreen, createClickableLink(episode.ExternalURL.URL(), opts.sourceLabel()), ColorReset))
+
+	opts.render(imageLines, infoLines, links)
+}
+
+// artLines renders the custom logo if set, otherwise the first image, a
+// placeholder when there is none, or nothing at all in text-only mode
+func (o Options) artLines(images []spotify.Image) []string {
+	if o.Logo != nil {
+		return o.Logo
+	}
+	if o.NoImage {
+		return nil
+	}
+
+	renderer := o.newRenderer(o.ImageSize)
+	renderer.swatch = o.Swatches
+	if len(images) > 0 {
+		return renderer.RenderImageLines(images[0].URL)
+	}
+	return renderer.getPlaceholderLines()
+}
+
+// newRenderer creates an image renderer of the given size with the art
+// options applied
+func (o Options) newRenderer(size int) *ImageRenderer {
+	renderer := NewImageRenderer(size)
+	renderer.mode = o.Renderer
+	renderer.dither = o.Dither
+	renderer.crop = o.Crop
+	renderer.cache = o.ImageCache
+	renderer.ctx = o.Context
+	renderer.setAspect(o.Aspect)
+	return renderer
+}
+
+// render prints the card, side by side with art unless in text-only mode
+func (o Options) render(imageLines, infoLines, links []string) {
+	o.Spinner.Stop()
+
+	var lines []string
+	if o.NoImage && o.Logo == nil {
+		lines = composeTextOnly(infoLines, links)
+	} else {
+		lines = composeSideBySide(o.frameArt(imageLines), infoLines, links, o.sourceLabel())
+	}
+	lines = o.withLyrics(lines)
+
+	if style := o.theme().Frame; style != "" && style != FrameNone {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		// Blank last row mirrors the spare last row of the art column
+		lines = append(frameLines(lines, style, 1), "")
+	}
+
+	for _, line := range lines {
+		o.println(line)
+	}
+
+	if o.PNGPath != "" {
+		if err := SavePNG(o.PNGPath, trimBlankLines(lines)); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save PNG: %v\n", err)
+		}
+	}
+}
+
+// sourceLabel names the source for the entity page link
+func (o Options) sourceLabel() string {
+	if o.Source != "" {
+		return o.Source
+	}
+	return "Spotify"
+}
+
+// println prints a line, dropping escape codes in no-color mode
+func (o Options) println(line string) {
+	if o.NoColor {
+		line = strings.TrimRight(ansi.Strip(line), " ")
+	} else if o.NoLinks {
+		line = ansi.StripLinks(line)
+	}
+	fmt.Fprintln(o.out(), line)
+}
+
+// composeTextOnly lays out the info pane followed by the links line
+func composeTextOnly(infoLines, links []string) []string {
+	var lines []string
+	for _, line := range infoLines {
+		lines = append(lines, " "+line)
+	}
+
+	if len(links) > 0 {
+		lines = append(lines, "", " "+strings.Join(links, "   "))
+	}
+
+	// Trailing line mirrors the spare last row of the art column
+	return append(lines, "")
+}
+
+// composeSideBySide lays out image and info side-by-side with links at
+// bottom; the link labelled with source is placed first
+func composeSideBySide(imageLines, infoLines, links []string, source string) []string {
 	maxLines := len(imageLines)
 	if len(infoLines) > maxLines {
 		maxLines = len(infoLines)
 	}
 
-	// Pad info lines to match image height minus 2 for link placement
+	// Pad info lines to match image height minus 2 for link placement,
+	// never dropping info that runs past the art
 	targetLines := maxLines - 2
+	if targetLines < len(infoLines) {
+		targetLines = len(infoLines)
+	}
 	for len(infoLines) < targetLines {
 		infoLines = append(infoLines, "")
 	}
 
+	// Blank filler keeps info aligned once the art column runs out
+	filler := ""
+	if len(imageLines) > 0 {
+		filler = strings.Repeat(" ", ansi.Width(imageLines[0]))
+	}
+
+	var lines []string
+
 	// Display main content side by side
 	for i := 0; i < targetLines; i++ {
 		if i < len(imageLines) {
-			fmt.Printf("%s   %s\n", imageLines[i], infoLines[i])
+			lines = append(lines, fmt.Sprintf("%s   %s", imageLines[i], infoLines[i]))
 		} else {
-			fmt.Printf("%s   %s\n", strings.Repeat(" ", 46), infoLines[i])
+			lines = append(lines, fmt.Sprintf("%s   %s", filler, infoLines[i]))
 		}
 	}
 
 	// Display links 2 spaces up from bottom
 	if len(links) > 0 {
-		var imageLine string
+		imageLine := filler
 		if targetLines < len(imageLines) {
 			imageLine = imageLines[targetLines]
-		} else {
-			imageLine = strings.Repeat(" ", 46)
 		}
 
-		// Separate Spotify and image links for consistent ordering
-		var spotifyLink, imageLink string
+		// Separate source page and image links for consistent ordering
+		var pageLink, imageLink string
 		for _, link := range links {
-			if strings.Contains(strings.ToLower(link), "spotify") {
-				spotifyLink = link
+			if strings.Contains(link, "\033\\"+source+"\033") {
+				pageLink = link
 			} else {
 				imageLink = link
 			}
 		}
 
 		// Fallback if any link is missing
-		if spotifyLink == "" && len(links) > 0 {
-			spotifyLink = links[0]
+		if pageLink == "" && len(links) > 0 {
+			pageLink = links[0]
 		}
 		if imageLink == "" && len(links) > 1 {
 			imageLink = links[1]
@@ -460,43 +788,55 @@ func displaySideBySideWithLinks(imageLines, infoLines, links []string) {
 
 		// Format link line with proper spacing
 		var linkLine string
-		if spotifyLink != "" && imageLink != "" {
-			linkLine = fmt.Sprintf("%s   %s", spotifyLink, imageLink)
-		} else if spotifyLink != "" {
-			linkLine = spotifyLink
+		if pageLink != "" && imageLink != "" {
+			linkLine = fmt.Sprintf("%s   %s", pageLink, imageLink)
+		} else if pageLink != "" {
+			linkLine = pageLink
 		} else {
 			linkLine = imageLink
 		}
 
-		fmt.Printf("%s   %s\n", imageLine, linkLine)
+		lines = append(lines, fmt.Sprintf("%s   %s", imageLine, linkLine))
 
 		// Display remaining image lines after links
 		for i := targetLines + 1; i < len(imageLines); i++ {
-			fmt.Printf("%s   \n", imageLines[i])
+			lines = append(lines, imageLines[i]+"   ")
+		}
+
+		// Keep a spare last row when the art ran out before the links
+		if targetLines+1 >= len(imageLines) {
+			lines = append(lines, "")
 		}
 	} else {
 		// If no links, display remaining image lines normally
 		for i := targetLines; i < len(imageLines); i++ {
-			fmt.Printf("%s   \n", imageLines[i])
+			lines = append(lines, imageLines[i]+"   ")
 		}
 	}
+
+	return lines
 }
 
+// Label column layout shared by all info lines
+const (
+	minLabelPadding  = 2
+	maxLabelWidth    = 12
+	labelColumnWidth = maxLabelWidth + minLabelPadding
+)
+
 // formatInfoLine creates consistently formatted label-value pairs
 func formatInfoLine(label, value, color string) string {
-	const minPadding = 2
-	const maxLabelWidth = 12
+	return fmt.Sprintf("%s%s%s%s", formatLabel(label), color, value, ColorReset)
+}
 
-	labelWidth := len(label)
-	padding := maxLabelWidth - labelWidth + minPadding
-	if padding < minPadding {
-		padding = minPadding
+// formatLabel renders a bold label padded to the label column width
+func formatLabel(label string) string {
+	padding := labelColumnWidth - runewidth.StringWidth(label)
+	if padding < minLabelPadding {
+		padding = minLabelPadding
 	}
 
-	return fmt.Sprintf("%s%s%s%s%s%s%s",
-		ColorBold, label, ColorReset,
-		strings.Repeat(" ", padding),
-		color, value, ColorReset)
+	return fmt.Sprintf("%s%s%s%s", ColorBold, label, ColorReset, strings.Repeat(" ", padding))
 }
 
 // formatOrdinalDate converts date string to ordinal format (1st Jan 2020)
@@ -546,14 +886,55 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%d:%02d", minutes, seconds)
 }
 
+// formatProgress renders elapsed/total time with a small progress bar
+func formatProgress(progress, total time.Duration) string {
+	const barWidth = 10
+
+	filled := 0
+	if total > 0 {
+		filled = int(float64(barWidth) * float64(progress) / float64(total))
+	}
+	if filled > barWidth {
+		filled = barWidth
+	}
+
+	bar := strings.Repeat("━", filled) + strings.Repeat("─", barWidth-filled)
+	return fmt.Sprintf("%s %s / %s", bar, formatDuration(progress), formatDuration(total))
+}
+
+// formatQuality summarizes audio encoding (FLAC 24-bit/96kHz · 2304 kbps)
+func formatQuality(q spotify.AudioQuality) string {
+	var parts []string
+	if q.Codec != "" {
+		parts = append(parts, strings.ToUpper(q.Codec))
+	}
+
+	switch {
+	case q.BitDepth > 0 && q.SampleRate > 0:
+		parts = append(parts, fmt.Sprintf("%d-bit/%skHz", q.BitDepth, formatKHz(q.SampleRate)))
+	case q.SampleRate > 0:
+		parts = append(parts, formatKHz(q.SampleRate)+"kHz")
+	}
+
+	summary := strings.Join(parts, " ")
+	if q.Bitrate > 0 {
+		if summary != "" {
+			summary += " · "
+		}
+		summary += fmt.Sprintf("%d kbps", q.Bitrate)
+	}
+
+	return formatString(summary)
+}
+
+// formatKHz renders a sample rate in kHz without trailing zeros (44.1, 96)
+func formatKHz(hz int) string {
+	return strconv.FormatFloat(float64(hz)/1000, 'f', -1, 64)
+}
+
 // formatNumber converts large numbers to readable format (1.2M, 15.3K)
 func formatNumber(n int) string {
-	if n >= 1000000 {
-		return fmt.Sprintf("%.1fM", float64(n)/1000000)
-	} else if n >= 1000 {
-		return fmt.Sprintf("%.1fK", float64(n)/1000)
-	}
-	return fmt.Sprintf("%d", n)
+	return compactCount(n, englishFormat)
 }
 
 // formatBool converts boolean to Yes/No string
@@ -564,6 +945,85 @@ func formatBool(b bool) string {
 	return "No"
 }
 
+// explicitSummaryField shows how many tracks are explicit ("7/12 explicit"),
+// flagging editions with no explicit tracks as clean
+func (o Options) explicitSummaryField(tracks []spotify.Track) field {
+	explicit := 0
+	for _, track := range tracks {
+		if track.Explicit {
+			explicit++
+		}
+	}
+
+	if len(tracks) == 0 {
+		return o.infoField("explicit", "Explicit", "N/A", ColorWhite)
+	}
+	if explicit == 0 {
+		return o.infoField("explicit", "Explicit", "No (clean edition)", o.goodColor())
+	}
+	return o.infoField("explicit", "Explicit", fmt.Sprintf("%d/%d explicit", explicit, len(tracks)), o.badColor())
+}
+
+// licenseField shows the license name linked to its deed
+func (o Options) licenseField(license spotify.License) field {
+	name := formatString(license.Name)
+	if license.URL != "" {
+		name = createClickableLink(license.URL, name)
+	}
+	return o.infoField("license", "License", name, ColorGreen)
+}
+
+// barcodeLabel names an album barcode: Spotify's upc field also holds
+// 13-digit EANs
+func barcodeLabel(code string) string {
+	if len(code) == 13 {
+		return "EAN"
+	}
+	return "UPC"
+}
+
+// truncate shortens s to at most max terminal cells, marking the cut with "..."
+func truncate(s string, max int) string {
+	return ansi.Truncate(s, max, "...")
+}
+
+// wrapText breaks s into lines of at most width cells at word boundaries,
+// splitting words that don't fit on a line of their own
+func wrapText(s string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+
+	for _, word := range strings.Fields(s) {
+		wordWidth := runewidth.StringWidth(word)
+
+		if lineWidth > 0 && lineWidth+1+wordWidth > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+
+		for wordWidth > width {
+			head := runewidth.Truncate(word, width, "")
+			lines = append(lines, head)
+			word = strings.TrimPrefix(word, head)
+			wordWidth = runewidth.StringWidth(word)
+		}
+
+		if lineWidth > 0 {
+			line.WriteString(" ")
+			lineWidth++
+		}
+		line.WriteString(word)
+		lineWidth += wordWidth
+	}
+
+	if line.Len() > 0 || len(lines) == 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
 // formatString handles empty strings with N/A fallback
 func formatString(s string) string {
 	if s == "" {
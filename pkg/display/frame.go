@@ -0,0 +1,79 @@
+package display
+
+import (
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+)
+
+// Frame styles for the card and the art
+const (
+	FrameNone    = "none"
+	FrameSingle  = "single"
+	FrameDouble  = "double"
+	FrameRounded = "rounded"
+)
+
+// FrameNames lists the accepted frame styles
+var FrameNames = []string{FrameNone, FrameSingle, FrameDouble, FrameRounded}
+
+// frameChars holds the box-drawing runes of a frame style
+type frameChars struct {
+	topLeft, topRight, bottomLeft, bottomRight, horizontal, vertical string
+}
+
+// frameStyles maps style names to their box-drawing characters
+var frameStyles = map[string]frameChars{
+	FrameSingle:  {"┌", "┐", "└", "┘", "─", "│"},
+	FrameDouble:  {"╔", "╗", "╚", "╝", "═", "║"},
+	FrameRounded: {"╭", "╮", "╰", "╯", "─", "│"},
+}
+
+// frameLines draws a box around lines, padding each to the widest one with
+// the given inner padding
+func frameLines(lines []string, style string, padding int) []string {
+	chars, ok := frameStyles[style]
+	if !ok || len(lines) == 0 {
+		return lines
+	}
+
+	width := 0
+	for _, line := range lines {
+		if w := ansi.Width(line); w > width {
+			width = w
+		}
+	}
+
+	pad := strings.Repeat(" ", padding)
+	inner := width + 2*padding
+
+	framed := make([]string, 0, len(lines)+2)
+	framed = append(framed, chars.topLeft+strings.Repeat(chars.horizontal, inner)+chars.topRight)
+	for _, line := range lines {
+		fill := strings.Repeat(" ", width-ansi.Width(line))
+		framed = append(framed, chars.vertical+pad+line+fill+pad+chars.vertical)
+	}
+	framed = append(framed, chars.bottomLeft+strings.Repeat(chars.horizontal, inner)+chars.bottomRight)
+
+	return framed
+}
+
+// frameArt wraps the art column in the theme's art frame, keeping the
+// one-space left margin outside the frame
+func (o Options) frameArt(imageLines []string) []string {
+	style := o.theme().ArtFrame
+	if style == "" || style == FrameNone || len(imageLines) == 0 {
+		return imageLines
+	}
+
+	trimmed := make([]string, len(imageLines))
+	for i, line := range imageLines {
+		trimmed[i] = strings.TrimPrefix(line, " ")
+	}
+
+	framed := frameLines(trimmed, style, 0)
+	for i := range framed {
+		framed[i] = " " + framed[i]
+	}
+	return framed
+}
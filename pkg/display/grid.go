@@ -0,0 +1,162 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// Grid thumbnail layout
+const (
+	gridThumbSize = 10 // Art size of each thumbnail
+	gridGap       = 2  // Blank columns between cells
+	gridColumns   = 80 // Terminal width assumed when it can't be detected
+)
+
+// GridItem is a single search result shown in the grid view
+type GridItem struct {
+	Title    string
+	Subtitle string
+	URL      string
+	Images   []spotify.Image
+}
+
+// TrackGridItems converts track results into grid items
+func TrackGridItems(tracks []spotify.Track) []GridItem {
+	items := make([]GridItem, len(tracks))
+	for i, t := range tracks {
+		items[i] = GridItem{Title: t.Name, Subtitle: artistNames(t.Artists), URL: t.ExternalURL.URL(), Images: t.Album.Images}
+	}
+	return items
+}
+
+// AlbumGridItems converts album results into grid items
+func AlbumGridItems(albums []spotify.Album) []GridItem {
+	items := make([]GridItem, len(albums))
+	for i, a := range albums {
+		subtitle := artistNames(a.Artists)
+		if len(a.ReleaseDate) >= 4 {
+			subtitle += " · " + a.ReleaseDate[:4]
+		}
+		items[i] = GridItem{Title: a.Name, Subtitle: subtitle, URL: a.ExternalURL.URL(), Images: a.Images}
+	}
+	return items
+}
+
+// ArtistGridItems converts artist results into grid items
+func ArtistGridItems(artists []spotify.Artist) []GridItem {
+	items := make([]GridItem, len(artists))
+	for i, a := range artists {
+		subtitle := "Artist"
+		if a.Followers.Total > 0 {
+			subtitle = formatNumber(a.Followers.Total) + " followers"
+		}
+		items[i] = GridItem{Title: a.Name, Subtitle: subtitle, URL: a.ExternalURL.URL(), Images: a.Images}
+	}
+	return items
+}
+
+// artistNames joins artist names with commas
+func artistNames(artists []spotify.Artist) string {
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// DisplayGrid renders results as numbered thumbnails with titles, as many
+// per row as fit the terminal
+func DisplayGrid(items []GridItem, opts Options) {
+	opts.ImageSize = gridThumbSize
+	cellWidth := gridThumbSize*2 + 1
+
+	// Thumbnails download in parallel; each lands in its own slot
+	thumbs := make([][]string, len(items))
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func(i int, item GridItem) {
+			defer wg.Done()
+			thumbs[i] = opts.gridThumb(item.Images)
+		}(i, item)
+	}
+	wg.Wait()
+
+	opts.Spinner.Stop()
+
+	cols := gridColumns
+	if size, err := platform.GetWindowSize(); err == nil && size.Cols > 0 {
+		cols = size.Cols
+	}
+	perRow := (cols + gridGap) / (cellWidth + gridGap)
+	if perRow < 1 {
+		perRow = 1
+	}
+
+	for start := 0; start < len(items); start += perRow {
+		end := start + perRow
+		if end > len(items) {
+			end = len(items)
+		}
+
+		var cells [][]string
+		for i := start; i < end; i++ {
+			cells = append(cells, gridCell(i+1, items[i], thumbs[i], cellWidth))
+		}
+
+		for row := 0; row < len(cells[0]); row++ {
+			var line strings.Builder
+			for i, cell := range cells {
+				if i > 0 {
+					line.WriteString(strings.Repeat(" ", gridGap))
+				}
+				line.WriteString(cell[row])
+			}
+			opts.println(strings.TrimRight(line.String(), " "))
+		}
+		opts.println("")
+	}
+}
+
+// gridThumb renders the art for a grid cell, or an empty box without art
+func (o Options) gridThumb(images []spotify.Image) []string {
+	renderer := o.newRenderer(gridThumbSize)
+	if o.NoImage || len(images) == 0 {
+		return renderer.placeholderLines("", "", ColorWhite)
+	}
+
+	// The smallest image that still covers the thumbnail downloads fastest
+	url := images[0].URL
+	for _, img := range images {
+		if img.Width >= gridThumbSize*8 || img.Width == 0 {
+			url = img.URL
+		}
+	}
+	return renderer.RenderImageLines(url)
+}
+
+// gridCell stacks a thumbnail over its numbered title and subtitle, padding
+// every line to the cell width
+func gridCell(n int, item GridItem, thumb []string, width int) []string {
+	// Thumbnails all come from one renderer size, so cells line up
+	lines := make([]string, 0, len(thumb)+2)
+	lines = append(lines, thumb...)
+
+	title := truncate(fmt.Sprintf("%d. %s", n, item.Title), width-1)
+	if item.URL != "" {
+		title = createClickableLink(item.URL, title)
+	}
+	lines = append(lines,
+		fmt.Sprintf(" %s%s%s", ColorBold, title, ColorReset),
+		fmt.Sprintf(" %s%s%s", ColorYellow, truncate(item.Subtitle, width-1), ColorReset))
+
+	for i, line := range lines {
+		lines[i] = ansi.Pad(line, width)
+	}
+	return lines
+}
@@ -0,0 +1,61 @@
+package display
+
+// fieldIcons maps field keys to Nerd Font glyphs shown in --icons mode
+var fieldIcons = map[string]string{
+	"name":         "",          // nf-fa-music
+	"artist":       "",          // nf-fa-user
+	"album":        "\U000f0025", // nf-md-album
+	"type":         "",          // nf-fa-tag
+	"duration":     "",          // nf-fa-clock_o
+	"track":        "",          // nf-fa-hashtag
+	"tracks":       "",          // nf-fa-list
+	"explicit":     "",          // nf-fa-exclamation_triangle
+	"released":     "",          // nf-fa-calendar
+	"popularity":   "",          // nf-fa-fire
+	"followers":    "",          // nf-fa-users
+	"genres":       "",          // nf-fa-tags
+	"label":        "",          // nf-fa-building
+	"albums":       "\U000f0025", // nf-md-album
+	"singles":      "",          // nf-fa-circle_o
+	"top_tracks":   "",          // nf-fa-star
+	"tracklist":    "",          // nf-fa-list_ol
+	"show":         "",          // nf-fa-microphone
+	"publisher":    "",          // nf-fa-bullhorn
+	"progress":     "",          // nf-fa-play
+	"quality":      "",          // nf-fa-headphones
+	"license":      "",          // nf-fa-creative_commons
+	"venue":        "",          // nf-fa-map_marker
+	"taper":        "",          // nf-fa-microphone
+	"recording":    "",          // nf-fa-cogs
+	"markets":      "",          // nf-fa-globe
+	"rights":       "",          // nf-fa-copyright
+	"isrc":         "",          // nf-fa-barcode
+	"upc":          "",          // nf-fa-barcode
+	"compilations": "",          // nf-fa-files_o
+	"appears_on":   "",          // nf-fa-users
+}
+
+// withIcon prefixes the first line of a field with its glyph and indents
+// the following lines to match; blank spacer lines are left alone
+func withIcon(f field) field {
+	icon, ok := fieldIcons[f.key]
+	if !ok {
+		icon = " "
+	}
+
+	lines := make([]string, len(f.lines))
+	first := true
+	for i, line := range f.lines {
+		switch {
+		case line == "":
+			lines[i] = line
+		case first:
+			lines[i] = icon + " " + line
+			first = false
+		default:
+			lines[i] = "  " + line
+		}
+	}
+
+	return field{key: f.key, lines: lines}
+}
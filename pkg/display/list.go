@@ -0,0 +1,28 @@
+package display
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DisplayList prints results as a numbered list, one per line, for picking
+// between near matches without loading art
+func DisplayList(items []GridItem, opts Options) {
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	numWidth := len(strconv.Itoa(len(items)))
+	for i, item := range items {
+		title := truncate(item.Title, opts.maxWidth())
+		if item.URL != "" {
+			title = createClickableLink(item.URL, title)
+		}
+		line := fmt.Sprintf(" %s%*d.%s %s%s%s", ColorBold, numWidth, i+1, ColorReset, ColorGreen, title, ColorReset)
+		if item.Subtitle != "" {
+			line += fmt.Sprintf(" %s· %s%s", ColorBlue, truncate(item.Subtitle, opts.maxWidth()), ColorReset)
+		}
+		opts.println(line)
+	}
+	opts.println("")
+}
@@ -0,0 +1,119 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// localeFormat holds how one locale writes dates and compact counts
+type localeFormat struct {
+	date     string // Layout for full dates; "" keeps the ordinal "1st Jan 2020"
+	month    string // Layout for year-month dates; "" uses "Jan 2006"
+	decimal  string
+	thousand string // Suffixes for compact counts, e.g. 1,2 Mio.
+	million  string
+}
+
+// englishFormat is used for English and locales without an entry
+var englishFormat = localeFormat{decimal: ".", thousand: "K", million: "M"}
+
+// localeFormats maps language tags, with or without a region, to their
+// formats; a region entry wins over its language's
+var localeFormats = map[string]localeFormat{
+	"en-us": {date: "Jan 2, 2006", decimal: ".", thousand: "K", million: "M"},
+	"de":    {date: "02.01.2006", month: "01.2006", decimal: ",", thousand: " Tsd.", million: " Mio."},
+	"fr":    {date: "02/01/2006", month: "01/2006", decimal: ",", thousand: " k", million: " M"},
+	"es":    {date: "02/01/2006", month: "01/2006", decimal: ",", thousand: " mil", million: " M"},
+	"pt":    {date: "02/01/2006", month: "01/2006", decimal: ",", thousand: " mil", million: " mi"},
+	"it":    {date: "02/01/2006", month: "01/2006", decimal: ",", thousand: "K", million: " Mln"},
+	"nl":    {date: "02-01-2006", month: "01-2006", decimal: ",", thousand: "K", million: " mln."},
+	"sv":    {date: "2006-01-02", month: "2006-01", decimal: ",", thousand: " tn", million: " mn"},
+	"pl":    {date: "02.01.2006", month: "01.2006", decimal: ",", thousand: " tys.", million: " mln"},
+	"ru":    {date: "02.01.2006", month: "01.2006", decimal: ",", thousand: " тыс.", million: " млн"},
+	"ja":    {date: "2006/01/02", month: "2006/01", decimal: ".", thousand: "K", million: "M"},
+	"zh":    {date: "2006/1/2", month: "2006/1", decimal: ".", thousand: "K", million: "M"},
+	"ko":    {date: "2006. 1. 2.", month: "2006. 1.", decimal: ".", thousand: "K", million: "M"},
+}
+
+// lookupLocale finds the format for a tag like de-AT or a POSIX locale like
+// pt_BR.UTF-8
+func lookupLocale(tag string) localeFormat {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+
+	if f, ok := localeFormats[tag]; ok {
+		return f
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	if f, ok := localeFormats[lang]; ok {
+		return f
+	}
+	return englishFormat
+}
+
+// formatDate writes a release date the way opts.Locale does, at the
+// precision Spotify knows it: "1994", "Mar 2001" or a full date, which
+// falls back to formatOrdinalDate
+func (o Options) formatDate(dateStr, precision string) string {
+	if dateStr == "" {
+		return "N/A"
+	}
+	f := lookupLocale(o.Locale)
+
+	switch datePrecision(dateStr, precision) {
+	case "year":
+		if t, err := time.Parse("2006", dateStr); err == nil {
+			return t.Format("2006")
+		}
+	case "month":
+		if t, err := time.Parse("2006-01", dateStr); err == nil {
+			if f.month == "" {
+				return t.Format("Jan 2006")
+			}
+			return t.Format(f.month)
+		}
+	default:
+		if f.date == "" {
+			return formatOrdinalDate(dateStr)
+		}
+		if t, err := time.Parse("2006-01-02", dateStr); err == nil {
+			return t.Format(f.date)
+		}
+	}
+	return formatOrdinalDate(dateStr)
+}
+
+// datePrecision returns a release date's precision (year, month or day),
+// going by its length when the source doesn't say, as with plugins
+func datePrecision(dateStr, precision string) string {
+	if precision != "" {
+		return precision
+	}
+	switch len(dateStr) {
+	case len("2006"):
+		return "year"
+	case len("2006-01"):
+		return "month"
+	}
+	return "day"
+}
+
+// formatCount writes a count compactly in opts.Locale (1.2M, 1,2 Mio.)
+func (o Options) formatCount(n int) string {
+	return compactCount(n, lookupLocale(o.Locale))
+}
+
+// compactCount abbreviates thousands and millions with f's suffixes
+func compactCount(n int, f localeFormat) string {
+	switch {
+	case n >= 1000000:
+		return strings.Replace(fmt.Sprintf("%.1f", float64(n)/1000000), ".", f.decimal, 1) + f.million
+	case n >= 1000:
+		return strings.Replace(fmt.Sprintf("%.1f", float64(n)/1000), ".", f.decimal, 1) + f.thousand
+	}
+	return fmt.Sprintf("%d", n)
+}
@@ -0,0 +1,35 @@
+package display
+
+import (
+	"os"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+)
+
+// LoadLogo reads an ASCII/ANSI art file and pads every line to the same
+// visible width so the info column stays aligned beside it
+func LoadLogo(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\t", "    ")
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	width := 0
+	for _, line := range lines {
+		if w := ansi.Width(line); w > width {
+			width = w
+		}
+	}
+
+	for i, line := range lines {
+		// Reset so colors left open by the art don't bleed into the info pane
+		lines[i] = " " + line + ColorReset + strings.Repeat(" ", width-ansi.Width(line))
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,90 @@
+package display
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/internal/platform"
+	"github.com/ashish0kumar/mufetch/pkg/ansi"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// lyricsGap separates the card from the lyrics column
+const lyricsGap = "     "
+
+// withLyrics places the lyrics panel to the right of the card when the
+// terminal is wide enough, and below it otherwise
+func (o Options) withLyrics(lines []string) []string {
+	if len(o.Lyrics) == 0 && o.LyricsErr == nil {
+		return lines
+	}
+
+	panel := []string{fmt.Sprintf("%sLyrics%s", ColorBold, ColorReset)}
+	for _, line := range o.Lyrics {
+		panel = append(panel, ColorWhite+truncate(line, o.maxWidth())+ColorReset)
+	}
+	if len(o.Lyrics) == 0 {
+		slog.Debug("lyrics unavailable", "err", o.LyricsErr)
+		panel = append(panel, ColorDim+"unavailable"+ColorReset)
+	}
+
+	// Trailing blank rows are re-added after the panel is merged in
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	cardWidth, panelWidth := 0, 0
+	for _, line := range lines {
+		if w := ansi.Width(line); w > cardWidth {
+			cardWidth = w
+		}
+	}
+	for _, line := range panel {
+		if w := ansi.Width(line); w > panelWidth {
+			panelWidth = w
+		}
+	}
+
+	size, err := platform.GetWindowSize()
+	if err != nil || size.Cols < cardWidth+len(lyricsGap)+panelWidth {
+		merged := append(lines, "")
+		for _, line := range panel {
+			merged = append(merged, " "+line)
+		}
+		return append(merged, "")
+	}
+
+	var merged []string
+	for i := 0; i < len(lines) || i < len(panel); i++ {
+		left := ""
+		if i < len(lines) {
+			left = lines[i]
+		}
+		if i >= len(panel) {
+			merged = append(merged, left)
+			continue
+		}
+		fill := strings.Repeat(" ", cardWidth-ansi.Width(left))
+		merged = append(merged, left+fill+lyricsGap+panel[i])
+	}
+	return append(merged, "")
+}
+
+// DisplayLyrics prints a track's full lyrics under a header with its name,
+// artists and album
+func DisplayLyrics(track spotify.Track, lines []string, opts Options) {
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	name := createClickableLink(track.ExternalURL.URL(), track.Name)
+	opts.println(fmt.Sprintf(" %s%s%s%s", ColorBold, ColorGreen, name, ColorReset))
+	opts.println(fmt.Sprintf(" %s%s%s %s· %s%s", ColorYellow, artistNames(track.Artists), ColorReset, ColorBlue, track.Album.Name, ColorReset))
+	opts.println("")
+
+	for _, line := range lines {
+		opts.println(" " + strings.TrimSpace(line))
+	}
+	opts.println("")
+}
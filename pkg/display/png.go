@@ -0,0 +1,309 @@
+package display
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// PNG card layout; the 7x13 bitmap font is scaled up so text stays crisp
+const (
+	pngCellWidth  = 7
+	pngCellHeight = 13
+	pngScale      = 2
+	pngMargin     = 2 // Cells of padding around the card
+)
+
+// Colors used where the card relies on the terminal's defaults
+var (
+	pngBackground = color.RGBA{0x1e, 0x1e, 0x2e, 0xff}
+	pngForeground = color.RGBA{0xcd, 0xd6, 0xf4, 0xff}
+)
+
+// ansi16 holds the xterm values of the basic 16 colors
+var ansi16 = func() []color.RGBA {
+	colors := make([]color.RGBA, len(palette16.colors))
+	for i, c := range palette16.colors {
+		colors[i] = color.RGBA{uint8(c.r), uint8(c.g), uint8(c.b), 0xff}
+	}
+	return colors
+}()
+
+// escapePattern matches CSI sequences (group 1: params, group 2: command)
+// and OSC 8 hyperlinks, which have no visible effect
+var escapePattern = regexp.MustCompile(`\x1b\[([0-9;?]*)([A-Za-z])|\x1b\]8;;[^\x1b]*\x1b\\`)
+
+// pngCell is a single character cell with its colors
+type pngCell struct {
+	r      rune
+	fg, bg color.RGBA
+	bold   bool
+	wide   bool // First half of a double-width character
+}
+
+// sgrState tracks the colors set by SGR escape codes
+type sgrState struct {
+	fg, bg color.RGBA
+	bold   bool
+}
+
+// SavePNG rasterizes rendered card lines (with ANSI colors) into a PNG
+func SavePNG(path string, lines []string) error {
+	grid := parseCells(lines)
+
+	cols := 0
+	for _, row := range grid {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+
+	width := (cols + pngMargin*2) * pngCellWidth
+	height := (len(grid) + pngMargin*2) * pngCellHeight
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{pngBackground}, image.Point{}, draw.Src)
+
+	for y, row := range grid {
+		for x, cell := range row {
+			px := (x + pngMargin) * pngCellWidth
+			py := (y + pngMargin) * pngCellHeight
+			drawCell(img, px, py, cell)
+		}
+	}
+
+	scaled := imaging.Resize(img, width*pngScale, height*pngScale, imaging.NearestNeighbor)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, scaled)
+}
+
+// trimBlankLines drops trailing lines that show nothing
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(escapePattern.ReplaceAllString(lines[len(lines)-1], "")) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// parseCells splits lines into colored cells, applying SGR codes
+func parseCells(lines []string) [][]pngCell {
+	grid := make([][]pngCell, 0, len(lines))
+	state := sgrState{fg: pngForeground, bg: pngBackground}
+
+	for _, line := range lines {
+		var row []pngCell
+		rest := line
+		for rest != "" {
+			loc := escapePattern.FindStringSubmatchIndex(rest)
+			text := rest
+			if loc != nil {
+				text = rest[:loc[0]]
+			}
+
+			for _, r := range text {
+				cell := pngCell{r: r, fg: state.fg, bg: state.bg, bold: state.bold}
+				if runewidth.RuneWidth(r) == 2 {
+					cell.wide = true
+					row = append(row, cell, pngCell{r: ' ', fg: state.fg, bg: state.bg})
+					continue
+				}
+				row = append(row, cell)
+			}
+
+			if loc == nil {
+				break
+			}
+			if loc[4] >= 0 && rest[loc[4]:loc[5]] == "m" {
+				state.apply(rest[loc[2]:loc[3]])
+			}
+			rest = rest[loc[1]:]
+		}
+		grid = append(grid, row)
+	}
+
+	return grid
+}
+
+// apply updates the state from the parameters of an SGR sequence
+func (s *sgrState) apply(params string) {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		n, _ := strconv.Atoi(codes[i])
+		switch {
+		case n == 0:
+			*s = sgrState{fg: pngForeground, bg: pngBackground}
+		case n == 1:
+			s.bold = true
+		case n == 22:
+			s.bold = false
+		case n >= 30 && n <= 37:
+			s.fg = ansi16[n-30]
+		case n >= 90 && n <= 97:
+			s.fg = ansi16[n-90+8]
+		case n >= 40 && n <= 47:
+			s.bg = ansi16[n-40]
+		case n >= 100 && n <= 107:
+			s.bg = ansi16[n-100+8]
+		case n == 39:
+			s.fg = pngForeground
+		case n == 49:
+			s.bg = pngBackground
+		case (n == 38 || n == 48) && i+1 < len(codes):
+			var c color.RGBA
+			var ok bool
+			c, i, ok = extendedColor(codes, i+1)
+			if ok && n == 38 {
+				s.fg = c
+			} else if ok {
+				s.bg = c
+			}
+		}
+	}
+}
+
+// extendedColor parses "5;n" or "2;r;g;b" starting at codes[i], returning
+// the color and the index of the last code consumed
+func extendedColor(codes []string, i int) (color.RGBA, int, bool) {
+	mode, _ := strconv.Atoi(codes[i])
+	switch {
+	case mode == 5 && i+1 < len(codes):
+		n, _ := strconv.Atoi(codes[i+1])
+		if n < 16 {
+			return ansi16[n], i + 1, true
+		}
+		c := xterm256(n)
+		return color.RGBA{uint8(c.r), uint8(c.g), uint8(c.b), 0xff}, i + 1, true
+	case mode == 2 && i+3 < len(codes):
+		r, _ := strconv.Atoi(codes[i+1])
+		g, _ := strconv.Atoi(codes[i+2])
+		b, _ := strconv.Atoi(codes[i+3])
+		return color.RGBA{uint8(r), uint8(g), uint8(b), 0xff}, i + 3, true
+	}
+	return color.RGBA{}, i, false
+}
+
+// drawCell paints one cell: block and box-drawing characters as shapes,
+// everything else with the bitmap font
+func drawCell(img *image.RGBA, x, y int, cell pngCell) {
+	w, h := pngCellWidth, pngCellHeight
+	if cell.wide {
+		w *= 2
+	}
+	fill(img, x, y, w, h, cell.bg)
+
+	switch r := cell.r; {
+	case r == ' ':
+	case r == '▀':
+		fill(img, x, y, w, h/2, cell.fg)
+	case r == '▄':
+		fill(img, x, y+h/2, w, h-h/2, cell.fg)
+	case r == '█':
+		fill(img, x, y, w, h, cell.fg)
+	case r >= 0x2800 && r <= 0x28ff:
+		drawBraille(img, x, y, r-0x2800, cell.fg)
+	case drawBox(img, x, y, r, cell.fg):
+	case hasGlyph(r):
+		drawGlyph(img, x, y, r, cell)
+	default:
+		// Symbols the font lacks (e.g. chafa's sextants) become shaded blocks
+		fill(img, x, y, w, h, blend(cell.fg, cell.bg))
+	}
+}
+
+// fill paints a rectangle
+func fill(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// blend mixes two colors evenly
+func blend(a, b color.RGBA) color.RGBA {
+	return color.RGBA{uint8((int(a.R) + int(b.R)) / 2), uint8((int(a.G) + int(b.G)) / 2), uint8((int(a.B) + int(b.B)) / 2), 0xff}
+}
+
+// drawBraille paints the lit dots of a braille pattern
+func drawBraille(img *image.RGBA, x, y int, bits rune, c color.RGBA) {
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 2; col++ {
+			if bits&brailleDots[row][col] == 0 {
+				continue
+			}
+			fill(img, x+1+col*3, y+1+row*3, 2, 2, c)
+		}
+	}
+}
+
+// boxSegments lists which arms (up, right, down, left) each box-drawing
+// character has
+var boxSegments = map[rune][4]bool{
+	'─': {false, true, false, true}, '━': {false, true, false, true}, '═': {false, true, false, true},
+	'│': {true, false, true, false}, '┃': {true, false, true, false}, '║': {true, false, true, false},
+	'┌': {false, true, true, false}, '╭': {false, true, true, false}, '╔': {false, true, true, false},
+	'┐': {false, false, true, true}, '╮': {false, false, true, true}, '╗': {false, false, true, true},
+	'└': {true, true, false, false}, '╰': {true, true, false, false}, '╚': {true, true, false, false},
+	'┘': {true, false, false, true}, '╯': {true, false, false, true}, '╝': {true, false, false, true},
+}
+
+// drawBox paints a box-drawing character, reporting whether r is one
+func drawBox(img *image.RGBA, x, y int, r rune, c color.RGBA) bool {
+	arms, ok := boxSegments[r]
+	if !ok {
+		return false
+	}
+
+	cx, cy := x+pngCellWidth/2, y+pngCellHeight/2
+	if arms[0] {
+		fill(img, cx, y, 1, cy-y+1, c)
+	}
+	if arms[1] {
+		fill(img, cx, cy, x+pngCellWidth-cx, 1, c)
+	}
+	if arms[2] {
+		fill(img, cx, cy, 1, y+pngCellHeight-cy, c)
+	}
+	if arms[3] {
+		fill(img, x, cy, cx-x+1, 1, c)
+	}
+	return true
+}
+
+// hasGlyph reports whether the bitmap font covers r
+func hasGlyph(r rune) bool {
+	for _, rng := range basicfont.Face7x13.Ranges {
+		if r >= rng.Low && r < rng.High {
+			return true
+		}
+	}
+	return false
+}
+
+// drawGlyph draws r with the bitmap font, faking bold by overstriking
+func drawGlyph(img *image.RGBA, x, y int, r rune, cell pngCell) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{cell.fg},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y+basicfont.Face7x13.Ascent),
+	}
+	d.DrawString(string(r))
+
+	if cell.bold {
+		d.Dot = fixed.P(x+1, y+basicfont.Face7x13.Ascent)
+		d.DrawString(string(r))
+	}
+}
@@ -0,0 +1,61 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/mattn/go-runewidth"
+)
+
+// DisplayReleases lists releases from several artists newest first,
+// grouped by year, with each release's date, title, type and artists,
+// under a title such as a record label's name
+func DisplayReleases(title string, albums []spotify.Album, opts Options) {
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	sorted := make([]spotify.Album, len(albums))
+	copy(sorted, albums)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ReleaseDate > sorted[j].ReleaseDate
+	})
+
+	opts.println(fmt.Sprintf(" %s%s%s %s· %s%s", ColorBold, title, ColorReset, ColorCyan, pluralize(len(sorted), "release"), ColorReset))
+
+	width := opts.maxWidth()
+	var currentYear string
+	for _, album := range sorted {
+		year := album.ReleaseDate
+		if len(year) > 4 {
+			year = year[:4]
+		}
+		if year != currentYear {
+			currentYear = year
+			opts.println("")
+			opts.println(fmt.Sprintf(" %s%s%s", ColorBold, year, ColorReset))
+		}
+
+		name := runewidth.Truncate(album.Name, width, "...")
+		padding := strings.Repeat(" ", width-runewidth.StringWidth(name))
+		name = createClickableLink(album.ExternalURL.URL(), name)
+
+		kind := album.AlbumType
+		if kind == "" {
+			kind = "album"
+		}
+		color, ok := albumTypeColors[kind]
+		if !ok {
+			color = ColorWhite
+		}
+
+		opts.println(fmt.Sprintf("   %s%-6s%s  %s%s  %s%-11s%s  %s%s%s",
+			ColorCyan, releaseMonthDay(album), ColorReset,
+			name, padding,
+			color, kind, ColorReset,
+			ColorYellow, truncate(artistNames(album.Artists), width), ColorReset))
+	}
+	opts.println("")
+}
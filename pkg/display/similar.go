@@ -0,0 +1,52 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/mattn/go-runewidth"
+)
+
+// similarGenres caps the genres shown per similar artist
+const similarGenres = 3
+
+// DisplaySimilar lists artists similar to an artist, one per line with
+// their popularity and top genres. Artists without a popularity (names
+// that couldn't be matched on the source) show a dash.
+func DisplaySimilar(artist spotify.Artist, similar []spotify.Artist, opts Options) {
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	name := createClickableLink(artist.ExternalURL.URL(), artist.Name)
+	opts.println(fmt.Sprintf(" %sSimilar to %s%s %s· %s%s", ColorBold, name, ColorReset, ColorCyan, pluralize(len(similar), "artist"), ColorReset))
+	opts.println("")
+
+	nameWidth := 0
+	for _, a := range similar {
+		nameWidth = max(nameWidth, runewidth.StringWidth(truncate(a.Name, opts.maxWidth())))
+	}
+
+	for _, a := range similar {
+		title := truncate(a.Name, opts.maxWidth())
+		padding := strings.Repeat(" ", nameWidth-runewidth.StringWidth(title))
+		title = createClickableLink(a.ExternalURL.URL(), title)
+
+		popularity := "   -"
+		if a.ID != "" {
+			popularity = fmt.Sprintf("%3d%%", a.Popularity)
+		}
+
+		genres := a.Genres
+		if len(genres) > similarGenres {
+			genres = genres[:similarGenres]
+		}
+
+		opts.println(fmt.Sprintf(" %s%s%s%s  %s%s%s  %s%s%s",
+			ColorGreen, title, ColorReset, padding,
+			ColorPurple, popularity, ColorReset,
+			ColorYellow, strings.Join(genres, ", "), ColorReset))
+	}
+	opts.println("")
+}
@@ -0,0 +1,104 @@
+package display
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// spinnerFrames are drawn in order while work is in progress
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner animates a status message on stderr while data is being fetched
+type Spinner struct {
+	out     io.Writer
+	mu      sync.Mutex
+	message string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSpinner creates a spinner showing message; it stays silent when stderr
+// is not a terminal so redirected output isn't polluted
+func NewSpinner(message string) *Spinner {
+	s := &Spinner{message: message}
+
+	// Debug logs share stderr and would be garbled by the animation
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		return s
+	}
+	if info, err := os.Stderr.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		s.out = os.Stderr
+	}
+	return s
+}
+
+// Start begins the animation in the background
+func (s *Spinner) Start() {
+	if s == nil || s.out == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return // Already running
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// SetMessage changes the text shown next to the spinner
+func (s *Spinner) SetMessage(message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.message = message
+	s.mu.Unlock()
+}
+
+// Stop ends the animation and clears its line; safe to call repeatedly
+func (s *Spinner) Stop() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// run redraws the current frame until stopped
+func (s *Spinner) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(80 * time.Millisecond)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		fmt.Fprintf(s.out, "\r\033[K %s%s%s %s", ColorCyan, spinnerFrames[i%len(spinnerFrames)], ColorReset, s.message)
+		s.mu.Unlock()
+
+		select {
+		case <-stop:
+			fmt.Fprint(s.out, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// statsCollaborators caps the collaborators listed
+const statsCollaborators = 5
+
+// DisplayArtistStats summarizes an artist's discography: release counts by
+// type, track totals and lengths, popularity, first and latest releases and
+// the artists they work with most. albums should include tracklists; on
+// releases the artist only appears on, just the tracks crediting them count.
+func DisplayArtistStats(artist spotify.Artist, albums []spotify.Album, opts Options) {
+	if opts.Spinner != nil {
+		opts.Spinner.Stop()
+	}
+
+	name := createClickableLink(artist.ExternalURL.URL(), artist.Name)
+	opts.println(fmt.Sprintf(" %s%s%s %s· discography stats%s", ColorBold, name, ColorReset, ColorCyan, ColorReset))
+	opts.println("")
+
+	if len(albums) == 0 {
+		opts.println(" No releases")
+		opts.println("")
+		return
+	}
+
+	sorted := make([]spotify.Album, len(albums))
+	copy(sorted, albums)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ReleaseDate < sorted[j].ReleaseDate
+	})
+
+	types := make(map[string]int)
+	tracks, timed, popularitySum := 0, 0, 0
+	var runtime time.Duration
+	collaborators := make(map[string]int)
+	mostPopular := sorted[0]
+	for _, album := range sorted {
+		kind := album.AlbumGroup
+		if kind == "" {
+			kind = album.AlbumType
+		}
+		if kind == "" {
+			kind = "album"
+		}
+		types[kind]++
+		appearsOn := kind == "appears_on"
+		if !appearsOn {
+			tracks += album.TotalTracks
+		}
+		popularitySum += album.Popularity
+		if album.Popularity > mostPopular.Popularity {
+			mostPopular = album
+		}
+
+		for _, t := range album.Tracks.Items {
+			if appearsOn {
+				if !creditsArtist(t, artist) {
+					continue
+				}
+				tracks++
+			}
+			runtime += time.Duration(t.Duration) * time.Millisecond
+			timed++
+			for _, a := range t.Artists {
+				if a.ID != artist.ID && a.Name != artist.Name {
+					collaborators[a.Name]++
+				}
+			}
+		}
+	}
+
+	var typeCounts []string
+	for _, kind := range []string{"album", "single", "compilation", "appears_on"} {
+		if n := types[kind]; n > 0 {
+			typeCounts = append(typeCounts, pluralize(n, strings.ReplaceAll(kind, "_", " ")))
+		}
+	}
+
+	rows := [][2]string{
+		{"Releases", fmt.Sprintf("%d (%s)", len(sorted), strings.Join(typeCounts, ", "))},
+		{"Tracks", fmt.Sprintf("%d", tracks)},
+	}
+	if timed > 0 {
+		rows = append(rows,
+			[2]string{"Total Length", formatLongDuration(runtime)},
+			[2]string{"Avg Track", formatDuration(runtime / time.Duration(timed))})
+	}
+	rows = append(rows,
+		[2]string{"Avg Popularity", fmt.Sprintf("%d%%", popularitySum/len(sorted))},
+		[2]string{"Most Popular", fmt.Sprintf("%s (%d%%)", mostPopular.Name, mostPopular.Popularity)},
+		[2]string{"First Release", fmt.Sprintf("%s (%s)", sorted[0].Name, sorted[0].ReleaseDate)},
+		[2]string{"Latest Release", fmt.Sprintf("%s (%s)", sorted[len(sorted)-1].Name, sorted[len(sorted)-1].ReleaseDate)},
+	)
+
+	for _, row := range rows {
+		opts.println(fmt.Sprintf(" %s%-15s%s %s", ColorCyan, row[0], ColorReset, truncate(row[1], opts.maxWidth())))
+	}
+
+	if len(collaborators) > 0 {
+		names := make([]string, 0, len(collaborators))
+		for n := range collaborators {
+			names = append(names, n)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if collaborators[names[i]] != collaborators[names[j]] {
+				return collaborators[names[i]] > collaborators[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		if len(names) > statsCollaborators {
+			names = names[:statsCollaborators]
+		}
+
+		for i, n := range names {
+			label := ""
+			if i == 0 {
+				label = "Collaborators"
+			}
+			opts.println(fmt.Sprintf(" %s%-15s%s %s%s%s %s(%s)%s", ColorCyan, label, ColorReset,
+				ColorYellow, truncate(n, opts.maxWidth()), ColorReset, ColorWhite, pluralize(collaborators[n], "track"), ColorReset))
+		}
+	}
+	opts.println("")
+}
+
+// creditsArtist reports whether the artist is credited on the track
+func creditsArtist(t spotify.Track, artist spotify.Artist) bool {
+	for _, a := range t.Artists {
+		if a.ID == artist.ID || a.Name == artist.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// formatLongDuration formats a running time in hours and minutes
+func formatLongDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%d h %d min", hours, minutes)
+}
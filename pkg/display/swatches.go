@@ -0,0 +1,147 @@
+package display
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// swatchCount is the number of dominant colors shown under the art
+const swatchCount = 8
+
+// colorBox is a group of pixels split by median cut
+type colorBox struct {
+	pixels []rgb
+}
+
+// channel returns the value of channel c (0=r, 1=g, 2=b)
+func (c rgb) channel(i int) float64 {
+	switch i {
+	case 0:
+		return c.r
+	case 1:
+		return c.g
+	}
+	return c.b
+}
+
+// widest returns the channel with the largest range and that range
+func (b colorBox) widest() (int, float64) {
+	best, bestRange := 0, -1.0
+	for ch := 0; ch < 3; ch++ {
+		lo, hi := 255.0, 0.0
+		for _, p := range b.pixels {
+			v := p.channel(ch)
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		if hi-lo > bestRange {
+			best, bestRange = ch, hi-lo
+		}
+	}
+	return best, bestRange
+}
+
+// average returns the mean color of the box
+func (b colorBox) average() rgb {
+	var sum rgb
+	for _, p := range b.pixels {
+		sum.r += p.r
+		sum.g += p.g
+		sum.b += p.b
+	}
+	n := float64(len(b.pixels))
+	return rgb{sum.r / n, sum.g / n, sum.b / n}
+}
+
+// dominantColors extracts up to n colors from img with median cut, most
+// common first
+func dominantColors(img image.Image, n int) []rgb {
+	small := imaging.Resize(img, 64, 64, imaging.Box)
+
+	pixels := make([]rgb, 0, 64*64)
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			c := small.NRGBAAt(x, y)
+			pixels = append(pixels, rgb{float64(c.R), float64(c.G), float64(c.B)})
+		}
+	}
+
+	boxes := []colorBox{{pixels: pixels}}
+	for len(boxes) < n {
+		// Split the box with the widest spread along that channel
+		split, splitCh, splitRange := -1, 0, 0.0
+		for i, b := range boxes {
+			if len(b.pixels) < 2 {
+				continue
+			}
+			if ch, r := b.widest(); r > splitRange {
+				split, splitCh, splitRange = i, ch, r
+			}
+		}
+		if split < 0 {
+			break
+		}
+
+		box := boxes[split].pixels
+		sort.Slice(box, func(i, j int) bool {
+			return box[i].channel(splitCh) < box[j].channel(splitCh)
+		})
+		mid := len(box) / 2
+		boxes[split] = colorBox{pixels: box[:mid]}
+		boxes = append(boxes, colorBox{pixels: box[mid:]})
+	}
+
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return len(boxes[i].pixels) > len(boxes[j].pixels)
+	})
+
+	colors := make([]rgb, len(boxes))
+	for i, b := range boxes {
+		colors[i] = b.average()
+	}
+	return colors
+}
+
+// getSwatchLines renders the dominant colors as a row of swatches the width
+// of the art, using the renderer's color depth, with blank rows around it
+func (r *ImageRenderer) getSwatchLines(img image.Image) []string {
+	colors := dominantColors(img, swatchCount)
+	if len(colors) == 0 {
+		return nil
+	}
+
+	artWidth := r.width * 2
+	swatchWidth := artWidth / len(colors)
+	if swatchWidth < 1 {
+		swatchWidth = 1
+	}
+
+	var row strings.Builder
+	row.WriteString(" ") // Left padding
+	for _, c := range colors {
+		row.WriteString(r.swatchColor(c) + strings.Repeat(" ", swatchWidth) + ColorReset)
+	}
+	row.WriteString(strings.Repeat(" ", artWidth-swatchWidth*len(colors)))
+
+	blank := strings.Repeat(" ", artWidth+1)
+	return []string{blank, row.String(), blank}
+}
+
+// swatchColor returns the background sequence for c in the renderer's palette
+func (r *ImageRenderer) swatchColor(c rgb) string {
+	switch r.mode {
+	case Renderer256:
+		return palette256.codes[palette256.nearest(c)]
+	case Renderer16:
+		return palette16.codes[palette16.nearest(c)]
+	}
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm", int(c.r), int(c.g), int(c.b))
+}
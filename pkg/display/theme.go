@@ -0,0 +1,188 @@
+package display
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Theme holds the user-configurable colors used when rendering cards
+type Theme struct {
+	ChipColors  []string          // Background sequences cycled across chips
+	ChipText    string            // Foreground sequence for chip labels
+	FieldColors map[string]string // Value color overrides keyed by field name
+	Frame       string            // Border around the whole card, see FrameNames
+	ArtFrame    string            // Border around the art only, see FrameNames
+	Good        string            // Foreground for good news like "available"; "" uses green
+	Bad         string            // Foreground for bad news like "explicit"; "" uses red
+}
+
+// DefaultTheme uses the 256-color palette so chips work without truecolor
+var DefaultTheme = Theme{
+	ChipColors: []string{
+		"\033[48;5;111m", // Blue
+		"\033[48;5;150m", // Green
+		"\033[48;5;216m", // Peach
+		"\033[48;5;183m", // Mauve
+		"\033[48;5;210m", // Red
+		"\033[48;5;117m", // Sky
+		"\033[48;5;229m", // Yellow
+	},
+	ChipText: "\033[38;5;235m",
+}
+
+// ThemeNames lists the built-in themes in the order they're documented
+var ThemeNames = []string{"default", "deuteranopia", "protanopia", "tritanopia", "high-contrast"}
+
+// themePresets are the built-in themes besides the default. The color-blind
+// ones keep the chips and the good/bad colors apart in lightness as well as
+// hue, drawing on the Okabe-Ito palette.
+var themePresets = map[string]Theme{
+	// Red and green look alike, so good and bad are blue and orange
+	"deuteranopia": {
+		ChipColors: []string{
+			"\033[48;5;117m", // Sky
+			"\033[48;5;214m", // Orange
+			"\033[48;5;229m", // Pale yellow
+			"\033[48;5;175m", // Reddish purple
+			"\033[48;5;153m", // Pale blue
+			"\033[48;5;180m", // Tan
+		},
+		ChipText: "\033[38;5;235m",
+		Good:     "\033[38;5;74m",
+		Bad:      "\033[38;5;208m",
+	},
+	// Reds also look dark, so bad is a bright yellow instead of orange
+	"protanopia": {
+		ChipColors: []string{
+			"\033[48;5;111m", // Blue
+			"\033[48;5;221m", // Yellow
+			"\033[48;5;183m", // Mauve
+			"\033[48;5;153m", // Pale blue
+			"\033[48;5;229m", // Pale yellow
+			"\033[48;5;146m", // Lavender
+		},
+		ChipText: "\033[38;5;235m",
+		Good:     "\033[38;5;33m",
+		Bad:      "\033[38;5;220m",
+	},
+	// Blue and yellow look alike, so good and bad are teal and red
+	"tritanopia": {
+		ChipColors: []string{
+			"\033[48;5;210m", // Salmon
+			"\033[48;5;116m", // Pale teal
+			"\033[48;5;218m", // Pink
+			"\033[48;5;250m", // Gray
+			"\033[48;5;174m", // Dusty rose
+			"\033[48;5;152m", // Pale cyan
+		},
+		ChipText: "\033[38;5;235m",
+		Good:     "\033[38;5;37m",
+		Bad:      "\033[38;5;197m",
+	},
+	// Bold bright white values, black on bright chips
+	"high-contrast": {
+		ChipColors: []string{
+			"\033[48;5;231m", // White
+			"\033[48;5;226m", // Yellow
+			"\033[48;5;51m",  // Cyan
+			"\033[48;5;213m", // Pink
+			"\033[48;5;156m", // Light green
+			"\033[48;5;215m", // Orange
+		},
+		ChipText:    "\033[38;5;16m",
+		FieldColors: highContrastFields(),
+		Good:        "\033[1;92m",
+		Bad:         "\033[1;91m",
+	},
+}
+
+// highContrastFields draws every value in bold bright white except the
+// ones colored by whether the news is good or bad
+func highContrastFields() map[string]string {
+	colors := make(map[string]string, len(FieldNames))
+	for _, key := range FieldNames {
+		if key != "markets" && key != "explicit" {
+			colors[key] = "\033[1;97m"
+		}
+	}
+	return colors
+}
+
+// LookupTheme returns a copy of the named built-in theme
+func LookupTheme(name string) (Theme, error) {
+	if name == "" || name == "default" {
+		return DefaultTheme, nil
+	}
+	theme, ok := themePresets[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (use %s)", name, strings.Join(ThemeNames, ", "))
+	}
+	return theme, nil
+}
+
+// namedColors maps color names to their base ANSI color index
+var namedColors = map[string]int{
+	"black":   0,
+	"red":     1,
+	"green":   2,
+	"yellow":  3,
+	"blue":    4,
+	"purple":  5,
+	"magenta": 5,
+	"cyan":    6,
+	"white":   7,
+}
+
+// ParseColor converts a color spec (name, #rrggbb or 0-255) into an ANSI
+// foreground or background escape sequence
+func ParseColor(spec string, background bool) (string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+
+	base, extended := 30, 38
+	if background {
+		base, extended = 40, 48
+	}
+
+	if n, ok := namedColors[spec]; ok {
+		return fmt.Sprintf("\033[%dm", base+n), nil
+	}
+
+	if strings.HasPrefix(spec, "#") && len(spec) == 7 {
+		rgb, err := strconv.ParseUint(spec[1:], 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid hex color %q", spec)
+		}
+		return fmt.Sprintf("\033[%d;2;%d;%d;%dm", extended, rgb>>16, (rgb>>8)&0xff, rgb&0xff), nil
+	}
+
+	if n, err := strconv.Atoi(spec); err == nil && n >= 0 && n <= 255 {
+		return fmt.Sprintf("\033[%d;5;%dm", extended, n), nil
+	}
+
+	return "", fmt.Errorf("invalid color %q (use a name, #rrggbb or 0-255)", spec)
+}
+
+// theme returns the configured theme or the default one
+func (o Options) theme() *Theme {
+	if o.Theme != nil {
+		return o.Theme
+	}
+	return &DefaultTheme
+}
+
+// goodColor returns the theme's color for good news like "available"
+func (o Options) goodColor() string {
+	if c := o.theme().Good; c != "" {
+		return c
+	}
+	return ColorGreen
+}
+
+// badColor returns the theme's color for bad news like "explicit"
+func (o Options) badColor() string {
+	if c := o.theme().Bad; c != "" {
+		return c
+	}
+	return ColorRed
+}
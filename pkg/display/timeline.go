@@ -0,0 +1,104 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TimelineEntry is a single timestamped item in a listening history view
+type TimelineEntry struct {
+	Time     time.Time
+	Title    string
+	Subtitle string
+	URL      string
+}
+
+// DisplayTimeline prints entries newest first, grouped by calendar day with
+// local-time headers (UTC when utc is set) and relative timestamps
+func DisplayTimeline(entries []TimelineEntry, utc bool, opts Options) {
+	loc := time.Local
+	if utc {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+
+	sorted := make([]TimelineEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.After(sorted[j].Time)
+	})
+
+	var currentDay string
+	for _, entry := range sorted {
+		t := entry.Time.In(loc)
+
+		if day := t.Format("2006-01-02"); day != currentDay {
+			if currentDay != "" {
+				opts.println("")
+			}
+			currentDay = day
+			opts.println(fmt.Sprintf(" %s%s%s", ColorBold, formatDayHeader(t, now), ColorReset))
+		}
+
+		title := entry.Title
+		if entry.URL != "" {
+			title = createClickableLink(entry.URL, entry.Title)
+		}
+
+		line := fmt.Sprintf("   %s%s%s  %s%-14s%s  %s%s%s",
+			ColorCyan, t.Format("15:04"), ColorReset,
+			ColorWhite, formatRelativeTime(t, now), ColorReset,
+			ColorGreen, title, ColorReset)
+		if entry.Subtitle != "" {
+			line += fmt.Sprintf(" %s· %s%s", ColorYellow, entry.Subtitle, ColorReset)
+		}
+		opts.println(line)
+	}
+}
+
+// formatDayHeader names a day relative to now (Today, Yesterday) or by date
+func formatDayHeader(t, now time.Time) string {
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := now.Date()
+	today := time.Date(y2, m2, d2, 0, 0, 0, 0, now.Location())
+	day := time.Date(y1, m1, d1, 0, 0, 0, 0, now.Location())
+
+	switch today.Sub(day) / (24 * time.Hour) {
+	case 0:
+		return "Today"
+	case 1:
+		return "Yesterday"
+	}
+	return fmt.Sprintf("%s, %s", t.Format("Monday"), formatOrdinalDate(t.Format("2006-01-02")))
+}
+
+// formatRelativeTime describes how long ago t was ("2 hours ago")
+func formatRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < time.Minute {
+		return "just now"
+	}
+
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{365 * 24 * time.Hour, "year"},
+		{30 * 24 * time.Hour, "month"},
+		{7 * 24 * time.Hour, "week"},
+		{24 * time.Hour, "day"},
+		{time.Hour, "hour"},
+		{time.Minute, "minute"},
+	}
+
+	for _, u := range units {
+		if n := int(d / u.size); n >= 1 {
+			if n == 1 {
+				return fmt.Sprintf("1 %s ago", u.name)
+			}
+			return fmt.Sprintf("%d %ss ago", n, u.name)
+		}
+	}
+	return "just now"
+}
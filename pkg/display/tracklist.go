@@ -0,0 +1,86 @@
+package display
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"github.com/mattn/go-runewidth"
+)
+
+// previewTracks is how many tracks an album card lists without Options.Tracks
+const previewTracks = 5
+
+// disc is one disc of an album's tracklist
+type disc struct {
+	number   int
+	duration time.Duration
+	tracks   []spotify.Track
+}
+
+// albumTracksField lists an album's tracks in order: the first few, or all
+// of them with numbers and lengths when Options.Tracks is set
+func (o Options) albumTracksField(tracks []spotify.Track) field {
+	if !o.Tracks {
+		f := o.trackLinksField("tracklist", "Tracks", tracks[:min(len(tracks), previewTracks)])
+		if more := len(tracks) - previewTracks; more > 0 {
+			f.lines = append(f.lines, fmt.Sprintf("%s... and %d more%s", ColorDim, more, ColorReset))
+		}
+		return f
+	}
+
+	color := o.fieldColor("tracklist", ColorGreen)
+	lines := []string{"", fmt.Sprintf("%sTracks%s", ColorBold, ColorReset)}
+
+	// Numbers and lengths line up in columns
+	numberWidth, nameWidth := 1, 0
+	for _, t := range tracks {
+		numberWidth = max(numberWidth, len(strconv.Itoa(t.TrackNumber)))
+		nameWidth = max(nameWidth, runewidth.StringWidth(truncate(t.Name, o.maxWidth())))
+	}
+
+	discs := groupDiscs(tracks)
+	for i, d := range discs {
+		if len(discs) > 1 {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, fmt.Sprintf("%sDisc %d · %s%s", ColorCyan, d.number, formatDuration(d.duration), ColorReset))
+		}
+		for j, t := range d.tracks {
+			number := t.TrackNumber
+			if number == 0 {
+				number = j + 1
+			}
+			name := truncate(t.Name, o.maxWidth())
+			padding := strings.Repeat(" ", nameWidth-runewidth.StringWidth(name))
+			duration := formatDuration(time.Duration(t.Duration) * time.Millisecond)
+
+			lines = append(lines, fmt.Sprintf("%s%*d%s  %s%s%s%s  %s%s%s%s",
+				ColorDim, numberWidth, number, ColorReset,
+				color, createClickableLink(t.ExternalURL.URL(), name), ColorReset, padding,
+				ColorDim, duration, ColorReset, o.explicitMark(t)))
+		}
+	}
+	return field{key: "tracklist", lines: lines}
+}
+
+// groupDiscs splits a tracklist by disc number, in the order the discs
+// first appear
+func groupDiscs(tracks []spotify.Track) []disc {
+	var discs []disc
+	index := make(map[int]int)
+	for _, t := range tracks {
+		i, ok := index[t.DiscNumber]
+		if !ok {
+			i = len(discs)
+			index[t.DiscNumber] = i
+			discs = append(discs, disc{number: max(t.DiscNumber, 1)})
+		}
+		discs[i].duration += time.Duration(t.Duration) * time.Millisecond
+		discs[i].tracks = append(discs[i].tracks, t)
+	}
+	return discs
+}
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Status bar colors, matching the card's name and artist colors
+const (
+	barNameColor   = "#a6e3a1"
+	barArtistColor = "#f9e2af"
+)
+
+// barParts returns the title and the artist shown in a status line, cut to
+// fit the writer's MaxLength; the title gets the room it needs first
+func (w *Writer) barParts(r *Result) (string, string) {
+	name, artist := r.Name, strings.Join(r.Artists, ", ")
+	if w.MaxLength <= 0 {
+		return name, artist
+	}
+
+	name = runewidth.Truncate(name, w.MaxLength, "…")
+	room := w.MaxLength - runewidth.StringWidth(name) - len(" - ")
+	if artist != "" && room < 4 {
+		return name, ""
+	}
+	return name, runewidth.Truncate(artist, room, "…")
+}
+
+// barText is the plain "Artist - Name" status text
+func (w *Writer) barText(r *Result) string {
+	name, artist := w.barParts(r)
+	if artist == "" {
+		return name
+	}
+	return artist + " - " + name
+}
+
+// writePolybar writes one line with polybar color tags
+func writePolybar(w *Writer, r *Result) error {
+	// Polybar treats % as the start of a formatting tag
+	escape := strings.NewReplacer("%", "%%").Replace
+
+	name, artist := w.barParts(r)
+	line := fmt.Sprintf("%%{F%s}%s%%{F-}", barNameColor, escape(name))
+	if artist != "" {
+		line = fmt.Sprintf("%%{F%s}%s%%{F-} - %s", barArtistColor, escape(artist), line)
+	}
+
+	_, err := fmt.Fprintln(w.out, line)
+	return err
+}
+
+// writeI3blocks writes the full text, short text and color lines i3blocks
+// reads from a blocklet
+func writeI3blocks(w *Writer, r *Result) error {
+	name, _ := w.barParts(r)
+	_, err := fmt.Fprintf(w.out, "%s\n%s\n%s\n", w.barText(r), name, barNameColor)
+	return err
+}
+
+// writeTmux writes a #[fg=...] tagged string for tmux's status-right
+func writeTmux(w *Writer, r *Result) error {
+	// tmux expands #(...), #{...} and friends, so a literal # is doubled
+	escape := strings.NewReplacer("#", "##").Replace
+
+	name, artist := w.barParts(r)
+	line := fmt.Sprintf("#[fg=%s]%s#[default]", barNameColor, escape(name))
+	if artist != "" {
+		line = fmt.Sprintf("#[fg=%s]%s#[default] - %s", barArtistColor, escape(artist), line)
+	}
+
+	_, err := fmt.Fprintln(w.out, line)
+	return err
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+// csvColumns is the stable CSV header; new columns are only ever appended
+var csvColumns = []string{"name", "artist", "album", "isrc", "duration", "popularity", "url"}
+
+// writeCSV writes one row per result, preceded by the header on the first
+func writeCSV(w *Writer, r *Result) error {
+	cw := csv.NewWriter(w.out)
+	if w.n == 0 {
+		if err := cw.Write(csvColumns); err != nil {
+			return err
+		}
+	}
+
+	album := r.Album
+	if r.Type == "album" {
+		album = r.Name
+	}
+	duration := ""
+	if r.DurationMS > 0 {
+		duration = formatDuration(r.DurationMS)
+	}
+
+	row := []string{
+		r.Name,
+		strings.Join(r.Artists, ", "),
+		album,
+		r.ISRC,
+		duration,
+		strconv.Itoa(r.Popularity),
+		r.URL,
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,334 @@
+// Package export converts search results into a flat, documented shape and
+// writes it in machine-readable formats for scripts and other tools.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Result is the serializable form of a track, album or artist. Fields that
+// don't apply to the result type are left out.
+type Result struct {
+	Type        string         `json:"type" yaml:"type"`
+	Name        string         `json:"name" yaml:"name"`
+	ISRC        string         `json:"isrc,omitempty" yaml:"isrc,omitempty"`
+	UPC         string         `json:"upc,omitempty" yaml:"upc,omitempty"` // UPC or EAN barcode of an album
+	Artists     []string       `json:"artists,omitempty" yaml:"artists,omitempty"`
+	Album       string         `json:"album,omitempty" yaml:"album,omitempty"`
+	AlbumType   string         `json:"album_type,omitempty" yaml:"album_type,omitempty"`
+	Released    string         `json:"released,omitempty" yaml:"released,omitempty"`
+	DurationMS  int            `json:"duration_ms,omitempty" yaml:"duration_ms,omitempty"`
+	TrackNumber int            `json:"track_number,omitempty" yaml:"track_number,omitempty"`
+	TotalTracks int            `json:"total_tracks,omitempty" yaml:"total_tracks,omitempty"`
+	Explicit    *bool          `json:"explicit,omitempty" yaml:"explicit,omitempty"`
+	Popularity  int            `json:"popularity" yaml:"popularity"`
+	Followers   int            `json:"followers,omitempty" yaml:"followers,omitempty"`
+	Genres      []string       `json:"genres,omitempty" yaml:"genres,omitempty"`
+	Label       string         `json:"label,omitempty" yaml:"label,omitempty"`
+	License     string         `json:"license,omitempty" yaml:"license,omitempty"`
+	URL         string         `json:"url,omitempty" yaml:"url,omitempty"`
+	ImageURL    string         `json:"image_url,omitempty" yaml:"image_url,omitempty"`
+	Source      string         `json:"source" yaml:"source"`
+	Tracks      []TrackSummary `json:"tracks,omitempty" yaml:"tracks,omitempty"`
+}
+
+// TrackSummary is an entry in an album's tracklist
+type TrackSummary struct {
+	Number     int    `json:"number" yaml:"number"`
+	Disc       int    `json:"disc,omitempty" yaml:"disc,omitempty"`
+	Name       string `json:"name" yaml:"name"`
+	DurationMS int    `json:"duration_ms" yaml:"duration_ms"`
+	Explicit   bool   `json:"explicit" yaml:"explicit"`
+	URL        string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// FromTrack flattens a track; source is the provider's display name
+func FromTrack(t spotify.Track, source string) *Result {
+	return &Result{
+		Type:        "track",
+		Name:        t.Name,
+		Artists:     artistNames(t.Artists),
+		ISRC:        t.ExternalIDs.ISRC,
+		Album:       t.Album.Name,
+		Released:    t.Album.ReleaseDate,
+		DurationMS:  t.Duration,
+		TrackNumber: t.TrackNumber,
+		Explicit:    &t.Explicit,
+		Popularity:  t.Popularity,
+		Genres:      t.Album.Genres,
+		License:     licenseName(t.License),
+		URL:         t.ExternalURL.URL(),
+		ImageURL:    firstImage(t.Album.Images),
+		Source:      source,
+	}
+}
+
+// FromAlbum flattens an album including its tracklist
+func FromAlbum(a spotify.Album, source string) *Result {
+	r := &Result{
+		Type:        "album",
+		Name:        a.Name,
+		Artists:     artistNames(a.Artists),
+		UPC:         a.ExternalIDs.UPC,
+		AlbumType:   a.AlbumType,
+		Released:    a.ReleaseDate,
+		TotalTracks: a.TotalTracks,
+		Popularity:  a.Popularity,
+		Genres:      a.Genres,
+		Label:       a.Label,
+		License:     licenseName(a.License),
+		URL:         a.ExternalURL.URL(),
+		ImageURL:    firstImage(a.Images),
+		Source:      source,
+	}
+
+	explicit := false
+	for _, t := range a.Tracks.Items {
+		r.DurationMS += t.Duration
+		explicit = explicit || t.Explicit
+		r.Tracks = append(r.Tracks, TrackSummary{
+			Number:     t.TrackNumber,
+			Disc:       t.DiscNumber,
+			Name:       t.Name,
+			DurationMS: t.Duration,
+			Explicit:   t.Explicit,
+			URL:        t.ExternalURL.URL(),
+		})
+	}
+	if len(a.Tracks.Items) > 0 {
+		r.Explicit = &explicit
+	}
+
+	return r
+}
+
+// Disc is one disc of an album's tracklist
+type Disc struct {
+	Number     int
+	DurationMS int
+	Tracks     []TrackSummary
+}
+
+// Discs splits the tracklist by disc number, in the order the discs first
+// appear; single-disc albums come back as one disc
+func (r *Result) Discs() []Disc {
+	var discs []Disc
+	index := make(map[int]int)
+	for _, t := range r.Tracks {
+		i, ok := index[t.Disc]
+		if !ok {
+			i = len(discs)
+			index[t.Disc] = i
+			discs = append(discs, Disc{Number: max(t.Disc, 1)})
+		}
+		discs[i].DurationMS += t.DurationMS
+		discs[i].Tracks = append(discs[i].Tracks, t)
+	}
+	return discs
+}
+
+// FromArtist flattens an artist
+func FromArtist(a spotify.Artist, source string) *Result {
+	return &Result{
+		Type:       "artist",
+		Name:       a.Name,
+		Popularity: a.Popularity,
+		Followers:  a.Followers.Total,
+		Genres:     a.Genres,
+		URL:        a.ExternalURL.URL(),
+		ImageURL:   firstImage(a.Images),
+		Source:     source,
+	}
+}
+
+// FromEpisode flattens a podcast episode; the show stands in for the album
+// and its publisher for the artist
+func FromEpisode(e spotify.Episode, source string) *Result {
+	r := &Result{
+		Type:       "episode",
+		Name:       e.Name,
+		Album:      e.Show.Name,
+		Released:   e.ReleaseDate,
+		DurationMS: e.Duration,
+		Explicit:   &e.Explicit,
+		URL:        e.ExternalURL.URL(),
+		ImageURL:   firstImage(e.Images),
+		Source:     source,
+	}
+	if e.Show.Publisher != "" {
+		r.Artists = []string{e.Show.Publisher}
+	}
+	return r
+}
+
+// writers maps each output format to its encoder
+var writers = map[string]func(w *Writer, r *Result) error{
+	"json":      writeJSON,
+	"yaml":      writeYAML,
+	"markdown":  writeMarkdown,
+	"csv":       writeCSV,
+	"polybar":   writePolybar,
+	"i3blocks":  writeI3blocks,
+	"tmux":      writeTmux,
+	"porcelain": writePorcelain,
+}
+
+// FormatNames lists the supported output formats
+func FormatNames() []string {
+	names := make([]string, 0, len(writers))
+	for name := range writers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rows lists the label and value of each field that applies to the result
+func (r *Result) Rows() [][2]string {
+	var rows [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			rows = append(rows, [2]string{label, value})
+		}
+	}
+
+	add("Type", r.Type)
+	add("Artist", strings.Join(r.Artists, ", "))
+	add("Album", r.Album)
+	add("ISRC", r.ISRC)
+	add("UPC", r.UPC)
+	add("Album type", r.AlbumType)
+	add("Released", r.Released)
+	if r.DurationMS > 0 {
+		add("Duration", formatDuration(r.DurationMS))
+	}
+	if r.TrackNumber > 0 {
+		add("Track", fmt.Sprint(r.TrackNumber))
+	}
+	if r.TotalTracks > 0 {
+		add("Tracks", fmt.Sprint(r.TotalTracks))
+	}
+	if r.Explicit != nil {
+		add("Explicit", yesNo(*r.Explicit))
+	}
+	if r.Followers > 0 {
+		add("Followers", fmt.Sprint(r.Followers))
+	}
+	add("Popularity", fmt.Sprintf("%d%%", r.Popularity))
+	add("Genres", strings.Join(r.Genres, ", "))
+	add("Label", r.Label)
+	add("License", r.License)
+	add("Source", r.Source)
+	return rows
+}
+
+// formatDuration renders milliseconds as m:ss or h:mm:ss
+func formatDuration(ms int) string {
+	s := ms / 1000
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// yesNo renders a boolean for people
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// Writer encodes a stream of results in one format
+type Writer struct {
+	// MaxLength caps status line formats (polybar, tmux, ...) in terminal
+	// cells; 0 means no limit
+	MaxLength int
+
+	out      io.Writer
+	format   string
+	template *template.Template // Set by NewTemplateWriter
+	n        int                // Results written so far, for headers and separators
+}
+
+// NewWriter creates a writer for the given format
+func NewWriter(w io.Writer, format string) *Writer {
+	return &Writer{out: w, format: format}
+}
+
+// Write encodes the next result
+func (w *Writer) Write(r *Result) error {
+	write, ok := writers[w.format]
+	if w.template != nil {
+		write, ok = writeTemplate, true
+	}
+	if !ok {
+		return fmt.Errorf("unknown output format: %s", w.format)
+	}
+	if err := write(w, r); err != nil {
+		return err
+	}
+	w.n++
+	return nil
+}
+
+// Write encodes a single result to w in the given format
+func Write(w io.Writer, format string, r *Result) error {
+	return NewWriter(w, format).Write(r)
+}
+
+// writeJSON writes indented JSON
+func writeJSON(w *Writer, r *Result) error {
+	enc := json.NewEncoder(w.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// writeYAML writes a YAML document, separating it from earlier ones
+func writeYAML(w *Writer, r *Result) error {
+	if w.n > 0 {
+		if _, err := io.WriteString(w.out, "---\n"); err != nil {
+			return err
+		}
+	}
+
+	enc := yaml.NewEncoder(w.out)
+	enc.SetIndent(2)
+	if err := enc.Encode(r); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// artistNames lists the names of artists
+func artistNames(artists []spotify.Artist) []string {
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	return names
+}
+
+// firstImage returns the URL of the largest image
+func firstImage(images []spotify.Image) string {
+	if len(images) == 0 {
+		return ""
+	}
+	return images[0].URL
+}
+
+// licenseName returns the license name if there is one
+func licenseName(l *spotify.License) string {
+	if l == nil {
+		return ""
+	}
+	return l.Name
+}
@@ -0,0 +1,219 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feed describes a feed of releases
+type Feed struct {
+	Title   string
+	Link    string    // Page the feed is about, required by RSS
+	ID      string    // URI that identifies the feed for good, required by Atom
+	Updated time.Time // When the feed was generated
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel is the channel of an RSS feed
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem is one release in an RSS feed
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Description string  `xml:"description"`
+}
+
+// rssGUID identifies an RSS item; permalinks are also its URL
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// atomFeed is an Atom 1.0 document
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Link      *atomLink   `xml:"link,omitempty"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+// atomEntry is one release in an Atom feed
+type atomEntry struct {
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Updated string       `xml:"updated"`
+	Authors []atomAuthor `xml:"author"`
+	Link    *atomLink    `xml:"link,omitempty"`
+	Summary string       `xml:"summary"`
+}
+
+// atomLink points an Atom feed or entry at a web page
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// atomAuthor names one of an entry's artists
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// WriteFeed writes results as an RSS or Atom feed, newest release first
+func WriteFeed(w io.Writer, format string, feed Feed, results []*Result) error {
+	sorted := make([]*Result, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Released > sorted[j].Released
+	})
+
+	var doc interface{}
+	switch format {
+	case "rss":
+		doc = rssDocument(feed, sorted)
+	case "atom":
+		doc = atomDocument(feed, sorted)
+	default:
+		return fmt.Errorf("unknown feed format: %s", format)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// rssDocument builds the RSS form of a feed
+func rssDocument(feed Feed, results []*Result) rssFeed {
+	channel := rssChannel{
+		Title:         feed.Title,
+		Link:          feed.Link,
+		Description:   feed.Title,
+		LastBuildDate: feed.Updated.Format(time.RFC1123Z),
+		Generator:     "mufetch",
+	}
+	for _, r := range results {
+		item := rssItem{
+			Title:       feedTitle(r),
+			Link:        r.URL,
+			GUID:        rssGUID{Value: feedEntryID(r), IsPermaLink: r.URL != ""},
+			Description: feedSummary(r),
+		}
+		if t, ok := releaseTime(r.Released); ok {
+			item.PubDate = t.Format(time.RFC1123Z)
+		}
+		channel.Items = append(channel.Items, item)
+	}
+	return rssFeed{Version: "2.0", Channel: channel}
+}
+
+// atomDocument builds the Atom form of a feed
+func atomDocument(feed Feed, results []*Result) atomFeed {
+	doc := atomFeed{
+		Title:     feed.Title,
+		ID:        feed.ID,
+		Updated:   feed.Updated.Format(time.RFC3339),
+		Generator: "mufetch",
+	}
+	if feed.Link != "" {
+		doc.Link = &atomLink{Href: feed.Link}
+	}
+	for _, r := range results {
+		// Atom requires a timestamp; releases without a date use the feed's
+		updated := feed.Updated
+		if t, ok := releaseTime(r.Released); ok {
+			updated = t
+		}
+		entry := atomEntry{
+			Title:   feedTitle(r),
+			ID:      feedEntryID(r),
+			Updated: updated.Format(time.RFC3339),
+			Summary: feedSummary(r),
+		}
+		for _, name := range r.Artists {
+			entry.Authors = append(entry.Authors, atomAuthor{Name: name})
+		}
+		if r.URL != "" {
+			entry.Link = &atomLink{Href: r.URL}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+// feedTitle names a release as "Artist - Name"
+func feedTitle(r *Result) string {
+	if len(r.Artists) == 0 {
+		return r.Name
+	}
+	return strings.Join(r.Artists, ", ") + " - " + r.Name
+}
+
+// feedEntryID identifies a release across feed updates by its link, or by
+// its artists, name and date when it has none
+func feedEntryID(r *Result) string {
+	if r.URL != "" {
+		return r.URL
+	}
+	return "urn:mufetch:" + url.QueryEscape(feedTitle(r)+":"+r.Released)
+}
+
+// feedSummary describes a release in one line, e.g. "Single · 2 tracks ·
+// Released 2024-05-17 · Hyperdub"
+func feedSummary(r *Result) string {
+	var parts []string
+	if r.AlbumType != "" {
+		parts = append(parts, strings.ToUpper(r.AlbumType[:1])+r.AlbumType[1:])
+	}
+	switch {
+	case r.TotalTracks == 1:
+		parts = append(parts, "1 track")
+	case r.TotalTracks > 1:
+		parts = append(parts, fmt.Sprintf("%d tracks", r.TotalTracks))
+	}
+	if r.Released != "" {
+		parts = append(parts, "Released "+r.Released)
+	}
+	if r.Label != "" {
+		parts = append(parts, r.Label)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// releaseTime parses a release date at year, month or day precision
+func releaseTime(date string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
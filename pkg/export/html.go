@@ -0,0 +1,99 @@
+package export
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+)
+
+// htmlTemplate is a self-contained page of cards; styles are inlined so the
+// file can be shared on its own
+var htmlTemplate = template.Must(template.New("card").Funcs(template.FuncMap{"duration": formatDuration}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{range $i, $c := .}}{{if $i}}, {{end}}{{$c.Result.Name}}{{end}}</title>
+<style>
+body { background: #1e1e2e; color: #cdd6f4; font-family: ui-monospace, "JetBrains Mono", Menlo, monospace; margin: 2rem; }
+.card { display: flex; gap: 2rem; align-items: flex-start; background: #181825; border-radius: 12px; padding: 1.5rem; margin-bottom: 2rem; max-width: 900px; }
+.card img { width: 300px; height: 300px; object-fit: cover; border-radius: 8px; }
+h1 { margin: 0 0 1rem; font-size: 1.5rem; color: #a6e3a1; }
+table { border-collapse: collapse; }
+th { text-align: left; padding: 0.15rem 1.5rem 0.15rem 0; color: #f9e2af; font-weight: 600; }
+td { padding: 0.15rem 0; }
+ol { margin: 1rem 0 0; padding-left: 1.5rem; }
+h2 { margin: 1rem 0 0; font-size: 1rem; color: #f9e2af; }
+a { color: #89b4fa; }
+.links { margin-top: 1rem; }
+</style>
+</head>
+<body>
+{{range .}}<div class="card">
+{{if .Image}}<img src="{{.Image}}" alt="{{.Result.Name}} cover">{{end}}
+<div>
+<h1>{{.Result.Name}}</h1>
+<table>
+{{range .Result.Rows}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+{{$discs := .Result.Discs}}{{range $discs}}{{if gt (len $discs) 1}}<h2>Disc {{.Number}} · {{duration .DurationMS}}</h2>
+{{end}}<ol>
+{{range .Tracks}}<li>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</li>
+{{end}}</ol>
+{{end}}
+<div class="links">{{if .Result.URL}}<a href="{{.Result.URL}}">{{.Result.Source}}</a>{{end}}{{if .Result.ImageURL}} · <a href="{{.Result.ImageURL}}">Cover Art</a>{{end}}</div>
+</div>
+</div>
+{{end}}</body>
+</html>
+`))
+
+// htmlCard is a result plus its cover inlined as a data URL
+type htmlCard struct {
+	Result *Result
+	Image  template.URL
+}
+
+// WriteHTML writes a self-contained HTML page with a card per result, the
+// cover art embedded as base64 so no external requests are needed
+func WriteHTML(w io.Writer, results []*Result) error {
+	cards := make([]htmlCard, len(results))
+	for i, r := range results {
+		cards[i] = htmlCard{Result: r}
+		if r.ImageURL != "" {
+			// A missing cover still leaves a usable card
+			if data, err := inlineImage(r.ImageURL); err == nil {
+				cards[i].Image = data
+			}
+		}
+	}
+	return htmlTemplate.Execute(w, cards)
+}
+
+// inlineImage downloads an image and returns it as a data URL
+func inlineImage(url string) (template.URL, error) {
+	resp, err := httpclient.Client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download image: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return template.URL("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)), nil
+}
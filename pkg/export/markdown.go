@@ -0,0 +1,66 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeMarkdown writes a note-friendly summary: heading, cover image, a
+// table of fields and the tracklist for albums
+func writeMarkdown(w *Writer, r *Result) error {
+	var b strings.Builder
+	if w.n > 0 {
+		b.WriteString("\n")
+	}
+
+	title := markdownEscape(r.Name)
+	if r.URL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, r.URL)
+	}
+	fmt.Fprintf(&b, "## %s\n\n", title)
+
+	if r.ImageURL != "" {
+		fmt.Fprintf(&b, "![%s cover](%s)\n\n", markdownEscape(r.Name), r.ImageURL)
+	}
+
+	b.WriteString("| Field | Value |\n|---|---|\n")
+	for _, row := range r.Rows() {
+		fmt.Fprintf(&b, "| %s | %s |\n", row[0], tableEscape(row[1]))
+	}
+
+	if len(r.Tracks) > 0 {
+		b.WriteString("\n### Tracks\n")
+		discs := r.Discs()
+		for _, disc := range discs {
+			if len(discs) > 1 {
+				fmt.Fprintf(&b, "\n#### Disc %d (%s)\n", disc.Number, formatDuration(disc.DurationMS))
+			}
+			b.WriteString("\n")
+			for _, t := range disc.Tracks {
+				name := markdownEscape(t.Name)
+				if t.URL != "" {
+					name = fmt.Sprintf("[%s](%s)", name, t.URL)
+				}
+				explicit := ""
+				if t.Explicit {
+					explicit = " *(explicit)*"
+				}
+				fmt.Fprintf(&b, "%d. %s (%s)%s\n", t.Number, name, formatDuration(t.DurationMS), explicit)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w.out, b.String())
+	return err
+}
+
+// markdownEscape escapes characters that would start Markdown formatting
+var markdownEscape = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`",
+).Replace
+
+// tableEscape also escapes the pipes that delimit table cells
+func tableEscape(s string) string {
+	return strings.ReplaceAll(markdownEscape(s), "|", `\|`)
+}
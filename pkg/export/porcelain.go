@@ -0,0 +1,72 @@
+package export
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PorcelainVersion is bumped only if the porcelain format ever has to change
+// incompatibly; new keys may be appended without a bump
+const PorcelainVersion = 1
+
+// porcelainEscape keeps every value on one line
+var porcelainEscape = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`).Replace
+
+// writePorcelain writes stable key=value lines, one key per line in a fixed
+// order. Every key is always present (empty when it doesn't apply), list
+// values repeat their key once per item, and results are separated by a
+// blank line.
+func writePorcelain(w *Writer, r *Result) error {
+	var b strings.Builder
+	if w.n > 0 {
+		b.WriteString("\n")
+	}
+
+	add := func(key, value string) {
+		b.WriteString(key + "=" + porcelainEscape(value) + "\n")
+	}
+	addList := func(key string, values []string) {
+		if len(values) == 0 {
+			add(key, "")
+		}
+		for _, v := range values {
+			add(key, v)
+		}
+	}
+	number := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+
+	explicit := ""
+	if r.Explicit != nil {
+		explicit = strconv.FormatBool(*r.Explicit)
+	}
+
+	add("version", strconv.Itoa(PorcelainVersion))
+	add("type", r.Type)
+	add("name", r.Name)
+	addList("artist", r.Artists)
+	add("album", r.Album)
+	add("album_type", r.AlbumType)
+	add("released", r.Released)
+	add("duration_ms", number(r.DurationMS))
+	add("track_number", number(r.TrackNumber))
+	add("total_tracks", number(r.TotalTracks))
+	add("explicit", explicit)
+	add("popularity", strconv.Itoa(r.Popularity))
+	add("followers", number(r.Followers))
+	addList("genre", r.Genres)
+	add("label", r.Label)
+	add("license", r.License)
+	add("isrc", r.ISRC)
+	add("url", r.URL)
+	add("image_url", r.ImageURL)
+	add("source", r.Source)
+	add("upc", r.UPC)
+
+	_, err := w.out.Write([]byte(b.String()))
+	return err
+}
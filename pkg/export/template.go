@@ -0,0 +1,81 @@
+package export
+
+import (
+	"io"
+	"strings"
+	"text/template"
+)
+
+// templateAlbum is the album as seen by --format templates
+type templateAlbum struct {
+	Name        string
+	ReleaseDate string
+	ReleaseYear string
+}
+
+// String lets {{.Album}} print the album name
+func (a templateAlbum) String() string {
+	return a.Name
+}
+
+// templateData is what --format templates are executed against: every
+// Result field plus a few conveniences
+type templateData struct {
+	*Result
+	Artist   string        // All artists joined with ", "
+	Album    templateAlbum // Shadows Result.Album
+	Duration string        // m:ss or h:mm:ss
+}
+
+// templateFuncs are available in --format templates
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"truncate": func(max int, s string) string {
+		runes := []rune(s)
+		if len(runes) <= max {
+			return s
+		}
+		return string(runes[:max]) + "…"
+	},
+}
+
+// NewTemplateWriter creates a writer that formats each result with a Go
+// template, e.g. "{{.Artist}} - {{.Name}} ({{.Album.ReleaseYear}})"
+func NewTemplateWriter(w io.Writer, format string) (*Writer, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{out: w, format: "template", template: tmpl}, nil
+}
+
+// writeTemplate executes the writer's template for r on its own line
+func writeTemplate(w *Writer, r *Result) error {
+	data := templateData{
+		Result: r,
+		Artist: strings.Join(r.Artists, ", "),
+		Album:  templateAlbum{Name: r.Album, ReleaseDate: r.Released},
+	}
+	if r.Type == "album" {
+		data.Album.Name = r.Name
+	}
+	if len(r.Released) >= 4 {
+		data.Album.ReleaseYear = r.Released[:4]
+	}
+	if r.DurationMS > 0 {
+		data.Duration = formatDuration(r.DurationMS)
+	}
+
+	var b strings.Builder
+	if err := w.template.Execute(&b, data); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w.out, b.String())
+	return err
+}
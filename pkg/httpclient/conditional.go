@@ -0,0 +1,135 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/cache"
+)
+
+// ResponseCache, if set, keeps JSON responses that carry an ETag or
+// Last-Modified, and asks the server whether they changed before using
+// them again, so a repeated lookup costs a 304 instead of the full body
+var ResponseCache *cache.Cache
+
+// maxResponseBytes is the largest response worth caching
+const maxResponseBytes = 1 << 20
+
+// conditionalTransport revalidates and fills ResponseCache
+type conditionalTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends a GET as a conditional request when a cached copy exists
+// and answers a 304 with that copy
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	store := ResponseCache
+	if store == nil || req.Method != http.MethodGet ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// Translated names come back for other locales
+	key := req.URL.String() + "\n" + req.Header.Get("Accept-Language")
+	entry, _ := store.GetEntry(cache.Responses, key)
+
+	send := req
+	if entry != nil {
+		send = req.Clone(req.Context())
+		Revalidate(send, entry)
+	}
+	resp, err := t.base.RoundTrip(send)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		slog.Debug("response cache hit", "url", Redact(req.URL))
+		Refresh(entry, resp)
+		store.PutEntry(cache.Responses, key, entry)
+		return cachedResponse(req, entry), nil
+
+	case resp.StatusCode == http.StatusOK && storable(resp):
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		store.PutEntry(cache.Responses, key, NewEntry(resp, body))
+	}
+	return resp, nil
+}
+
+// storable reports whether a response can be cached and revalidated later
+func storable(resp *http.Response) bool {
+	if resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "" {
+		return false
+	}
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	if resp.ContentLength > maxResponseBytes {
+		return false
+	}
+	return strings.Contains(resp.Header.Get("Content-Type"), "json")
+}
+
+// cachedResponse turns a revalidated entry into a 200 for req
+func cachedResponse(req *http.Request, e *cache.Entry) *http.Response {
+	header := make(http.Header)
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(e.Body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// NewEntry builds a cache entry from a 200 response and its body
+func NewEntry(resp *http.Response, body []byte) *cache.Entry {
+	return &cache.Entry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Checked:      time.Now(),
+		Body:         body,
+	}
+}
+
+// Revalidate makes req conditional on e having changed
+func Revalidate(req *http.Request, e *cache.Entry) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// Refresh records a 304 for e, picking up any new validators it carries
+func Refresh(e *cache.Entry, resp *http.Response) {
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		e.ETag = etag
+	}
+	if modified := resp.Header.Get("Last-Modified"); modified != "" {
+		e.LastModified = modified
+	}
+	e.Checked = time.Now()
+}
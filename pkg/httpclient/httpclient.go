@@ -0,0 +1,253 @@
+// Package httpclient holds the HTTP client shared by every API and image
+// request, so a lookup that makes several calls reuses its connections
+// instead of paying for a new TLS handshake each time
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Timeout is the default AttemptTimeout
+const Timeout = 30 * time.Second
+
+// AttemptTimeout bounds each attempt at a request, including reading the
+// body; retries and rate-limit waits get a fresh one. Zero means no limit.
+var AttemptTimeout = Timeout
+
+// Transport pools connections per host and goes through the proxy picked
+// by proxyFor
+var Transport = &http.Transport{
+	Proxy: proxyFor,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          32,
+	MaxIdleConnsPerHost:   8, // Album and artist cards fetch a few things at once
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: 20 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+// Retries is how many times a request is repeated after a transient
+// failure: a 5xx response or a dropped connection
+var Retries = 2
+
+// MaxRateLimitWait is the longest Retry-After a rate-limited request waits
+// out; longer waits return the 429 so the caller can report it
+const MaxRateLimitWait = time.Minute
+
+// maxRateLimitWaits bounds how many times one request waits out a 429
+const maxRateLimitWaits = 3
+
+// OnRateLimit, if set, is called before waiting out a 429 response, e.g.
+// to tell the user why the lookup stalls
+var OnRateLimit func(wait time.Duration)
+
+// proxyURL is the proxy set with SetProxy; nil falls back to the
+// environment
+var proxyURL *url.URL
+
+// SetProxy routes every request through rawURL, an http://, https://,
+// socks5:// or socks5h:// proxy, instead of the one from the environment
+func SetProxy(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("invalid proxy %q: the scheme must be http, https, socks5 or socks5h", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid proxy %q: missing host", rawURL)
+	}
+	proxyURL = u
+	return nil
+}
+
+// envProxy reads HTTP_PROXY, HTTPS_PROXY and NO_PROXY like
+// http.ProxyFromEnvironment, with ALL_PROXY (as curl uses it) standing in
+// for whichever of the first two is unset
+var envProxy = sync.OnceValue(func() func(*url.URL) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	all := os.Getenv("ALL_PROXY")
+	if all == "" {
+		all = os.Getenv("all_proxy")
+	}
+	if config.HTTPProxy == "" {
+		config.HTTPProxy = all
+	}
+	if config.HTTPSProxy == "" {
+		config.HTTPSProxy = all
+	}
+	return config.ProxyFunc()
+})
+
+// proxyFor returns the proxy for a request, nil for a direct connection
+func proxyFor(req *http.Request) (*url.URL, error) {
+	if proxyURL != nil {
+		return proxyURL, nil
+	}
+	return envProxy()(req.URL)
+}
+
+// Client is the shared client. It has no overall Timeout, since that would
+// also cut short retries and rate-limit waits; see AttemptTimeout.
+var Client = &http.Client{
+	Transport: &conditionalTransport{base: &retryTransport{base: &logTransport{base: Transport}}},
+}
+
+// retryTransport repeats requests that failed for reasons likely to go away,
+// waiting a little longer (with jitter) before each attempt
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request, retrying transient failures
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	send := req
+	attempt, waits := 0, 0
+	for {
+		resp, err := t.attempt(send)
+
+		// Rate limits say how long to back off, and don't use up retries
+		var delay time.Duration
+		if wait, ok := rateLimitWait(req, resp, err); ok && waits < maxRateLimitWaits {
+			waits++
+			delay = wait
+			if OnRateLimit != nil {
+				OnRateLimit(wait)
+			}
+			slog.Debug("rate limited", "url", Redact(req.URL), "wait", wait)
+		} else if attempt < Retries && retryable(resp, err) {
+			delay = backoff(attempt)
+			attempt++
+			slog.Debug("retrying", "url", Redact(req.URL), "attempt", attempt, "delay", delay.Round(time.Millisecond))
+		} else {
+			return resp, err
+		}
+
+		// Requests with a body can only be sent again if it can be rewound
+		send = req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			send.Body = body
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attempt sends the request once under AttemptTimeout, which keeps running
+// until the response body is closed
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if AttemptTimeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), AttemptTimeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases an attempt's timeout once its body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and stops its timeout
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// rateLimitWait returns how long a 429 response asks to wait, if that is
+// short enough to wait out before the request's deadline
+func rateLimitWait(req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	// Retry-After is either seconds or an HTTP date
+	wait := time.Second
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			wait = time.Duration(secs) * time.Second
+		} else if at, err := http.ParseTime(v); err == nil {
+			wait = time.Until(at)
+		}
+	}
+	wait = max(wait, 0)
+
+	if wait > MaxRateLimitWait {
+		return 0, false
+	}
+	if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+		return 0, false
+	}
+	return wait, true
+}
+
+// retryable reports whether a failure is worth another attempt
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// Cancelled and timed out requests are not repeated
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns a random wait of up to 250ms, doubling with each attempt
+// up to 4s, so clients that failed together don't retry together
+func backoff(attempt int) time.Duration {
+	limit := 250 * time.Millisecond << min(attempt, 4)
+	return time.Duration(rand.Int64N(int64(limit))) + 50*time.Millisecond
+}
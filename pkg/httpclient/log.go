@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// secretParams are query parameters hidden from logs
+var secretParams = []string{"api_key", "key", "token", "access_token", "client_secret"}
+
+// logTransport logs every request sent over the network at debug level
+type logTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request and logs its status and timing
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		slog.Debug("request failed", "method", req.Method, "url", Redact(req.URL), "elapsed", elapsed, "err", err)
+		return resp, err
+	}
+	slog.Debug("request", "method", req.Method, "url", Redact(req.URL), "status", resp.StatusCode, "elapsed", elapsed)
+	return resp, err
+}
+
+// Redact returns u as a string with API keys and tokens masked
+func Redact(u *url.URL) string {
+	query := u.Query()
+	hidden := false
+	for _, name := range secretParams {
+		if query.Has(name) {
+			query.Set(name, "REDACTED")
+			hidden = true
+		}
+	}
+	if !hidden {
+		return u.String()
+	}
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
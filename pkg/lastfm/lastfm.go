@@ -0,0 +1,98 @@
+// Package lastfm finds similar artists with the Last.fm API
+// (https://www.last.fm/api), which needs a free API key.
+package lastfm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+)
+
+// ErrNotFound is returned when Last.fm doesn't know the artist
+var ErrNotFound = errors.New("artist not found on Last.fm")
+
+// DefaultBaseURL is the public Last.fm API
+const DefaultBaseURL = "https://ws.audioscrobbler.com/2.0/"
+
+// Similar is an artist similar to another, with Last.fm's match score
+type Similar struct {
+	Name  string
+	Match float64 // 0 to 1, higher is more similar
+	URL   string
+}
+
+// Client calls the Last.fm API with an API key
+type Client struct {
+	APIKey  string
+	BaseURL string
+}
+
+// NewClient creates a client for the public Last.fm API
+func NewClient(apiKey string) *Client {
+	return &Client{APIKey: apiKey, BaseURL: DefaultBaseURL}
+}
+
+// SimilarArtists returns up to limit artists similar to artist, most
+// similar first
+func (c *Client) SimilarArtists(ctx context.Context, artist string, limit int) ([]Similar, error) {
+	params := url.Values{}
+	params.Set("method", "artist.getsimilar")
+	params.Set("artist", artist)
+	params.Set("autocorrect", "1")
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("api_key", c.APIKey)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "mufetch (https://github.com/ashish0kumar/mufetch)")
+
+	resp, err := httpclient.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Errors come back as JSON, often with a 200 status
+	var result struct {
+		Error          int    `json:"error"`
+		Message        string `json:"message"`
+		SimilarArtists struct {
+			Artist []struct {
+				Name  string `json:"name"`
+				Match string `json:"match"`
+				URL   string `json:"url"`
+			} `json:"artist"`
+		} `json:"similarartists"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("Last.fm request failed: %s", resp.Status)
+	}
+
+	switch result.Error {
+	case 0:
+	case 6: // Invalid parameters, which is how unknown artists are reported
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("Last.fm request failed: %s", strings.TrimSpace(result.Message))
+	}
+
+	similar := make([]Similar, 0, len(result.SimilarArtists.Artist))
+	for _, a := range result.SimilarArtists.Artist {
+		match, _ := strconv.ParseFloat(a.Match, 64)
+		similar = append(similar, Similar{Name: a.Name, Match: match, URL: a.URL})
+	}
+	if len(similar) == 0 {
+		return nil, ErrNotFound
+	}
+	return similar, nil
+}
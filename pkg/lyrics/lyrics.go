@@ -0,0 +1,132 @@
+// Package lyrics fetches song lyrics from LRCLIB (https://lrclib.net), a
+// free lyrics database that needs no API key.
+package lyrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+)
+
+// ErrNotFound is returned when no lyrics exist for the track
+var ErrNotFound = errors.New("no lyrics found")
+
+// DefaultBaseURL is the public LRCLIB API
+const DefaultBaseURL = "https://lrclib.net/api"
+
+// Lyrics holds the plain and time-synced lyrics of a track
+type Lyrics struct {
+	TrackName    string  `json:"trackName"`
+	ArtistName   string  `json:"artistName"`
+	AlbumName    string  `json:"albumName"`
+	Duration     float64 `json:"duration"`
+	Instrumental bool    `json:"instrumental"`
+	Plain        string  `json:"plainLyrics"`
+	Synced       string  `json:"syncedLyrics"`
+}
+
+// Client looks lyrics up on an LRCLIB instance
+type Client struct {
+	BaseURL string
+}
+
+// NewClient creates a client for the public LRCLIB API
+func NewClient() *Client {
+	return &Client{BaseURL: DefaultBaseURL}
+}
+
+// Get finds lyrics for a track, preferring the match whose length is closest
+// to duration when there are several (pass 0 to take the first)
+func (c *Client) Get(ctx context.Context, artist, track string, duration time.Duration) (*Lyrics, error) {
+	params := url.Values{}
+	params.Set("track_name", track)
+	params.Set("artist_name", artist)
+	reqURL := fmt.Sprintf("%s/search?%s", strings.TrimRight(c.BaseURL, "/"), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "mufetch (https://github.com/ashish0kumar/mufetch)")
+
+	resp, err := httpclient.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("lyrics search failed: %s", resp.Status)
+	}
+
+	var results []Lyrics
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+
+	var best *Lyrics
+	bestDiff := math.MaxFloat64
+	for i := range results {
+		r := &results[i]
+		if r.Plain == "" && !r.Instrumental {
+			continue
+		}
+		if duration == 0 {
+			return r, nil
+		}
+		if diff := math.Abs(r.Duration - duration.Seconds()); diff < bestDiff {
+			best, bestDiff = r, diff
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNotFound
+	}
+	return best, nil
+}
+
+// Lines returns the plain lyrics split into lines
+func (l *Lyrics) Lines() []string {
+	if l.Instrumental {
+		return []string{"(instrumental)"}
+	}
+	return strings.Split(strings.TrimSpace(strings.ReplaceAll(l.Plain, "\r\n", "\n")), "\n")
+}
+
+// FirstSection returns the opening stanzas of the lyrics, usually the first
+// verse and chorus, stopping at a blank line once maxLines is reached
+func (l *Lyrics) FirstSection(maxLines int) []string {
+	var section []string
+	stanzas := 0
+
+	for _, line := range l.Lines() {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(section) > 0 && section[len(section)-1] != "" {
+				stanzas++
+				if stanzas == 2 || len(section) >= maxLines {
+					break
+				}
+				section = append(section, "")
+			}
+			continue
+		}
+		if len(section) >= maxLines {
+			break
+		}
+		section = append(section, line)
+	}
+
+	for len(section) > 0 && section[len(section)-1] == "" {
+		section = section[:len(section)-1]
+	}
+	return section
+}
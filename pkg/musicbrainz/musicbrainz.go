@@ -0,0 +1,233 @@
+// Package musicbrainz looks up recording credits (songwriters, producers,
+// engineers, performers) on MusicBrainz (https://musicbrainz.org), which
+// needs no API key.
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+)
+
+// ErrNotFound is returned when MusicBrainz has no matching recording
+var ErrNotFound = errors.New("recording not found on MusicBrainz")
+
+// DefaultBaseURL is the public MusicBrainz web service
+const DefaultBaseURL = "https://musicbrainz.org/ws/2"
+
+// requestGap keeps requests within MusicBrainz's limit of one per second
+const requestGap = time.Second
+
+// Credit is one person's role on a recording or on the song it performs
+type Credit struct {
+	Role string // e.g. "producer", "composer", "instrument"
+	Name string
+	// Attributes qualify the role, e.g. the instrument played
+	Attributes []string
+}
+
+// roleOrder lists credit roles from songwriting to performance; other roles
+// come after these
+var roleOrder = []string{
+	"writer", "composer", "lyricist", "arranger",
+	"producer", "programming", "recording", "engineer", "audio", "mix", "mastering",
+	"vocal", "instrument", "performer", "conductor", "orchestrator",
+}
+
+// roleLabels are the display names of roles whose MusicBrainz name isn't
+// clear on its own
+var roleLabels = map[string]string{
+	"writer":      "Songwriter",
+	"recording":   "Recording engineer",
+	"audio":       "Audio engineer",
+	"mix":         "Mixing",
+	"programming": "Programming",
+}
+
+// Label returns a readable role, using the instrument or vocal type when
+// MusicBrainz gives one ("Guitar", "Lead vocals")
+func (c Credit) Label() string {
+	if (c.Role == "instrument" || c.Role == "vocal") && len(c.Attributes) > 0 {
+		return capitalize(strings.Join(c.Attributes, ", "))
+	}
+	if c.Role == "vocal" {
+		return "Vocals"
+	}
+	if label, ok := roleLabels[c.Role]; ok {
+		return label
+	}
+	return capitalize(c.Role)
+}
+
+// rank orders a role by roleOrder
+func rank(role string) int {
+	for i, r := range roleOrder {
+		if r == role {
+			return i
+		}
+	}
+	return len(roleOrder)
+}
+
+// Client queries a MusicBrainz server
+type Client struct {
+	BaseURL string
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewClient creates a client for the public MusicBrainz service
+func NewClient() *Client {
+	return &Client{BaseURL: DefaultBaseURL}
+}
+
+// relation is an artist or work relationship of a recording or work
+type relation struct {
+	Type       string   `json:"type"`
+	TargetType string   `json:"target-type"`
+	Attributes []string `json:"attributes"`
+	Artist     *struct {
+		Name string `json:"name"`
+	} `json:"artist"`
+	Work *struct {
+		Relations []relation `json:"relations"`
+	} `json:"work"`
+}
+
+// FindRecording returns the MusicBrainz ID of a recording, by ISRC when
+// one is known and by title and artist otherwise
+func (c *Client) FindRecording(ctx context.Context, isrc, title, artist string) (string, error) {
+	type recordings struct {
+		Recordings []struct {
+			ID string `json:"id"`
+		} `json:"recordings"`
+	}
+
+	if isrc != "" {
+		var byISRC recordings
+		err := c.get(ctx, "/isrc/"+url.PathEscape(isrc), nil, &byISRC)
+		if err == nil && len(byISRC.Recordings) > 0 {
+			return byISRC.Recordings[0].ID, nil
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+	}
+
+	query := fmt.Sprintf("recording:%s", quote(title))
+	if artist != "" {
+		query += fmt.Sprintf(" AND artist:%s", quote(artist))
+	}
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("limit", "1")
+
+	var found recordings
+	if err := c.get(ctx, "/recording", params, &found); err != nil {
+		return "", err
+	}
+	if len(found.Recordings) == 0 {
+		return "", ErrNotFound
+	}
+	return found.Recordings[0].ID, nil
+}
+
+// Credits returns the people credited on a recording, including the
+// writers of the work it's a performance of
+func (c *Client) Credits(ctx context.Context, recordingID string) ([]Credit, error) {
+	params := url.Values{}
+	params.Set("inc", "artist-rels work-rels work-level-rels")
+
+	var recording struct {
+		Relations []relation `json:"relations"`
+	}
+	if err := c.get(ctx, "/recording/"+url.PathEscape(recordingID), params, &recording); err != nil {
+		return nil, err
+	}
+
+	var credits []Credit
+	for _, rel := range recording.Relations {
+		switch {
+		case rel.TargetType == "artist" && rel.Artist != nil:
+			credits = append(credits, Credit{Role: rel.Type, Name: rel.Artist.Name, Attributes: rel.Attributes})
+		case rel.TargetType == "work" && rel.Work != nil:
+			for _, workRel := range rel.Work.Relations {
+				if workRel.TargetType == "artist" && workRel.Artist != nil {
+					credits = append(credits, Credit{Role: workRel.Type, Name: workRel.Artist.Name, Attributes: workRel.Attributes})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(credits, func(i, j int) bool {
+		return rank(credits[i].Role) < rank(credits[j].Role)
+	})
+	return credits, nil
+}
+
+// get fetches a JSON resource, pausing between requests for the rate limit
+func (c *Client) get(ctx context.Context, path string, params url.Values, v interface{}) error {
+	c.mu.Lock()
+	if wait := requestGap - time.Since(c.last); wait > 0 {
+		select {
+		case <-ctx.Done():
+			c.mu.Unlock()
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	c.last = time.Now()
+	c.mu.Unlock()
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("fmt", "json")
+	reqURL := strings.TrimRight(c.BaseURL, "/") + path + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	// MusicBrainz asks clients to identify themselves
+	req.Header.Set("User-Agent", "mufetch (https://github.com/ashish0kumar/mufetch)")
+
+	resp, err := httpclient.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		return fmt.Errorf("MusicBrainz request failed: %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// quote wraps a search term in quotes, escaping Lucene's special characters
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
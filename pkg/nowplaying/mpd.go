@@ -0,0 +1,143 @@
+package nowplaying
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MPD reads the current song from a Music Player Daemon over its text
+// protocol
+type MPD struct {
+	Addr     string // host:port, or the path of a unix socket
+	Password string
+}
+
+// NewMPD creates an MPD source. An empty addr uses $MPD_HOST and $MPD_PORT
+// (including MPD_HOST's "password@host" form) or localhost:6600.
+func NewMPD(addr string) *MPD {
+	m := &MPD{Addr: addr}
+	if m.Addr != "" {
+		return m
+	}
+
+	host, port := os.Getenv("MPD_HOST"), os.Getenv("MPD_PORT")
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		m.Password, host = host[:i], host[i+1:]
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" {
+		port = "6600"
+	}
+	if strings.HasPrefix(host, "/") {
+		m.Addr = host
+	} else {
+		m.Addr = net.JoinHostPort(host, port)
+	}
+	return m
+}
+
+// Name returns "mpd"
+func (m *MPD) Name() string {
+	return "mpd"
+}
+
+// Current returns the song MPD is playing or paused on
+func (m *MPD) Current(ctx context.Context) (*Playing, error) {
+	network := "tcp"
+	if strings.HasPrefix(m.Addr, "/") {
+		network = "unix"
+	}
+	conn, err := (&net.Dialer{Timeout: 2 * time.Second}).DialContext(ctx, network, m.Addr)
+	if err != nil {
+		return nil, ErrUnavailable
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	r := bufio.NewReader(conn)
+	if greeting, err := r.ReadString('\n'); err != nil || !strings.HasPrefix(greeting, "OK MPD") {
+		return nil, ErrUnavailable
+	}
+
+	if m.Password != "" {
+		if _, err := mpdCommand(conn, r, "password "+strconv.Quote(m.Password)); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := mpdCommand(conn, r, "status")
+	if err != nil {
+		return nil, err
+	}
+	if status["state"] != "play" && status["state"] != "pause" {
+		return nil, nil
+	}
+
+	song, err := mpdCommand(conn, r, "currentsong")
+	if err != nil {
+		return nil, err
+	}
+
+	playing := &Playing{
+		Title:    song["Title"],
+		Album:    song["Album"],
+		Paused:   status["state"] == "pause",
+		Duration: mpdSeconds(status["duration"]),
+		Position: mpdSeconds(status["elapsed"]),
+		URL:      song["file"],
+	}
+	if artist := song["Artist"]; artist != "" {
+		playing.Artists = []string{artist}
+	}
+	if playing.Title == "" {
+		playing.Title = song["Name"] // Radio streams
+	}
+	return playing, nil
+}
+
+// mpdCommand sends cmd and reads the "key: value" response up to OK,
+// keeping the first value of repeated keys
+func mpdCommand(conn net.Conn, r *bufio.Reader, cmd string) (map[string]string, error) {
+	if _, err := fmt.Fprintf(conn, "%s\n", cmd); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "OK":
+			return values, nil
+		case strings.HasPrefix(line, "ACK "):
+			return nil, fmt.Errorf("mpd: %s", strings.TrimPrefix(line, "ACK "))
+		}
+
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			if _, seen := values[key]; !seen {
+				values[key] = value
+			}
+		}
+	}
+}
+
+// mpdSeconds parses MPD's fractional seconds
+func mpdSeconds(s string) time.Duration {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,125 @@
+package nowplaying
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	mprisPrefix = "org.mpris.MediaPlayer2."
+	mprisPath   = "/org/mpris/MediaPlayer2"
+	mprisPlayer = "org.mpris.MediaPlayer2.Player"
+)
+
+// MPRIS reads the current song from desktop players (Spotify, VLC, mpv with
+// mpv-mpris, browsers, ...) over the D-Bus session bus
+type MPRIS struct {
+	Player string // Bus name suffix, e.g. "spotify"; empty picks one
+}
+
+// NewMPRIS creates an MPRIS source, preferring the named player
+func NewMPRIS(player string) *MPRIS {
+	return &MPRIS{Player: player}
+}
+
+// Name returns "mpris"
+func (m *MPRIS) Name() string {
+	return "mpris"
+}
+
+// Current returns the song of the named player, or of the first player
+// that is playing (falling back to one that is paused)
+func (m *MPRIS) Current(ctx context.Context) (*Playing, error) {
+	conn, err := dbus.ConnectSessionBus(dbus.WithContext(ctx))
+	if err != nil {
+		return nil, ErrUnavailable
+	}
+	defer conn.Close()
+
+	var names []string
+	if err := conn.BusObject().CallWithContext(ctx, "org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		return nil, err
+	}
+
+	var paused *Playing
+	for _, name := range names {
+		if !strings.HasPrefix(name, mprisPrefix) {
+			continue
+		}
+		if m.Player != "" && !strings.HasPrefix(strings.TrimPrefix(name, mprisPrefix), m.Player) {
+			continue
+		}
+
+		playing, err := mprisCurrent(conn.Object(name, mprisPath))
+		if err != nil || playing == nil {
+			continue
+		}
+		if !playing.Paused {
+			return playing, nil
+		}
+		if paused == nil {
+			paused = playing
+		}
+	}
+	return paused, nil
+}
+
+// mprisCurrent reads the song from one player
+func mprisCurrent(obj dbus.BusObject) (*Playing, error) {
+	status, err := obj.GetProperty(mprisPlayer + ".PlaybackStatus")
+	if err != nil {
+		return nil, err
+	}
+	state, _ := status.Value().(string)
+	if state != "Playing" && state != "Paused" {
+		return nil, nil
+	}
+
+	variant, err := obj.GetProperty(mprisPlayer + ".Metadata")
+	if err != nil {
+		return nil, err
+	}
+	meta, _ := variant.Value().(map[string]dbus.Variant)
+
+	playing := &Playing{
+		Title:    mprisString(meta["xesam:title"]),
+		Album:    mprisString(meta["xesam:album"]),
+		URL:      mprisString(meta["xesam:url"]),
+		Duration: mprisMicros(meta["mpris:length"]),
+		Paused:   state == "Paused",
+	}
+	if artists, ok := meta["xesam:artist"].Value().([]string); ok {
+		playing.Artists = artists
+	}
+	if art := mprisString(meta["mpris:artUrl"]); strings.HasPrefix(art, "http") {
+		playing.ArtURL = art
+	}
+	if position, err := obj.GetProperty(mprisPlayer + ".Position"); err == nil {
+		playing.Position = mprisMicros(position)
+	}
+	if playing.Title == "" {
+		return nil, nil
+	}
+	return playing, nil
+}
+
+// mprisString returns a string metadata value, or "" for other types
+func mprisString(v dbus.Variant) string {
+	s, _ := v.Value().(string)
+	return s
+}
+
+// mprisMicros converts a microsecond count, which players send as either
+// int64 or uint64
+func mprisMicros(v dbus.Variant) time.Duration {
+	switch n := v.Value().(type) {
+	case int64:
+		return time.Duration(n) * time.Microsecond
+	case uint64:
+		return time.Duration(n) * time.Microsecond
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+// Package nowplaying reads the currently playing song from a local player
+// (MPRIS on Linux, MPD) or the user's Spotify account.
+package nowplaying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// SourceNames lists the accepted backends; auto tries each in turn
+var SourceNames = []string{"auto", "spotify", "mpris", "mpd"}
+
+// ErrUnavailable is returned when a backend can't be reached at all, as
+// opposed to being reachable with nothing playing
+var ErrUnavailable = errors.New("player not available")
+
+// Playing describes the current song. Local players only know the tags, so
+// Track and Episode are only set by the Spotify backend.
+type Playing struct {
+	Title    string
+	Artists  []string
+	Album    string
+	Duration time.Duration
+	Position time.Duration
+	Paused   bool
+	ArtURL   string // Cover art, if the player exposes an http(s) URL
+	URL      string // Page or stream location of the song
+
+	Track   *spotify.Track
+	Episode *spotify.Episode
+}
+
+// Key identifies the song, so watchers can tell when it changes
+func (p *Playing) Key() string {
+	switch {
+	case p.Track != nil:
+		return "spotify:track:" + p.Track.ID
+	case p.Episode != nil:
+		return "spotify:episode:" + p.Episode.ID
+	}
+	return strings.Join(p.Artists, ", ") + "\x00" + p.Title + "\x00" + p.Album
+}
+
+// Query returns a search query for looking the song up by its tags
+func (p *Playing) Query() string {
+	if len(p.Artists) == 0 {
+		return p.Title
+	}
+	return p.Artists[0] + " " + p.Title
+}
+
+// LocalFile returns the path of the file being played, or "" when the
+// player is streaming or only gave a path relative to its library
+func (p *Playing) LocalFile() string {
+	if strings.HasPrefix(p.URL, "file://") {
+		u, err := url.Parse(p.URL)
+		if err != nil {
+			return ""
+		}
+		path := u.Path
+		if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			path = path[1:] // file:///C:/Music/...
+		}
+		return filepath.FromSlash(path)
+	}
+	if filepath.IsAbs(p.URL) {
+		return p.URL
+	}
+	return ""
+}
+
+// AsTrack builds a track from the player's tags, for when the song can't be
+// found on a metadata provider
+func (p *Playing) AsTrack() spotify.Track {
+	track := spotify.Track{
+		Name:     p.Title,
+		Duration: int(p.Duration / time.Millisecond),
+		Album:    spotify.Album{Name: p.Album},
+	}
+	if strings.HasPrefix(p.URL, "http") {
+		track.ExternalURL.Web = p.URL
+	}
+	for _, name := range p.Artists {
+		track.Artists = append(track.Artists, spotify.Artist{Name: name})
+	}
+	if p.ArtURL != "" {
+		track.Album.Images = []spotify.Image{{URL: p.ArtURL}}
+	}
+	return track
+}
+
+// Source reads the current song from one backend
+type Source interface {
+	// Name returns the backend name, e.g. "mpd"
+	Name() string
+
+	// Current returns the playing (or paused) song, or nil when the player
+	// is stopped
+	Current(ctx context.Context) (*Playing, error)
+}
+
+// Auto tries each source in order, skipping those that are unavailable
+type Auto struct {
+	sources []Source
+}
+
+// NewAuto creates a source that asks each of sources in turn
+func NewAuto(sources ...Source) *Auto {
+	return &Auto{sources: sources}
+}
+
+// Name returns "auto"
+func (a *Auto) Name() string {
+	return "auto"
+}
+
+// Current returns the song from the first source that has one
+func (a *Auto) Current(ctx context.Context) (*Playing, error) {
+	var reached bool
+	for _, s := range a.sources {
+		playing, err := s.Current(ctx)
+		if errors.Is(err, ErrUnavailable) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.Name(), err)
+		}
+		if playing != nil {
+			return playing, nil
+		}
+		reached = true
+	}
+	if !reached {
+		return nil, ErrUnavailable
+	}
+	return nil, nil
+}
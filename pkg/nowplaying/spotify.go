@@ -0,0 +1,60 @@
+package nowplaying
+
+import (
+	"context"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// Spotify reads the user's current playback from the Spotify Web API
+type Spotify struct {
+	Client *spotify.Client // Must hold a user access token
+}
+
+// NewSpotify creates a Spotify source from a client with a user token
+func NewSpotify(client *spotify.Client) *Spotify {
+	return &Spotify{Client: client}
+}
+
+// Name returns "spotify"
+func (s *Spotify) Name() string {
+	return "spotify"
+}
+
+// Current returns the track or episode playing on any of the user's devices
+func (s *Spotify) Current(ctx context.Context) (*Playing, error) {
+	if s.Client == nil {
+		return nil, ErrUnavailable
+	}
+
+	cp, err := s.Client.GetCurrentlyPlaying(ctx)
+	if err != nil || cp == nil {
+		return nil, err
+	}
+
+	playing := &Playing{
+		Position: time.Duration(cp.Progress) * time.Millisecond,
+		Paused:   !cp.IsPlaying,
+		Track:    cp.Track,
+		Episode:  cp.Episode,
+	}
+	switch {
+	case cp.Track != nil:
+		playing.Title = cp.Track.Name
+		playing.Album = cp.Track.Album.Name
+		playing.Duration = time.Duration(cp.Track.Duration) * time.Millisecond
+		playing.URL = cp.Track.ExternalURL.URL()
+		for _, a := range cp.Track.Artists {
+			playing.Artists = append(playing.Artists, a.Name)
+		}
+	case cp.Episode != nil:
+		playing.Title = cp.Episode.Name
+		playing.Album = cp.Episode.Show.Name
+		playing.Duration = time.Duration(cp.Episode.Duration) * time.Millisecond
+		playing.URL = cp.Episode.ExternalURL.URL()
+	default:
+		return nil, nil // Ads and unknown item types
+	}
+	return playing, nil
+}
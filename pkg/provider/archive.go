@@ -0,0 +1,286 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// archiveBaseURL is the Internet Archive root for search, metadata and images
+const archiveBaseURL = "https://archive.org"
+
+// Archive looks up live concert recordings in the Internet Archive's Live
+// Music Archive (etree) collection
+type Archive struct{}
+
+// NewArchive creates an Internet Archive provider; no credentials are needed
+func NewArchive() *Archive {
+	return &Archive{}
+}
+
+// archiveSearchResponse is the advancedsearch.php result envelope
+type archiveSearchResponse struct {
+	Response struct {
+		Docs []struct {
+			Identifier string `json:"identifier"`
+		} `json:"docs"`
+	} `json:"response"`
+}
+
+// archiveMetadata is the /metadata/{identifier} response
+type archiveMetadata struct {
+	Metadata struct {
+		Identifier string        `json:"identifier"`
+		Title      archiveString `json:"title"`
+		Creator    archiveString `json:"creator"`
+		Date       archiveString `json:"date"`
+		Venue      archiveString `json:"venue"`
+		Coverage   archiveString `json:"coverage"`
+		Source     archiveString `json:"source"`
+		Lineage    archiveString `json:"lineage"`
+		Taper      archiveString `json:"taper"`
+		Transferer archiveString `json:"transferer"`
+		LicenseURL archiveString `json:"licenseurl"`
+		Subject    archiveString `json:"subject"`
+	} `json:"metadata"`
+	Files []archiveFile `json:"files"`
+}
+
+// archiveFile is a single file within an item
+type archiveFile struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Title  string `json:"title"`
+	Track  string `json:"track"`
+	Length string `json:"length"` // Seconds or MM:SS
+	Source string `json:"source"` // "original" or "derivative"
+}
+
+// archiveString decodes metadata values that may be a string or a list
+type archiveString string
+
+// UnmarshalJSON joins list values with "; "
+func (s *archiveString) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = archiveString(single)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = archiveString(strings.Join(list, "; "))
+	return nil
+}
+
+// Name returns the provider display name
+func (a *Archive) Name() string {
+	return "Internet Archive"
+}
+
+// SearchTrack finds a recording for the query and returns the track whose
+// title best matches it
+func (a *Archive) SearchTrack(ctx context.Context, query string) (*spotify.Track, error) {
+	album, err := a.SearchAlbum(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(album.Tracks.Items) == 0 {
+		return nil, ErrNotFound
+	}
+
+	track := album.Tracks.Items[0]
+	lower := strings.ToLower(query)
+	for _, t := range album.Tracks.Items {
+		if t.Name != "" && strings.Contains(lower, strings.ToLower(t.Name)) {
+			track = t
+			break
+		}
+	}
+
+	album.Tracks = spotify.TracksPage{}
+	track.Album = *album
+	return &track, nil
+}
+
+// SearchAlbum returns the best matching concert recording with its setlist
+// and taper/source details
+func (a *Archive) SearchAlbum(ctx context.Context, query string) (*spotify.Album, error) {
+	id, err := a.search(ctx, fmt.Sprintf("(%s) AND collection:etree AND mediatype:etree", query))
+	if err != nil {
+		return nil, err
+	}
+
+	var meta archiveMetadata
+	if err := getJSON(ctx, archiveBaseURL+"/metadata/"+url.PathEscape(id), &meta); err != nil {
+		return nil, err
+	}
+
+	m := meta.Metadata
+	album := &spotify.Album{
+		ID:          id,
+		Name:        string(m.Title),
+		Artists:     []spotify.Artist{archiveArtistRef(string(m.Creator))},
+		Images:      []spotify.Image{{URL: archiveBaseURL + "/services/img/" + url.PathEscape(id)}},
+		ReleaseDate: string(m.Date),
+		AlbumType:   "live recording",
+		Genres:      archiveSubjects(string(m.Subject)),
+		ExternalURL: spotify.ExternalURL{Web: archiveBaseURL + "/details/" + url.PathEscape(id)},
+		License:     licenseFromURL(string(m.LicenseURL)),
+		Recording: &spotify.Recording{
+			Venue:      string(m.Venue),
+			Location:   string(m.Coverage),
+			Source:     string(m.Source),
+			Lineage:    string(m.Lineage),
+			Taper:      string(m.Taper),
+			Transferer: string(m.Transferer),
+		},
+	}
+
+	for _, f := range archiveAudioFiles(meta.Files) {
+		number, _ := strconv.Atoi(strings.SplitN(f.Track, "/", 2)[0])
+		name := f.Title
+		if name == "" {
+			name = f.Name
+		}
+
+		album.Tracks.Items = append(album.Tracks.Items, spotify.Track{
+			ID:          id + "/" + f.Name,
+			Name:        name,
+			Artists:     album.Artists,
+			Duration:    archiveLength(f.Length),
+			TrackNumber: number,
+			ExternalURL: spotify.ExternalURL{Web: archiveBaseURL + "/details/" + url.PathEscape(id) + "/" + url.PathEscape(f.Name)},
+			Quality:     archiveQuality(f.Format),
+			License:     album.License,
+		})
+	}
+	album.TotalTracks = len(album.Tracks.Items)
+	album.Tracks.Total = album.TotalTracks
+
+	return album, nil
+}
+
+// SearchArtist returns the matching band collection within etree
+func (a *Archive) SearchArtist(ctx context.Context, query string) (*spotify.Artist, error) {
+	id, err := a.search(ctx, fmt.Sprintf("(%s) AND collection:etree AND mediatype:collection", query))
+	if err != nil {
+		return nil, err
+	}
+
+	var meta archiveMetadata
+	if err := getJSON(ctx, archiveBaseURL+"/metadata/"+url.PathEscape(id), &meta); err != nil {
+		return nil, err
+	}
+
+	artist := archiveArtistRef(string(meta.Metadata.Title))
+	artist.ID = id
+	artist.Images = []spotify.Image{{URL: archiveBaseURL + "/services/img/" + url.PathEscape(id)}}
+	artist.ExternalURL.Web = archiveBaseURL + "/details/" + url.PathEscape(id)
+	return &artist, nil
+}
+
+// search returns the identifier of the most downloaded item matching q
+func (a *Archive) search(ctx context.Context, q string) (string, error) {
+	params := url.Values{}
+	params.Set("q", q)
+	params.Add("fl[]", "identifier")
+	params.Add("sort[]", "downloads desc")
+	params.Set("rows", "1")
+	params.Set("output", "json")
+
+	var resp archiveSearchResponse
+	if err := getJSON(ctx, archiveBaseURL+"/advancedsearch.php?"+params.Encode(), &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Response.Docs) == 0 {
+		return "", ErrNotFound
+	}
+	return resp.Response.Docs[0].Identifier, nil
+}
+
+// archiveAudioFormats ranks audio formats, lossless originals first
+var archiveAudioFormats = []string{"24bit Flac", "Flac", "Shorten", "VBR MP3", "Ogg Vorbis"}
+
+// archiveAudioFiles returns the tracks in the best available format, in
+// track order
+func archiveAudioFiles(files []archiveFile) []archiveFile {
+	for _, format := range archiveAudioFormats {
+		var matched []archiveFile
+		for _, f := range files {
+			if f.Format == format {
+				matched = append(matched, f)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		sort.SliceStable(matched, func(i, j int) bool {
+			ti, _ := strconv.Atoi(strings.SplitN(matched[i].Track, "/", 2)[0])
+			tj, _ := strconv.Atoi(strings.SplitN(matched[j].Track, "/", 2)[0])
+			if ti != tj {
+				return ti < tj
+			}
+			return matched[i].Name < matched[j].Name
+		})
+		return matched
+	}
+	return nil
+}
+
+// archiveQuality maps an archive.org file format to audio quality details
+func archiveQuality(format string) *spotify.AudioQuality {
+	switch format {
+	case "24bit Flac":
+		return &spotify.AudioQuality{Codec: "flac", BitDepth: 24}
+	case "Flac":
+		return &spotify.AudioQuality{Codec: "flac"}
+	case "Shorten":
+		return &spotify.AudioQuality{Codec: "shn"}
+	case "VBR MP3":
+		return &spotify.AudioQuality{Codec: "mp3"}
+	case "Ogg Vorbis":
+		return &spotify.AudioQuality{Codec: "ogg"}
+	}
+	return nil
+}
+
+// archiveLength parses a file length given in seconds or as MM:SS
+func archiveLength(length string) int {
+	if strings.Contains(length, ":") {
+		return parseClockDuration(length)
+	}
+	seconds, err := strconv.ParseFloat(length, 64)
+	if err != nil {
+		return 0
+	}
+	return int(seconds * 1000)
+}
+
+// archiveSubjects splits the subject keywords into genre-like tags
+func archiveSubjects(subject string) []string {
+	var tags []string
+	for _, s := range strings.Split(subject, ";") {
+		if s = strings.TrimSpace(s); s != "" && !strings.EqualFold(s, "live concert") {
+			tags = append(tags, s)
+		}
+	}
+	return tags
+}
+
+// archiveArtistRef builds a minimal artist linking to a creator search
+func archiveArtistRef(name string) spotify.Artist {
+	return spotify.Artist{
+		Name:        name,
+		ExternalURL: spotify.ExternalURL{Web: archiveBaseURL + "/search?query=" + url.QueryEscape(`creator:"`+name+`"`)},
+	}
+}
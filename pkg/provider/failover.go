@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// Failover tries providers in priority order, moving on to the next one
+// when a provider errors or is rate limited. A provider that simply has no
+// match ends the search without failing over.
+type Failover struct {
+	providers  []Provider
+	onFailover func(from, to Provider, err error)
+
+	mu   sync.Mutex
+	last Provider
+}
+
+// NewFailover creates a failover chain; onFailover, if non-nil, is called
+// each time a provider is skipped so callers can print a notice
+func NewFailover(providers []Provider, onFailover func(from, to Provider, err error)) *Failover {
+	return &Failover{providers: providers, onFailover: onFailover}
+}
+
+// Name returns the name of the provider that served the last result
+func (f *Failover) Name() string {
+	return f.Last().Name()
+}
+
+// Last returns the provider that served the last result, or the primary
+// provider before any lookup
+func (f *Failover) Last() Provider {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.last != nil {
+		return f.last
+	}
+	return f.providers[0]
+}
+
+// SearchTrack returns the top track match from the first working provider
+func (f *Failover) SearchTrack(ctx context.Context, query string) (*spotify.Track, error) {
+	return try(f, func(p Provider) (*spotify.Track, error) { return p.SearchTrack(ctx, query) })
+}
+
+// SearchAlbum returns the top album match from the first working provider
+func (f *Failover) SearchAlbum(ctx context.Context, query string) (*spotify.Album, error) {
+	return try(f, func(p Provider) (*spotify.Album, error) { return p.SearchAlbum(ctx, query) })
+}
+
+// SearchArtist returns the top artist match from the first working provider
+func (f *Failover) SearchArtist(ctx context.Context, query string) (*spotify.Artist, error) {
+	return try(f, func(p Provider) (*spotify.Artist, error) { return p.SearchArtist(ctx, query) })
+}
+
+// ListTracks returns track matches from the first working provider
+func (f *Failover) ListTracks(ctx context.Context, query string, limit int) ([]spotify.Track, error) {
+	return tryList(f, func(p Provider) ([]spotify.Track, error) { return ListTracks(ctx, p, query, limit) })
+}
+
+// ListAlbums returns album matches from the first working provider
+func (f *Failover) ListAlbums(ctx context.Context, query string, limit int) ([]spotify.Album, error) {
+	return tryList(f, func(p Provider) ([]spotify.Album, error) { return ListAlbums(ctx, p, query, limit) })
+}
+
+// ListArtists returns artist matches from the first working provider
+func (f *Failover) ListArtists(ctx context.Context, query string, limit int) ([]spotify.Artist, error) {
+	return tryList(f, func(p Provider) ([]spotify.Artist, error) { return ListArtists(ctx, p, query, limit) })
+}
+
+// tryList is try for lookups returning several results
+func tryList[T any](f *Failover, lookup func(Provider) ([]T, error)) ([]T, error) {
+	result, err := try(f, func(p Provider) (*[]T, error) {
+		items, err := lookup(p)
+		return &items, err
+	})
+	if result == nil {
+		return nil, err
+	}
+	return *result, err
+}
+
+// try runs lookup against each provider until one succeeds or reports that
+// nothing matched, returning the last error if they all fail
+func try[T any](f *Failover, lookup func(Provider) (*T, error)) (*T, error) {
+	var lastErr error
+	for i, p := range f.providers {
+		result, err := lookup(p)
+		if errors.Is(err, context.Canceled) {
+			return nil, err // Interrupted, not a provider failure
+		}
+		if err == nil || errors.Is(err, ErrNotFound) {
+			f.mu.Lock()
+			f.last = p
+			f.mu.Unlock()
+			return result, err
+		}
+
+		lastErr = err
+		if i+1 < len(f.providers) && f.onFailover != nil {
+			f.onFailover(p, f.providers[i+1], err)
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// FMA looks up Creative Commons music on the Free Music Archive
+type FMA struct {
+	APIKey  string
+	BaseURL string
+}
+
+// NewFMA creates a Free Music Archive provider. FMA retired its public API,
+// so baseURL must point at a mirror of the legacy one.
+func NewFMA(apiKey, baseURL string) *FMA {
+	return &FMA{APIKey: apiKey, BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// fmaResponse is the envelope of the legacy FMA dataset API
+type fmaResponse[T any] struct {
+	Message string `json:"message"`
+	Dataset []T    `json:"dataset"`
+}
+
+// fmaTrack mirrors the fields we use from the tracks dataset
+type fmaTrack struct {
+	ID           string `json:"track_id"`
+	Title        string `json:"track_title"`
+	URL          string `json:"track_url"`
+	ImageFile    string `json:"track_image_file"`
+	Duration     string `json:"track_duration"` // MM:SS or HH:MM:SS
+	Number       string `json:"track_number"`
+	Explicit     string `json:"track_explicit"`
+	LicenseTitle string `json:"license_title"`
+	LicenseURL   string `json:"license_url"`
+	ArtistID     string `json:"artist_id"`
+	ArtistName   string `json:"artist_name"`
+	ArtistURL    string `json:"artist_url"`
+	AlbumID      string `json:"album_id"`
+	AlbumTitle   string `json:"album_title"`
+	AlbumURL     string `json:"album_url"`
+	Genres       []struct {
+		Title string `json:"genre_title"`
+	} `json:"track_genres"`
+}
+
+// fmaAlbum mirrors the fields we use from the albums dataset
+type fmaAlbum struct {
+	ID           string `json:"album_id"`
+	Title        string `json:"album_title"`
+	URL          string `json:"album_url"`
+	ImageFile    string `json:"album_image_file"`
+	DateReleased string `json:"album_date_released"` // MM/DD/YYYY
+	Tracks       string `json:"album_tracks"`
+	Type         string `json:"album_type"`
+	ArtistID     string `json:"artist_id"`
+	ArtistName   string `json:"artist_name"`
+	ArtistURL    string `json:"artist_url"`
+}
+
+// fmaArtist mirrors the fields we use from the artists dataset
+type fmaArtist struct {
+	ID        string `json:"artist_id"`
+	Name      string `json:"artist_name"`
+	URL       string `json:"artist_url"`
+	ImageFile string `json:"artist_image_file"`
+}
+
+// Name returns the provider display name
+func (f *FMA) Name() string {
+	return "FMA"
+}
+
+// SearchTrack returns the top track match with its license and genres
+func (f *FMA) SearchTrack(ctx context.Context, query string) (*spotify.Track, error) {
+	var resp fmaResponse[fmaTrack]
+	if err := f.get(ctx, "tracks", query, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Dataset) == 0 {
+		return nil, ErrNotFound
+	}
+
+	t := resp.Dataset[0]
+	number, _ := strconv.Atoi(t.Number)
+
+	track := &spotify.Track{
+		ID:          t.ID,
+		Name:        t.Title,
+		Artists:     []spotify.Artist{fmaArtistRef(t.ArtistID, t.ArtistName, t.ArtistURL)},
+		Duration:    parseClockDuration(t.Duration),
+		TrackNumber: number,
+		Explicit:    strings.EqualFold(t.Explicit, "explicit"),
+		ExternalURL: spotify.ExternalURL{Web: t.URL},
+		Album: spotify.Album{
+			ID:          t.AlbumID,
+			Name:        t.AlbumTitle,
+			Images:      fmaImages(t.ImageFile),
+			ExternalURL: spotify.ExternalURL{Web: t.AlbumURL},
+		},
+	}
+
+	if t.LicenseURL != "" || t.LicenseTitle != "" {
+		track.License = &spotify.License{Name: t.LicenseTitle, URL: t.LicenseURL}
+	}
+	for _, g := range t.Genres {
+		track.Album.Genres = append(track.Album.Genres, g.Title)
+	}
+
+	return track, nil
+}
+
+// SearchAlbum returns the top album match
+func (f *FMA) SearchAlbum(ctx context.Context, query string) (*spotify.Album, error) {
+	var resp fmaResponse[fmaAlbum]
+	if err := f.get(ctx, "albums", query, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Dataset) == 0 {
+		return nil, ErrNotFound
+	}
+
+	a := resp.Dataset[0]
+	total, _ := strconv.Atoi(a.Tracks)
+
+	album := &spotify.Album{
+		ID:          a.ID,
+		Name:        a.Title,
+		Artists:     []spotify.Artist{fmaArtistRef(a.ArtistID, a.ArtistName, a.ArtistURL)},
+		Images:      fmaImages(a.ImageFile),
+		AlbumType:   strings.ToLower(formatFallback(a.Type, "album")),
+		TotalTracks: total,
+		ExternalURL: spotify.ExternalURL{Web: a.URL},
+	}
+
+	if released, err := time.Parse("01/02/2006", a.DateReleased); err == nil {
+		album.ReleaseDate = released.Format("2006-01-02")
+	}
+
+	return album, nil
+}
+
+// SearchArtist returns the top artist match
+func (f *FMA) SearchArtist(ctx context.Context, query string) (*spotify.Artist, error) {
+	var resp fmaResponse[fmaArtist]
+	if err := f.get(ctx, "artists", query, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Dataset) == 0 {
+		return nil, ErrNotFound
+	}
+
+	a := resp.Dataset[0]
+	artist := fmaArtistRef(a.ID, a.Name, a.URL)
+	artist.Images = fmaImages(a.ImageFile)
+	return &artist, nil
+}
+
+// get queries a dataset endpoint of the legacy API
+func (f *FMA) get(ctx context.Context, dataset, query string, v interface{ message() string }) error {
+	params := url.Values{}
+	params.Set("api_key", f.APIKey)
+	params.Set("q", query)
+	params.Set("limit", "1")
+
+	reqURL := fmt.Sprintf("%s/get/%s.json?%s", f.BaseURL, dataset, params.Encode())
+	if err := getJSON(ctx, reqURL, v); err != nil {
+		return err
+	}
+
+	if msg := v.message(); msg != "" {
+		return fmt.Errorf("fma: %s", msg)
+	}
+	return nil
+}
+
+// message returns the API-level error message, if any
+func (r *fmaResponse[T]) message() string {
+	return r.Message
+}
+
+// fmaArtistRef builds a minimal artist linking to its FMA page
+func fmaArtistRef(id, name, pageURL string) spotify.Artist {
+	return spotify.Artist{
+		ID:          id,
+		Name:        name,
+		ExternalURL: spotify.ExternalURL{Web: pageURL},
+	}
+}
+
+// fmaImages wraps an image URL when present
+func fmaImages(imageURL string) []spotify.Image {
+	if imageURL == "" {
+		return nil
+	}
+	return []spotify.Image{{URL: imageURL}}
+}
+
+// parseClockDuration converts "MM:SS" or "HH:MM:SS" into milliseconds
+func parseClockDuration(s string) int {
+	total := 0
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return total * 1000
+}
+
+// formatFallback returns s, or fallback when s is empty
+func formatFallback(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
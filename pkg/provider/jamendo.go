@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// jamendoBaseURL is the Jamendo v3 API root
+const jamendoBaseURL = "https://api.jamendo.com/v3.0"
+
+// Jamendo looks up Creative Commons music on jamendo.com
+type Jamendo struct {
+	ClientID string
+}
+
+// NewJamendo creates a Jamendo provider with an API client ID
+func NewJamendo(clientID string) *Jamendo {
+	return &Jamendo{ClientID: clientID}
+}
+
+// jamendoResponse is the envelope shared by all Jamendo endpoints
+type jamendoResponse[T any] struct {
+	Headers struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"headers"`
+	Results []T `json:"results"`
+}
+
+// jamendoTrack mirrors the fields we use from /tracks and /albums/tracks
+type jamendoTrack struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Duration     int    `json:"duration"` // Seconds
+	Position     int    `json:"position"`
+	ArtistID     string `json:"artist_id"`
+	ArtistName   string `json:"artist_name"`
+	AlbumID      string `json:"album_id"`
+	AlbumName    string `json:"album_name"`
+	AlbumImage   string `json:"album_image"`
+	ReleaseDate  string `json:"releasedate"`
+	LicenseCCURL string `json:"license_ccurl"`
+	ShareURL     string `json:"shareurl"`
+	MusicInfo    struct {
+		Tags struct {
+			Genres []string `json:"genres"`
+		} `json:"tags"`
+	} `json:"musicinfo"`
+}
+
+// jamendoAlbum mirrors the fields we use from /albums/tracks
+type jamendoAlbum struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	ReleaseDate string         `json:"releasedate"`
+	ArtistID    string         `json:"artist_id"`
+	ArtistName  string         `json:"artist_name"`
+	Image       string         `json:"image"`
+	Tracks      []jamendoTrack `json:"tracks"`
+}
+
+// jamendoArtist mirrors the fields we use from /artists
+type jamendoArtist struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Image    string `json:"image"`
+	ShareURL string `json:"shareurl"`
+}
+
+// Name returns the provider display name
+func (j *Jamendo) Name() string {
+	return "Jamendo"
+}
+
+// SearchTrack returns the top track match with its license and genres
+func (j *Jamendo) SearchTrack(ctx context.Context, query string) (*spotify.Track, error) {
+	params := url.Values{}
+	params.Set("search", query)
+	params.Set("include", "licenses musicinfo")
+
+	var resp jamendoResponse[jamendoTrack]
+	if err := j.get(ctx, "tracks", params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	t := resp.Results[0]
+	track := j.convertTrack(t)
+	track.Album = spotify.Album{
+		ID:          t.AlbumID,
+		Name:        t.AlbumName,
+		Images:      jamendoImages(t.AlbumImage),
+		ReleaseDate: t.ReleaseDate,
+		Genres:      t.MusicInfo.Tags.Genres,
+		ExternalURL: spotify.ExternalURL{Web: "https://www.jamendo.com/album/" + t.AlbumID},
+	}
+	return &track, nil
+}
+
+// SearchAlbum returns the top album match including its tracklist
+func (j *Jamendo) SearchAlbum(ctx context.Context, query string) (*spotify.Album, error) {
+	params := url.Values{}
+	params.Set("namesearch", query)
+	params.Set("track_type", "albumtrack")
+
+	var resp jamendoResponse[jamendoAlbum]
+	if err := j.get(ctx, "albums/tracks", params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	a := resp.Results[0]
+	album := &spotify.Album{
+		ID:          a.ID,
+		Name:        a.Name,
+		Artists:     []spotify.Artist{jamendoArtistRef(a.ArtistID, a.ArtistName)},
+		Images:      jamendoImages(a.Image),
+		ReleaseDate: a.ReleaseDate,
+		AlbumType:   "album",
+		TotalTracks: len(a.Tracks),
+		ExternalURL: spotify.ExternalURL{Web: "https://www.jamendo.com/album/" + a.ID},
+	}
+
+	for _, t := range a.Tracks {
+		track := j.convertTrack(t)
+		track.Artists = album.Artists
+		album.Tracks.Items = append(album.Tracks.Items, track)
+
+		// Albums are licensed per track; show it when they all agree
+		if album.License == nil {
+			album.License = track.License
+		} else if track.License != nil && track.License.URL != album.License.URL {
+			album.License = &spotify.License{Name: "Mixed"}
+		}
+	}
+	album.Tracks.Total = len(album.Tracks.Items)
+
+	return album, nil
+}
+
+// SearchArtist returns the top artist match
+func (j *Jamendo) SearchArtist(ctx context.Context, query string) (*spotify.Artist, error) {
+	params := url.Values{}
+	params.Set("namesearch", query)
+
+	var resp jamendoResponse[jamendoArtist]
+	if err := j.get(ctx, "artists", params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	a := resp.Results[0]
+	artist := jamendoArtistRef(a.ID, a.Name)
+	artist.Images = jamendoImages(a.Image)
+	if a.ShareURL != "" {
+		artist.ExternalURL.Web = a.ShareURL
+	}
+	return &artist, nil
+}
+
+// get calls a Jamendo endpoint with the client ID and common parameters
+func (j *Jamendo) get(ctx context.Context, endpoint string, params url.Values, v interface {
+	status() (string, string)
+}) error {
+	params.Set("client_id", j.ClientID)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+
+	reqURL := fmt.Sprintf("%s/%s/?%s", jamendoBaseURL, endpoint, params.Encode())
+	if err := getJSON(ctx, reqURL, v); err != nil {
+		return err
+	}
+
+	if status, message := v.status(); status != "" && status != "success" {
+		return fmt.Errorf("jamendo: %s", message)
+	}
+	return nil
+}
+
+// status reports the API-level status carried in the response headers
+func (r *jamendoResponse[T]) status() (string, string) {
+	return r.Headers.Status, r.Headers.ErrorMessage
+}
+
+// convertTrack maps a Jamendo track onto the shared track model
+func (j *Jamendo) convertTrack(t jamendoTrack) spotify.Track {
+	shareURL := t.ShareURL
+	if shareURL == "" {
+		shareURL = "https://www.jamendo.com/track/" + t.ID
+	}
+
+	return spotify.Track{
+		ID:          t.ID,
+		Name:        t.Name,
+		Artists:     []spotify.Artist{jamendoArtistRef(t.ArtistID, t.ArtistName)},
+		Duration:    t.Duration * 1000,
+		TrackNumber: t.Position,
+		ExternalURL: spotify.ExternalURL{Web: shareURL},
+		License:     licenseFromURL(t.LicenseCCURL),
+	}
+}
+
+// jamendoArtistRef builds a minimal artist linking to its Jamendo page
+func jamendoArtistRef(id, name string) spotify.Artist {
+	return spotify.Artist{
+		ID:          id,
+		Name:        name,
+		ExternalURL: spotify.ExternalURL{Web: "https://www.jamendo.com/artist/" + id},
+	}
+}
+
+// jamendoImages wraps an image URL, requesting a larger rendition when the
+// URL carries a width parameter
+func jamendoImages(imageURL string) []spotify.Image {
+	if imageURL == "" {
+		return nil
+	}
+	if strings.Contains(imageURL, "width=") {
+		if u, err := url.Parse(imageURL); err == nil {
+			q := u.Query()
+			q.Set("width", strconv.Itoa(500))
+			u.RawQuery = q.Encode()
+			imageURL = u.String()
+		}
+	}
+	return []spotify.Image{{URL: imageURL}}
+}
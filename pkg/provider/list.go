@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// Lister is implemented by providers that can return several matches for a
+// query. Listed albums and artists may omit details like tracklists.
+type Lister interface {
+	ListTracks(ctx context.Context, query string, limit int) ([]spotify.Track, error)
+	ListAlbums(ctx context.Context, query string, limit int) ([]spotify.Album, error)
+	ListArtists(ctx context.Context, query string, limit int) ([]spotify.Artist, error)
+}
+
+// ListTracks returns up to limit track matches from p, falling back to the
+// top match for providers that only support single results
+func ListTracks(ctx context.Context, p Provider, query string, limit int) ([]spotify.Track, error) {
+	if l, ok := p.(Lister); ok {
+		return l.ListTracks(ctx, query, limit)
+	}
+	return single(p.SearchTrack(ctx, query))
+}
+
+// ListAlbums returns up to limit album matches from p, falling back to the
+// top match for providers that only support single results
+func ListAlbums(ctx context.Context, p Provider, query string, limit int) ([]spotify.Album, error) {
+	if l, ok := p.(Lister); ok {
+		return l.ListAlbums(ctx, query, limit)
+	}
+	return single(p.SearchAlbum(ctx, query))
+}
+
+// ListArtists returns up to limit artist matches from p, falling back to
+// the top match for providers that only support single results
+func ListArtists(ctx context.Context, p Provider, query string, limit int) ([]spotify.Artist, error) {
+	if l, ok := p.(Lister); ok {
+		return l.ListArtists(ctx, query, limit)
+	}
+	return single(p.SearchArtist(ctx, query))
+}
+
+// single wraps a lone search result in a slice
+func single[T any](result *T, err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []T{*result}, nil
+}
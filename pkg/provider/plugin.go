@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// PluginPrefix starts the name of every provider plugin executable, e.g.
+// mufetch-provider-bandcamp for the source "bandcamp"
+const PluginPrefix = "mufetch-provider-"
+
+// PluginVersion is the version of the plugin protocol sent with requests
+const PluginVersion = 1
+
+// PluginRequest is written as JSON to a plugin's stdin, one per run
+type PluginRequest struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"` // track, album or artist
+	Query   string `json:"query"`
+	Limit   int    `json:"limit"`
+	Locale  string `json:"locale,omitempty"`
+}
+
+// PluginResponse is read as JSON from a plugin's stdout. Results use the
+// Spotify Web API's object shapes; only the list for the requested type is
+// read. Error, with Code set to not_found, unauthorized or rate_limited,
+// reports a failed lookup.
+type PluginResponse struct {
+	Source  string           `json:"source,omitempty"` // Display name, e.g. "Bandcamp"
+	Tracks  []spotify.Track  `json:"tracks,omitempty"`
+	Albums  []spotify.Album  `json:"albums,omitempty"`
+	Artists []spotify.Artist `json:"artists,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	Code    string           `json:"code,omitempty"`
+}
+
+// Plugin is a provider backed by an external executable speaking the
+// plugin protocol
+type Plugin struct {
+	Path   string
+	Locale string
+
+	name   string
+	mu     sync.Mutex
+	source string // Display name reported by the plugin
+}
+
+// NewPlugin creates a provider that runs the plugin executable at path for
+// the source called name
+func NewPlugin(name, path string) *Plugin {
+	return &Plugin{Path: path, name: name}
+}
+
+// FindPlugin looks up the plugin for a source on $PATH
+func FindPlugin(name string) (*Plugin, error) {
+	path, err := exec.LookPath(PluginPrefix + name)
+	if err != nil {
+		return nil, err
+	}
+	return NewPlugin(name, path), nil
+}
+
+// Plugins lists the source names of the plugins found on $PATH
+func Plugins() []string {
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), PluginPrefix)
+			if !ok || e.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				if name, ok = strings.CutSuffix(strings.ToLower(name), ".exe"); !ok {
+					continue
+				}
+			} else if info, err := e.Info(); err != nil || info.Mode()&0111 == 0 {
+				continue
+			}
+			if name != "" {
+				seen[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Name returns the display name the plugin reported, or its source name
+// before the first lookup
+func (p *Plugin) Name() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.source != "" {
+		return p.source
+	}
+	return p.name
+}
+
+// SearchTrack returns the plugin's top track match
+func (p *Plugin) SearchTrack(ctx context.Context, query string) (*spotify.Track, error) {
+	return first(p.ListTracks(ctx, query, 1))
+}
+
+// SearchAlbum returns the plugin's top album match
+func (p *Plugin) SearchAlbum(ctx context.Context, query string) (*spotify.Album, error) {
+	return first(p.ListAlbums(ctx, query, 1))
+}
+
+// SearchArtist returns the plugin's top artist match
+func (p *Plugin) SearchArtist(ctx context.Context, query string) (*spotify.Artist, error) {
+	return first(p.ListArtists(ctx, query, 1))
+}
+
+// ListTracks returns up to limit track matches
+func (p *Plugin) ListTracks(ctx context.Context, query string, limit int) ([]spotify.Track, error) {
+	resp, err := p.run(ctx, "track", query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return found(resp.Tracks, limit)
+}
+
+// ListAlbums returns up to limit album matches
+func (p *Plugin) ListAlbums(ctx context.Context, query string, limit int) ([]spotify.Album, error) {
+	resp, err := p.run(ctx, "album", query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return found(resp.Albums, limit)
+}
+
+// ListArtists returns up to limit artist matches
+func (p *Plugin) ListArtists(ctx context.Context, query string, limit int) ([]spotify.Artist, error) {
+	resp, err := p.run(ctx, "artist", query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return found(resp.Artists, limit)
+}
+
+// run sends one request to the plugin and decodes its response
+func (p *Plugin) run(ctx context.Context, itemType, query string, limit int) (*PluginResponse, error) {
+	req, err := json.Marshal(PluginRequest{
+		Version: PluginVersion,
+		Type:    itemType,
+		Query:   query,
+		Limit:   limit,
+		Locale:  p.Locale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s plugin failed: %s", p.name, msg)
+		}
+		return nil, fmt.Errorf("%s plugin failed: %w", p.name, err)
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("%s plugin sent invalid JSON: %w", p.name, err)
+	}
+	if resp.Source != "" {
+		p.mu.Lock()
+		p.source = resp.Source
+		p.mu.Unlock()
+	}
+	if resp.Error != "" || resp.Code != "" {
+		return nil, pluginError(p.name, resp)
+	}
+	return &resp, nil
+}
+
+// pluginError maps a reported error onto the sentinel errors
+func pluginError(name string, resp PluginResponse) error {
+	msg := resp.Error
+	if msg == "" {
+		msg = resp.Code
+	}
+	switch resp.Code {
+	case "not_found":
+		return ErrNotFound
+	case "unauthorized":
+		return fmt.Errorf("%s plugin: %s: %w", name, msg, spotify.ErrUnauthorized)
+	case "rate_limited":
+		return fmt.Errorf("%s plugin: %s: %w", name, msg, spotify.ErrRateLimited)
+	}
+	return fmt.Errorf("%s plugin: %s", name, msg)
+}
+
+// found caps results at limit, failing with ErrNotFound when there are none
+func found[T any](items []T, limit int) ([]T, error) {
+	if len(items) == 0 {
+		return nil, ErrNotFound
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// first returns the first of a list of matches
+func first[T any](items []T, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
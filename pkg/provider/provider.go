@@ -0,0 +1,73 @@
+// Package provider defines the music metadata sources mufetch can query.
+// Every provider returns the spotify package's models so the display code
+// works the same regardless of where the data came from.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// ErrNotFound is returned when a provider has no match for the query. It is
+// the same error as spotify.ErrNotFound so callers can check either
+var ErrNotFound = spotify.ErrNotFound
+
+// Provider looks up the best matching track, album or artist for a query
+type Provider interface {
+	// Name returns the display name used in links and notices
+	Name() string
+	SearchTrack(ctx context.Context, query string) (*spotify.Track, error)
+	SearchAlbum(ctx context.Context, query string) (*spotify.Album, error)
+	SearchArtist(ctx context.Context, query string) (*spotify.Artist, error)
+}
+
+// getJSON fetches reqURL and decodes the JSON response into v
+func getJSON(ctx context.Context, reqURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpclient.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return spotify.StatusError("request failed", resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// ccLicensePattern extracts the type and version from a Creative Commons URL
+var ccLicensePattern = regexp.MustCompile(`creativecommons\.org/(licenses|publicdomain)/([a-z-]+)/([0-9.]+)`)
+
+// licenseFromURL builds a license with a readable name ("CC BY-NC-SA 3.0")
+// from a Creative Commons deed URL
+func licenseFromURL(url string) *spotify.License {
+	if url == "" {
+		return nil
+	}
+
+	license := &spotify.License{Name: url, URL: url}
+	if m := ccLicensePattern.FindStringSubmatch(url); m != nil {
+		switch {
+		case m[1] == "publicdomain" && m[2] == "zero":
+			license.Name = "CC0 " + m[3]
+		case m[1] == "publicdomain":
+			license.Name = "Public Domain"
+		default:
+			license.Name = "CC " + strings.ToUpper(m[2]) + " " + m[3]
+		}
+	}
+	return license
+}
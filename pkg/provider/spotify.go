@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/ashish0kumar/mufetch/pkg/spotify"
+)
+
+// Spotify looks up music through the Spotify Web API
+type Spotify struct {
+	Client *spotify.Client
+}
+
+// NewSpotify creates a Spotify provider with client credentials
+func NewSpotify(clientID, clientSecret string) *Spotify {
+	return &Spotify{Client: spotify.NewClient(clientID, clientSecret)}
+}
+
+// Name returns the provider display name
+func (s *Spotify) Name() string {
+	return "Spotify"
+}
+
+// SearchTrack returns the top track match
+func (s *Spotify) SearchTrack(ctx context.Context, query string) (*spotify.Track, error) {
+	result, err := s.Client.Search(ctx, query, "track")
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Tracks.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return &result.Tracks.Items[0], nil
+}
+
+// SearchAlbum returns full details (tracks, label) of the top album match
+func (s *Spotify) SearchAlbum(ctx context.Context, query string) (*spotify.Album, error) {
+	result, err := s.Client.Search(ctx, query, "album")
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Albums.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return s.Client.GetAlbum(ctx, result.Albums.Items[0].ID)
+}
+
+// SearchArtist returns full details of the top artist match
+func (s *Spotify) SearchArtist(ctx context.Context, query string) (*spotify.Artist, error) {
+	result, err := s.Client.Search(ctx, query, "artist")
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Artists.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return s.Client.GetArtist(ctx, result.Artists.Items[0].ID)
+}
+
+// ListTracks returns up to limit track matches
+func (s *Spotify) ListTracks(ctx context.Context, query string, limit int) ([]spotify.Track, error) {
+	result, err := s.Client.SearchLimit(ctx, query, "track", limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Tracks.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return result.Tracks.Items, nil
+}
+
+// ListAlbums returns up to limit album matches without their tracklists
+func (s *Spotify) ListAlbums(ctx context.Context, query string, limit int) ([]spotify.Album, error) {
+	result, err := s.Client.SearchLimit(ctx, query, "album", limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Albums.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return result.Albums.Items, nil
+}
+
+// ListArtists returns up to limit artist matches
+func (s *Spotify) ListArtists(ctx context.Context, query string, limit int) ([]spotify.Artist, error) {
+	result, err := s.Client.SearchLimit(ctx, query, "artist", limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Artists.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	return result.Artists.Items, nil
+}
@@ -1,22 +1,67 @@
+// Package spotify is a client for the Spotify Web API. Its models are what
+// every mufetch provider returns.
 package spotify
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/ashish0kumar/mufetch/pkg/httpclient"
 )
 
+// DefaultBaseURL is the root of the Spotify Web API
+const DefaultBaseURL = "https://api.spotify.com/v1"
+
+// DefaultAccountsURL is the root of Spotify's OAuth service
+const DefaultAccountsURL = "https://accounts.spotify.com"
+
+// DefaultMarket is the country top tracks and discographies are checked in
+// when Market isn't set
+const DefaultMarket = "US"
+
 // Client represents a Spotify API client with authentication
 type Client struct {
 	ClientID     string
 	ClientSecret string
 	AccessToken  string
 	TokenExpiry  time.Time
+
+	// BaseURL and AccountsURL point the client at another server, e.g. a
+	// mock in tests; empty uses DefaultBaseURL and DefaultAccountsURL
+	BaseURL     string
+	AccountsURL string
+
+	// HTTPClient sends the requests; nil uses httpclient.Client with its
+	// retries and response cache
+	HTTPClient *http.Client
+
+	// RefreshToken renews user access tokens, see NewRefreshingUserClient
+	RefreshToken string
+	OnRefresh    func(*UserToken)
+
+	// Locale asks for names and text in a language, e.g. "ja" or "pt-BR",
+	// where Spotify has translations
+	Locale string
+
+	// Market is the country code top tracks and discographies are relinked
+	// for, e.g. "JP"; empty uses DefaultMarket
+	Market string
+
+	// OnToken, if set, is called with each new client-credentials token so
+	// it can be reused by later runs until it expires
+	OnToken func(accessToken string, expiry time.Time)
+
+	// mu guards the token, so requests can be made from several goroutines
+	mu sync.Mutex
 }
 
 // TokenResponse represents the OAuth token response from Spotify
@@ -50,19 +95,22 @@ type ArtistsResponse struct {
 
 // Track represents a Spotify track with all metadata
 type Track struct {
-	ID               string       `json:"id"`
-	Name             string       `json:"name"`
-	Artists          []Artist     `json:"artists"`
-	Album            Album        `json:"album"`
-	Duration         int          `json:"duration_ms"`
-	Popularity       int          `json:"popularity"`
-	TrackNumber      int          `json:"track_number"`
-	DiscNumber       int          `json:"disc_number"`
-	Explicit         bool         `json:"explicit"`
-	PreviewURL       string       `json:"preview_url"`
-	ExternalURL      ExternalURL  `json:"external_urls"`
-	AvailableMarkets []string     `json:"available_markets"`
-	Restrictions     Restrictions `json:"restrictions"`
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	Artists          []Artist      `json:"artists"`
+	Album            Album         `json:"album"`
+	Duration         int           `json:"duration_ms"`
+	Popularity       int           `json:"popularity"`
+	TrackNumber      int           `json:"track_number"`
+	DiscNumber       int           `json:"disc_number"`
+	Explicit         bool          `json:"explicit"`
+	PreviewURL       string        `json:"preview_url"`
+	ExternalURL      ExternalURL   `json:"external_urls"`
+	ExternalIDs      ExternalIDs   `json:"external_ids"`
+	AvailableMarkets []string      `json:"available_markets"`
+	Restrictions     Restrictions  `json:"restrictions"`
+	Quality          *AudioQuality `json:"quality,omitempty"` // Set by providers exposing lossless streams
+	License          *License      `json:"license,omitempty"` // Set by Creative Commons providers
 }
 
 // Album represents a Spotify album with all metadata
@@ -81,9 +129,12 @@ type Album struct {
 	Label                string       `json:"label"`
 	Copyrights           []Copyright  `json:"copyrights"`
 	ExternalURL          ExternalURL  `json:"external_urls"`
+	ExternalIDs          ExternalIDs  `json:"external_ids"`
 	AvailableMarkets     []string     `json:"available_markets"`
 	Restrictions         Restrictions `json:"restrictions"`
 	Tracks               TracksPage   `json:"tracks"`
+	License              *License     `json:"license,omitempty"`
+	Recording            *Recording   `json:"recording,omitempty"` // Set for live concert recordings
 }
 
 // Artist represents a Spotify artist with all metadata
@@ -105,6 +156,31 @@ type Image struct {
 	Width  int    `json:"width"`
 }
 
+// AudioQuality describes the encoding of a track's audio stream
+type AudioQuality struct {
+	Codec      string `json:"codec,omitempty"`
+	BitDepth   int    `json:"bit_depth,omitempty"`
+	SampleRate int    `json:"sample_rate,omitempty"` // Hz
+	Bitrate    int    `json:"bitrate,omitempty"`     // kbps
+	Channels   int    `json:"channels,omitempty"`
+}
+
+// Recording describes how a live concert recording was made
+type Recording struct {
+	Venue      string `json:"venue,omitempty"`
+	Location   string `json:"location,omitempty"`
+	Source     string `json:"source,omitempty"`  // Microphones and recording chain
+	Lineage    string `json:"lineage,omitempty"` // Transfer and mastering steps
+	Taper      string `json:"taper,omitempty"`
+	Transferer string `json:"transferer,omitempty"`
+}
+
+// ExternalIDs holds industry identifiers for tracks and albums
+type ExternalIDs struct {
+	ISRC string `json:"isrc,omitempty"`
+	UPC  string `json:"upc,omitempty"`
+}
+
 // Followers represents artist follower count
 type Followers struct {
 	Total int `json:"total"`
@@ -113,6 +189,21 @@ type Followers struct {
 // ExternalURL represents external URLs for Spotify entities
 type ExternalURL struct {
 	Spotify string `json:"spotify"`
+	Web     string `json:"web,omitempty"` // Page on a non-Spotify source
+}
+
+// URL returns the entity's page on whichever source it came from
+func (e ExternalURL) URL() string {
+	if e.Spotify != "" {
+		return e.Spotify
+	}
+	return e.Web
+}
+
+// License represents the reuse terms of openly licensed music
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
 }
 
 // Copyright represents album copyright information
@@ -130,6 +221,7 @@ type Restrictions struct {
 type TracksPage struct {
 	Items []Track `json:"items"`
 	Total int     `json:"total"`
+	Next  *string `json:"next"`
 }
 
 // TopTracksResponse represents artist's top tracks response
@@ -146,6 +238,7 @@ type RelatedArtistsResponse struct {
 type ArtistAlbumsResponse struct {
 	Items []Album `json:"items"`
 	Total int     `json:"total"`
+	Next  *string `json:"next"`
 }
 
 // NewClient creates a new Spotify API client with credentials
@@ -156,17 +249,39 @@ func NewClient(clientID, clientSecret string) *Client {
 	}
 }
 
+// NewUserClient creates a client that calls the API with a user access
+// token, needed for endpoints like the current playback. User tokens are
+// valid for an hour.
+func NewUserClient(accessToken string) *Client {
+	return &Client{
+		AccessToken: accessToken,
+		TokenExpiry: time.Now().Add(time.Hour),
+	}
+}
+
+// Authenticate fetches an access token up front, which checks that the
+// credentials work
+func (c *Client) Authenticate(ctx context.Context) error {
+	return c.authenticate(ctx)
+}
+
 // authenticate obtains or refreshes the access token for API calls
-func (c *Client) authenticate() error {
+func (c *Client) authenticate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if time.Now().Before(c.TokenExpiry) {
 		return nil // Token still valid
 	}
+	if c.RefreshToken != "" {
+		return c.refreshUser(ctx)
+	}
 
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 
 	// Create a new HTTP request for token endpoint
-	req, err := http.NewRequest("POST", "https://accounts.spotify.com/api/token", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL(), strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -176,15 +291,19 @@ func (c *Client) authenticate() error {
 	req.Header.Set("Authorization", "Basic "+auth)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("authentication failed: %s", resp.Status)
+		// The token endpoint rejects bad credentials with 400 invalid_client
+		code := resp.StatusCode
+		if code == http.StatusBadRequest {
+			code = http.StatusUnauthorized
+		}
+		return &statusError{action: "authentication failed", status: resp.Status, code: code}
 	}
 
 	var tokenResp TokenResponse
@@ -195,32 +314,87 @@ func (c *Client) authenticate() error {
 	// Store token and expiry time
 	c.AccessToken = tokenResp.AccessToken
 	c.TokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	if c.OnToken != nil {
+		c.OnToken(c.AccessToken, c.TokenExpiry)
+	}
 
 	return nil
 }
 
-// Search performs a search query for tracks, albums, or artists
-func (c *Client) Search(query, searchType string) (*SearchResponse, error) {
-	if err := c.authenticate(); err != nil {
+// apiURL returns the URL of an API path like "/albums/{id}"
+func (c *Client) apiURL(path string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return strings.TrimRight(base, "/") + path
+}
+
+// accountsURL returns the root of the OAuth service
+func (c *Client) accountsURL() string {
+	if c.AccountsURL == "" {
+		return DefaultAccountsURL
+	}
+	return strings.TrimRight(c.AccountsURL, "/")
+}
+
+// httpClient returns the client requests are sent with
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return httpclient.Client
+	}
+	return c.HTTPClient
+}
+
+// market returns the country to ask for market-specific results
+func (c *Client) market() string {
+	if c.Market == "" {
+		return DefaultMarket
+	}
+	return c.Market
+}
+
+// tokenURL returns the OAuth token endpoint
+func (c *Client) tokenURL() string {
+	return c.accountsURL() + "/api/token"
+}
+
+// setHeaders adds the access token and preferred language to an API request
+func (c *Client) setHeaders(req *http.Request) {
+	c.mu.Lock()
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	c.mu.Unlock()
+	if c.Locale != "" {
+		req.Header.Set("Accept-Language", c.Locale)
+	}
+}
+
+// Search performs a search query for the top track, album, or artist
+func (c *Client) Search(ctx context.Context, query, searchType string) (*SearchResponse, error) {
+	return c.SearchLimit(ctx, query, searchType, 1)
+}
+
+// SearchLimit searches for up to limit tracks, albums, or artists
+func (c *Client) SearchLimit(ctx context.Context, query, searchType string, limit int) (*SearchResponse, error) {
+	if err := c.authenticate(ctx); err != nil {
 		return nil, err
 	}
 
 	params := url.Values{}
 	params.Set("q", query)
 	params.Set("type", searchType)
-	params.Set("limit", "1")
+	params.Set("limit", strconv.Itoa(limit))
 
-	reqURL := "https://api.spotify.com/v1/search?" + params.Encode()
+	reqURL := c.apiURL("/search?" + params.Encode())
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	c.setHeaders(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +402,7 @@ func (c *Client) Search(query, searchType string) (*SearchResponse, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("search failed: %s - %s", resp.Status, string(body))
+		return nil, fmt.Errorf("%w - %s", StatusError("search failed", resp), string(body))
 	}
 
 	var searchResp SearchResponse
@@ -239,30 +413,62 @@ func (c *Client) Search(query, searchType string) (*SearchResponse, error) {
 	return &searchResp, nil
 }
 
+// GetTrack retrieves a track by ID
+func (c *Client) GetTrack(ctx context.Context, trackID string) (*Track, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	reqURL := c.apiURL(fmt.Sprintf("/tracks/%s", trackID))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError("failed to get track", resp)
+	}
+
+	var track Track
+	if err := json.NewDecoder(resp.Body).Decode(&track); err != nil {
+		return nil, err
+	}
+
+	return &track, nil
+}
+
 // GetAlbum retrieves detailed album information by ID
-func (c *Client) GetAlbum(albumID string) (*Album, error) {
-	if err := c.authenticate(); err != nil {
+func (c *Client) GetAlbum(ctx context.Context, albumID string) (*Album, error) {
+	if err := c.authenticate(ctx); err != nil {
 		return nil, err
 	}
 
-	reqURL := fmt.Sprintf("https://api.spotify.com/v1/albums/%s", albumID)
+	reqURL := c.apiURL(fmt.Sprintf("/albums/%s", albumID))
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	c.setHeaders(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get album: %s", resp.Status)
+		return nil, StatusError("failed to get album", resp)
 	}
 
 	var album Album
@@ -270,33 +476,103 @@ func (c *Client) GetAlbum(albumID string) (*Album, error) {
 		return nil, err
 	}
 
+	if err := c.completeTracks(ctx, &album); err != nil {
+		return nil, err
+	}
+
 	return &album, nil
 }
 
+// GetAlbums retrieves several albums by ID in as few requests as possible.
+// IDs Spotify doesn't know come back as nil.
+func (c *Client) GetAlbums(ctx context.Context, albumIDs []string) ([]*Album, error) {
+	albums, err := getSeveral[Album](ctx, c, "albums", albumIDs, 20)
+	if err != nil {
+		return nil, err
+	}
+	for _, album := range albums {
+		if album == nil {
+			continue
+		}
+		if err := c.completeTracks(ctx, album); err != nil {
+			return nil, err
+		}
+	}
+	return albums, nil
+}
+
+// completeTracks fetches the rest of an album's tracklist. Albums come with
+// their first 50 tracks; box sets and long compilations need the rest
+// fetched page by page.
+func (c *Client) completeTracks(ctx context.Context, album *Album) error {
+	for album.Tracks.Next != nil && len(album.Tracks.Items) < album.Tracks.Total {
+		page, err := c.getTracksPage(ctx, *album.Tracks.Next)
+		if err != nil {
+			return err
+		}
+		if len(page.Items) == 0 {
+			break
+		}
+		album.Tracks.Items = append(album.Tracks.Items, page.Items...)
+		album.Tracks.Next = page.Next
+	}
+	return nil
+}
+
+// getTracksPage follows a tracks paging link
+func (c *Client) getTracksPage(ctx context.Context, reqURL string) (*TracksPage, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError("failed to get album tracks", resp)
+	}
+
+	var page TracksPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
 // GetArtist retrieves detailed artist information by ID
-func (c *Client) GetArtist(artistID string) (*Artist, error) {
-	if err := c.authenticate(); err != nil {
+func (c *Client) GetArtist(ctx context.Context, artistID string) (*Artist, error) {
+	if err := c.authenticate(ctx); err != nil {
 		return nil, err
 	}
 
-	reqURL := fmt.Sprintf("https://api.spotify.com/v1/artists/%s", artistID)
+	reqURL := c.apiURL(fmt.Sprintf("/artists/%s", artistID))
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	c.setHeaders(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get artist: %s", resp.Status)
+		return nil, StatusError("failed to get artist", resp)
 	}
 
 	var artist Artist
@@ -307,30 +583,85 @@ func (c *Client) GetArtist(artistID string) (*Artist, error) {
 	return &artist, nil
 }
 
+// GetArtists retrieves several artists by ID in as few requests as
+// possible. IDs Spotify doesn't know come back as nil.
+func (c *Client) GetArtists(ctx context.Context, artistIDs []string) ([]*Artist, error) {
+	return getSeveral[Artist](ctx, c, "artists", artistIDs, 50)
+}
+
+// GetTracks retrieves several tracks by ID in as few requests as possible.
+// IDs Spotify doesn't know come back as nil.
+func (c *Client) GetTracks(ctx context.Context, trackIDs []string) ([]*Track, error) {
+	return getSeveral[Track](ctx, c, "tracks", trackIDs, 50)
+}
+
+// getSeveral fetches ids from the /v1/<kind>?ids= endpoint, batchSize at a
+// time (Spotify's cap for that kind), keeping the order of ids
+func getSeveral[T any](ctx context.Context, c *Client, kind string, ids []string, batchSize int) ([]*T, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	items := make([]*T, 0, len(ids))
+	for start := 0; start < len(ids); start += batchSize {
+		batch := ids[start:min(start+batchSize, len(ids))]
+		reqURL := c.apiURL(fmt.Sprintf("/%s?ids=%s", kind, strings.Join(batch, ",")))
+
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		c.setHeaders(req)
+
+		resp, err := c.httpClient().Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, StatusError("failed to get "+kind, resp)
+		}
+
+		var page map[string][]*T
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(page[kind]) != len(batch) {
+			return nil, fmt.Errorf("failed to get %s: expected %d, got %d", kind, len(batch), len(page[kind]))
+		}
+		items = append(items, page[kind]...)
+	}
+
+	return items, nil
+}
+
 // GetArtistTopTracks retrieves an artist's most popular tracks
-func (c *Client) GetArtistTopTracks(artistID string) (*TopTracksResponse, error) {
-	if err := c.authenticate(); err != nil {
+func (c *Client) GetArtistTopTracks(ctx context.Context, artistID string) (*TopTracksResponse, error) {
+	if err := c.authenticate(ctx); err != nil {
 		return nil, err
 	}
 
-	reqURL := fmt.Sprintf("https://api.spotify.com/v1/artists/%s/top-tracks?market=US", artistID)
+	reqURL := c.apiURL(fmt.Sprintf("/artists/%s/top-tracks?market=%s", artistID, url.QueryEscape(c.market())))
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	c.setHeaders(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get top tracks: %s", resp.Status)
+		return nil, StatusError("failed to get top tracks", resp)
 	}
 
 	var topTracks TopTracksResponse
@@ -341,35 +672,90 @@ func (c *Client) GetArtistTopTracks(artistID string) (*TopTracksResponse, error)
 	return &topTracks, nil
 }
 
+// GetRelatedArtists retrieves artists similar to an artist. Spotify has
+// withdrawn this endpoint for newer apps, which get ErrNotFound or
+// ErrUnauthorized.
+func (c *Client) GetRelatedArtists(ctx context.Context, artistID string) (*RelatedArtistsResponse, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	reqURL := c.apiURL(fmt.Sprintf("/artists/%s/related-artists", artistID))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError("failed to get related artists", resp)
+	}
+
+	var related RelatedArtistsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&related); err != nil {
+		return nil, err
+	}
+
+	return &related, nil
+}
+
 // GetArtistAlbums retrieves an artist's albums by type (album, single, etc.)
-func (c *Client) GetArtistAlbums(artistID string, includeGroups string) (*ArtistAlbumsResponse, error) {
-	if err := c.authenticate(); err != nil {
+func (c *Client) GetArtistAlbums(ctx context.Context, artistID string, includeGroups string) (*ArtistAlbumsResponse, error) {
+	return c.getArtistAlbumsPage(ctx, artistID, includeGroups, 0)
+}
+
+// GetAllArtistAlbums pages through every album of the given types
+func (c *Client) GetAllArtistAlbums(ctx context.Context, artistID string, includeGroups string) ([]Album, error) {
+	var albums []Album
+	for {
+		page, err := c.getArtistAlbumsPage(ctx, artistID, includeGroups, len(albums))
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, page.Items...)
+		if page.Next == nil || len(page.Items) == 0 {
+			return albums, nil
+		}
+	}
+}
+
+// getArtistAlbumsPage retrieves up to 50 albums starting at offset
+func (c *Client) getArtistAlbumsPage(ctx context.Context, artistID string, includeGroups string, offset int) (*ArtistAlbumsResponse, error) {
+	if err := c.authenticate(ctx); err != nil {
 		return nil, err
 	}
 
 	params := url.Values{}
 	params.Set("include_groups", includeGroups)
 	params.Set("limit", "50")
-	params.Set("market", "US")
+	params.Set("offset", strconv.Itoa(offset))
+	params.Set("market", c.market())
 
-	reqURL := fmt.Sprintf("https://api.spotify.com/v1/artists/%s/albums?%s", artistID, params.Encode())
+	reqURL := c.apiURL(fmt.Sprintf("/artists/%s/albums?%s", artistID, params.Encode()))
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	c.setHeaders(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get artist albums: %s", resp.Status)
+		return nil, StatusError("failed to get artist albums", resp)
 	}
 
 	var albums ArtistAlbumsResponse
@@ -0,0 +1,278 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockAPI serves the fixtures in testdata in place of the Web API and the
+// accounts service, counting the requests it gets
+type mockAPI struct {
+	*httptest.Server
+	t *testing.T
+
+	mu       sync.Mutex
+	requests map[string]int        // By path
+	queries  map[string]url.Values // Last query string by path
+}
+
+// newMockAPI starts a mock server and a client pointed at it
+func newMockAPI(t *testing.T) (*mockAPI, *Client) {
+	t.Helper()
+	m := &mockAPI{t: t, requests: make(map[string]int), queries: make(map[string]url.Values)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/token", m.token)
+	mux.HandleFunc("GET /v1/search", m.fixture("search_track.json"))
+	mux.HandleFunc("GET /v1/albums/3GhhbMDnwxYtpSpdIP2jzc", m.fixture("album.json"))
+	mux.HandleFunc("GET /v1/albums/3GhhbMDnwxYtpSpdIP2jzc/tracks", m.fixture("album_tracks.json"))
+	mux.HandleFunc("GET /v1/artists/7w29UYBi0qsHi5RTcv3lmA", m.fixture("artist.json"))
+	mux.HandleFunc("GET /v1/artists/7w29UYBi0qsHi5RTcv3lmA/top-tracks", m.fixture("top_tracks.json"))
+	mux.HandleFunc("GET /v1/tracks/unauthorized", m.status(http.StatusUnauthorized, "error_401.json"))
+	mux.HandleFunc("GET /v1/tracks/missing", m.status(http.StatusNotFound, "error_404.json"))
+	mux.HandleFunc("GET /v1/tracks/throttled", m.status(http.StatusTooManyRequests, "error_429.json"))
+	m.Server = httptest.NewServer(m.count(mux))
+	t.Cleanup(m.Close)
+
+	return m, &Client{
+		ClientID:     "test-id",
+		ClientSecret: "test-secret",
+		BaseURL:      m.URL + "/v1",
+		AccountsURL:  m.URL,
+		HTTPClient:   m.Client(),
+	}
+}
+
+// count records each request before handling it
+func (m *mockAPI) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.requests[r.URL.Path]++
+		m.queries[r.URL.Path] = r.URL.Query()
+		m.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestCount returns how many requests were made for path
+func (m *mockAPI) requestCount(path string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests[path]
+}
+
+// lastQuery returns the query string of the last request for path
+func (m *mockAPI) lastQuery(path string) url.Values {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.queries[path]
+}
+
+// token answers the client-credentials, authorization-code and refresh
+// grants
+func (m *mockAPI) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+		if id, secret, ok := r.BasicAuth(); !ok || id != "test-id" || secret != "test-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_client"}`))
+			return
+		}
+		m.fixture("token.json")(w, r)
+	case "authorization_code":
+		if r.PostForm.Get("code") != "test-code" || r.PostForm.Get("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+		m.fixture("user_token.json")(w, r)
+	default:
+		http.Error(w, "unsupported grant", http.StatusBadRequest)
+	}
+}
+
+// fixture serves a testdata file, pointing its links at the mock server
+func (m *mockAPI) fixture(name string) http.HandlerFunc {
+	return m.status(http.StatusOK, name)
+}
+
+// status serves a testdata file with the given status code
+func (m *mockAPI) status(code int, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if code == http.StatusOK && r.URL.Path != "/api/token" && r.Header.Get("Authorization") != "Bearer test-access-token" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			m.t.Errorf("reading fixture: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if code == http.StatusTooManyRequests {
+			// Longer than the client waits out, so the 429 is returned
+			w.Header().Set("Retry-After", "3600")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(strings.ReplaceAll(string(data), "{{server}}", m.URL)))
+	}
+}
+
+func TestAuthenticateReusesToken(t *testing.T) {
+	m, c := newMockAPI(t)
+	ctx := context.Background()
+
+	var saved string
+	c.OnToken = func(token string, _ time.Time) { saved = token }
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetArtist(ctx, "7w29UYBi0qsHi5RTcv3lmA"); err != nil {
+			t.Fatalf("GetArtist: %v", err)
+		}
+	}
+	if n := m.requestCount("/api/token"); n != 1 {
+		t.Errorf("token requested %d times, want 1", n)
+	}
+	if saved != "test-access-token" {
+		t.Errorf("OnToken got %q, want test-access-token", saved)
+	}
+}
+
+func TestAuthenticateBadCredentials(t *testing.T) {
+	_, c := newMockAPI(t)
+	c.ClientSecret = "wrong"
+
+	_, err := c.Search(context.Background(), "joga", "track")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("got %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	_, c := newMockAPI(t)
+
+	resp, err := c.Search(context.Background(), "joga", "track")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.Tracks.Items) != 1 {
+		t.Fatalf("got %d tracks, want 1", len(resp.Tracks.Items))
+	}
+	track := resp.Tracks.Items[0]
+	if track.Name != "Jóga" || track.Album.Name != "Homogenic" || track.Artists[0].Name != "Björk" {
+		t.Errorf("got %q from %q by %q", track.Name, track.Album.Name, track.Artists[0].Name)
+	}
+	if track.ExternalIDs.ISRC != "GBAAN9700013" {
+		t.Errorf("got ISRC %q", track.ExternalIDs.ISRC)
+	}
+}
+
+func TestGetAlbumFollowsTrackPages(t *testing.T) {
+	m, c := newMockAPI(t)
+
+	album, err := c.GetAlbum(context.Background(), "3GhhbMDnwxYtpSpdIP2jzc")
+	if err != nil {
+		t.Fatalf("GetAlbum: %v", err)
+	}
+	var names []string
+	for _, track := range album.Tracks.Items {
+		names = append(names, track.Name)
+	}
+	if got := strings.Join(names, ", "); got != "Hunter, Jóga, Unravel" {
+		t.Errorf("got tracks %s", got)
+	}
+	if album.Tracks.Next != nil {
+		t.Errorf("next page left at %s", *album.Tracks.Next)
+	}
+	if n := m.requestCount("/v1/albums/3GhhbMDnwxYtpSpdIP2jzc/tracks"); n != 1 {
+		t.Errorf("tracks page requested %d times, want 1", n)
+	}
+	if album.ExternalIDs.UPC != "5016958036327" || album.Label != "One Little Independent" {
+		t.Errorf("got UPC %q, label %q", album.ExternalIDs.UPC, album.Label)
+	}
+}
+
+func TestGetArtistTopTracks(t *testing.T) {
+	m, c := newMockAPI(t)
+	c.Market = "IS"
+	ctx := context.Background()
+
+	artist, err := c.GetArtist(ctx, "7w29UYBi0qsHi5RTcv3lmA")
+	if err != nil {
+		t.Fatalf("GetArtist: %v", err)
+	}
+	if artist.Followers.Total != 2810000 || len(artist.Genres) != 2 {
+		t.Errorf("got %d followers, genres %v", artist.Followers.Total, artist.Genres)
+	}
+
+	top, err := c.GetArtistTopTracks(ctx, artist.ID)
+	if err != nil {
+		t.Fatalf("GetArtistTopTracks: %v", err)
+	}
+	if len(top.Tracks) != 3 || top.Tracks[0].Name != "Jóga" {
+		t.Errorf("got %d top tracks", len(top.Tracks))
+	}
+	if market := m.lastQuery("/v1/artists/7w29UYBi0qsHi5RTcv3lmA/top-tracks").Get("market"); market != "IS" {
+		t.Errorf("top tracks asked for market %q, want IS", market)
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	tests := []struct {
+		id   string
+		want error
+	}{
+		{"unauthorized", ErrUnauthorized},
+		{"missing", ErrNotFound},
+		{"throttled", ErrRateLimited},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, c := newMockAPI(t)
+			_, err := c.GetTrack(context.Background(), tt.id)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+			for _, other := range []error{ErrUnauthorized, ErrNotFound, ErrRateLimited} {
+				if other != tt.want && errors.Is(err, other) {
+					t.Errorf("%v also matches %v", err, other)
+				}
+			}
+		})
+	}
+}
+
+func TestExchangeCode(t *testing.T) {
+	m, c := newMockAPI(t)
+	ctx := context.Background()
+
+	authURL := c.AuthorizeURL("http://127.0.0.1:8888/callback", Challenge("verifier"), "state")
+	if !strings.HasPrefix(authURL, m.URL+"/authorize?") {
+		t.Errorf("authorize URL %s isn't on the mock server", authURL)
+	}
+
+	token, err := c.ExchangeCode(ctx, "test-code", "http://127.0.0.1:8888/callback", "verifier")
+	if err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+	if token.AccessToken != "test-user-token" || token.RefreshToken != "test-refresh-token" {
+		t.Errorf("got %+v", token)
+	}
+
+	if _, err := c.ExchangeCode(ctx, "stale-code", "http://127.0.0.1:8888/callback", "verifier"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("stale code: got %v, want ErrUnauthorized", err)
+	}
+}
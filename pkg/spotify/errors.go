@@ -0,0 +1,61 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+var (
+	// ErrNotFound is returned when nothing matches a search or ID
+	ErrNotFound = errors.New("no results found")
+
+	// ErrUnauthorized is returned when credentials are missing, invalid, or
+	// lack the required scope
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrRateLimited is returned when the API asks the client to slow down
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// statusError describes a failed HTTP response and matches the sentinel
+// error for its status code with errors.Is
+type statusError struct {
+	action string
+	status string
+	code   int
+}
+
+// Error returns the failed action and HTTP status
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.action, e.status)
+}
+
+// Is maps the HTTP status code onto the sentinel errors
+func (e *statusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.code == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.code == http.StatusUnauthorized || e.code == http.StatusForbidden
+	case ErrRateLimited:
+		return e.code == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// StatusError builds the error for a non-OK response, e.g.
+// StatusError("failed to get album", resp)
+func StatusError(action string, resp *http.Response) error {
+	status := resp.Status
+	if after := resp.Header.Get("Retry-After"); resp.StatusCode == http.StatusTooManyRequests && after != "" {
+		// Only waits too long to sit out get here. Retry-After is either
+		// seconds or a date.
+		if _, err := strconv.Atoi(after); err == nil {
+			after += "s"
+		}
+		status += " (try again after " + after + ")"
+	}
+	return &statusError{action: action, status: status, code: resp.StatusCode}
+}
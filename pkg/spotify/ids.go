@@ -0,0 +1,52 @@
+package spotify
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// idPattern matches Spotify's 22-character base62 IDs
+var idPattern = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+
+// ParseID extracts the entity type and ID from a Spotify URI
+// (spotify:track:ID), an open.spotify.com URL, or a bare ID. The type is
+// empty for bare IDs.
+func ParseID(s string) (entityType, id string, err error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case strings.HasPrefix(s, "spotify:"):
+		parts := strings.Split(s, ":")
+		if len(parts) != 3 {
+			return "", "", fmt.Errorf("invalid Spotify URI: %s", s)
+		}
+		entityType, id = parts[1], parts[2]
+	case strings.Contains(s, "open.spotify.com/"):
+		if !strings.Contains(s, "://") {
+			s = "https://" + s
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid Spotify URL: %w", err)
+		}
+
+		// Localized links carry a prefix like /intl-de/track/ID
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) > 0 && strings.HasPrefix(parts[0], "intl-") {
+			parts = parts[1:]
+		}
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid Spotify URL: %s", s)
+		}
+		entityType, id = parts[0], parts[1]
+	default:
+		id = s
+	}
+
+	if !idPattern.MatchString(id) {
+		return "", "", fmt.Errorf("invalid Spotify ID: %s", id)
+	}
+	return entityType, id, nil
+}
@@ -0,0 +1,151 @@
+package spotify
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UserScopes are the permissions requested by `mufetch auth --user`,
+// covering playback, the library, top items and the queue
+var UserScopes = []string{
+	"user-read-currently-playing",
+	"user-read-playback-state",
+	"user-read-recently-played",
+	"user-top-read",
+	"user-library-read",
+	"user-follow-read",
+}
+
+// UserToken is a user access token with the refresh token that renews it
+type UserToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+}
+
+// NewVerifier creates a random PKCE code verifier
+func NewVerifier() (string, error) {
+	b := make([]byte, 48)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge derives the S256 PKCE code challenge from a verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthorizeURL returns the page where the user grants the client's app
+// access to their account
+func (c *Client) AuthorizeURL(redirectURI, challenge, state string) string {
+	params := url.Values{}
+	params.Set("client_id", c.ClientID)
+	params.Set("response_type", "code")
+	params.Set("redirect_uri", redirectURI)
+	params.Set("code_challenge_method", "S256")
+	params.Set("code_challenge", challenge)
+	params.Set("state", state)
+	params.Set("scope", strings.Join(UserScopes, " "))
+	return c.accountsURL() + "/authorize?" + params.Encode()
+}
+
+// ExchangeCode trades the code from the authorization redirect for a user
+// token
+func (c *Client) ExchangeCode(ctx context.Context, code, redirectURI, verifier string) (*UserToken, error) {
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("code", code)
+	data.Set("redirect_uri", redirectURI)
+	data.Set("client_id", c.ClientID)
+	data.Set("code_verifier", verifier)
+	return c.requestUserToken(ctx, data)
+}
+
+// RefreshUserToken gets a fresh access token for a refresh token. Spotify
+// may rotate the refresh token, so callers should keep the returned one.
+func (c *Client) RefreshUserToken(ctx context.Context, refreshToken string) (*UserToken, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", refreshToken)
+	data.Set("client_id", c.ClientID)
+
+	token, err := c.requestUserToken(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+// requestUserToken posts a grant to the token endpoint
+func (c *Client) requestUserToken(ctx context.Context, data url.Values) (*UserToken, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Expired or revoked grants come back as 400 invalid_grant
+		code := resp.StatusCode
+		if code == http.StatusBadRequest {
+			code = http.StatusUnauthorized
+		}
+		return nil, &statusError{action: "user authorization failed", status: resp.Status, code: code}
+	}
+
+	var token UserToken
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// NewRefreshingUserClient creates a client that calls the API as the user,
+// renewing its access token with the refresh token from `auth --user`.
+// onRefresh, if set, is called when Spotify rotates the refresh token so the
+// new one can be saved.
+func NewRefreshingUserClient(clientID, refreshToken string, onRefresh func(*UserToken)) *Client {
+	return &Client{
+		ClientID:     clientID,
+		RefreshToken: refreshToken,
+		OnRefresh:    onRefresh,
+	}
+}
+
+// refreshUser renews the user access token
+func (c *Client) refreshUser(ctx context.Context) error {
+	token, err := c.RefreshUserToken(ctx, c.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	c.AccessToken = token.AccessToken
+	c.TokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	if token.RefreshToken != c.RefreshToken {
+		c.RefreshToken = token.RefreshToken
+		if c.OnRefresh != nil {
+			c.OnRefresh(token)
+		}
+	}
+	return nil
+}
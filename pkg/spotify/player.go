@@ -0,0 +1,226 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Episode represents a podcast episode
+type Episode struct {
+	ID                   string      `json:"id"`
+	Name                 string      `json:"name"`
+	Description          string      `json:"description"`
+	Duration             int         `json:"duration_ms"`
+	Explicit             bool        `json:"explicit"`
+	ReleaseDate          string      `json:"release_date"`
+	ReleaseDatePrecision string      `json:"release_date_precision"`
+	Images               []Image     `json:"images"`
+	ExternalURL          ExternalURL `json:"external_urls"`
+	Show                 Show        `json:"show"`
+}
+
+// Show represents the podcast an episode belongs to
+type Show struct {
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	Publisher     string      `json:"publisher"`
+	Description   string      `json:"description"`
+	Images        []Image     `json:"images"`
+	TotalEpisodes int         `json:"total_episodes"`
+	ExternalURL   ExternalURL `json:"external_urls"`
+}
+
+// CurrentlyPlaying represents the user's current playback. Exactly one of
+// Track or Episode is set depending on the item type.
+type CurrentlyPlaying struct {
+	Timestamp int64    `json:"timestamp"`
+	Progress  int      `json:"progress_ms"`
+	IsPlaying bool     `json:"is_playing"`
+	ItemType  string   `json:"currently_playing_type"`
+	Track     *Track   `json:"-"`
+	Episode   *Episode `json:"-"`
+}
+
+// UnmarshalJSON decodes the playback item as a track or an episode based on
+// currently_playing_type, falling back to the item's own type field
+func (cp *CurrentlyPlaying) UnmarshalJSON(data []byte) error {
+	type plain CurrentlyPlaying
+	var raw struct {
+		plain
+		Item json.RawMessage `json:"item"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*cp = CurrentlyPlaying(raw.plain)
+
+	if len(raw.Item) == 0 || string(raw.Item) == "null" {
+		return nil
+	}
+
+	itemType := cp.ItemType
+	if itemType != "track" && itemType != "episode" {
+		var probe struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw.Item, &probe); err != nil {
+			return err
+		}
+		itemType = probe.Type
+	}
+
+	switch itemType {
+	case "track":
+		cp.Track = &Track{}
+		return json.Unmarshal(raw.Item, cp.Track)
+	case "episode":
+		cp.Episode = &Episode{}
+		return json.Unmarshal(raw.Item, cp.Episode)
+	}
+	return nil
+}
+
+// GetCurrentlyPlaying retrieves the user's current playback, including
+// podcast episodes. It returns nil without an error when nothing is playing.
+// This endpoint requires a user access token.
+func (c *Client) GetCurrentlyPlaying(ctx context.Context) (*CurrentlyPlaying, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	reqURL := c.apiURL("/me/player/currently-playing?additional_types=track,episode")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError("failed to get currently playing", resp)
+	}
+
+	var playing CurrentlyPlaying
+	if err := json.NewDecoder(resp.Body).Decode(&playing); err != nil {
+		return nil, err
+	}
+
+	return &playing, nil
+}
+
+// PlayHistory represents a single recently played track
+type PlayHistory struct {
+	Track    Track     `json:"track"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+// RecentlyPlayedResponse represents the user's recently played tracks
+type RecentlyPlayedResponse struct {
+	Items []PlayHistory `json:"items"`
+}
+
+// GetRecentlyPlayed retrieves up to limit of the user's most recently played
+// tracks. This endpoint requires a user access token.
+func (c *Client) GetRecentlyPlayed(ctx context.Context, limit int) (*RecentlyPlayedResponse, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	reqURL := c.apiURL(fmt.Sprintf("/me/player/recently-played?limit=%d", limit))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError("failed to get recently played", resp)
+	}
+
+	var recent RecentlyPlayedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&recent); err != nil {
+		return nil, err
+	}
+
+	return &recent, nil
+}
+
+// TopTracksPage represents a page of the user's top tracks
+type TopTracksPage struct {
+	Items []Track `json:"items"`
+}
+
+// TopArtistsPage represents a page of the user's top artists
+type TopArtistsPage struct {
+	Items []Artist `json:"items"`
+}
+
+// GetTopTracks retrieves the user's most played tracks over timeRange
+// (short_term, medium_term or long_term). This endpoint requires a user
+// access token.
+func (c *Client) GetTopTracks(ctx context.Context, timeRange string, limit int) (*TopTracksPage, error) {
+	var page TopTracksPage
+	if err := c.getTop(ctx, "tracks", timeRange, limit, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetTopArtists retrieves the user's most played artists over timeRange.
+// This endpoint requires a user access token.
+func (c *Client) GetTopArtists(ctx context.Context, timeRange string, limit int) (*TopArtistsPage, error) {
+	var page TopArtistsPage
+	if err := c.getTop(ctx, "artists", timeRange, limit, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// getTop fetches the user's top items of itemType into v
+func (c *Client) getTop(ctx context.Context, itemType, timeRange string, limit int, v interface{}) error {
+	if err := c.authenticate(ctx); err != nil {
+		return err
+	}
+
+	reqURL := c.apiURL(fmt.Sprintf("/me/top/%s?time_range=%s&limit=%d", itemType, timeRange, limit))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return StatusError("failed to get top "+itemType, resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}